@@ -2,18 +2,39 @@ package main
 
 import (
 	"context"
+	"errors"
+	"expvar"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/joho/godotenv"
 	"github.com/theNixagen/linker/internal/api"
+	"github.com/theNixagen/linker/internal/repositories/audit_repository"
 	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/click_repository"
+	"github.com/theNixagen/linker/internal/repositories/collaborator_repository"
+	"github.com/theNixagen/linker/internal/repositories/custom_domain_repository"
 	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/reference_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+	"github.com/theNixagen/linker/internal/repositories/storage_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+	"github.com/theNixagen/linker/internal/repositories/upload_repository"
 	"github.com/theNixagen/linker/internal/repositories/user_repository"
 	"github.com/theNixagen/linker/internal/services"
 )
@@ -30,7 +51,8 @@ func main() {
 		log.Println("Aviso: arquivo .env não encontrado, usando variáveis de ambiente do sistema")
 	}
 
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	pool, err := pgxpool.New(ctx, fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s sslmode=disable",
 		os.Getenv("LINKER_DATABASE_USER"),
@@ -46,25 +68,109 @@ func main() {
 	r := chi.NewMux()
 	jwtSecret := os.Getenv("JWT_SECRET")
 	refreshSecret := os.Getenv("REFRESH_SECRET")
+	unlockSecret := os.Getenv("UNLOCK_SECRET")
+	if unlockSecret == "" {
+		log.Fatal("UNLOCK_SECRET is required to sign link unlock tokens")
+	}
 	bucket_name := os.Getenv("BUCKET")
 	minio_url := os.Getenv("MINIO_URL")
 	minio_user := os.Getenv("MINIO_USER")
 	minio_passwd := os.Getenv("MINIO_PASSWORD")
 	redis_addr := os.Getenv("REDIS_ADDR")
-	file_service := services.NewFileService(bucket_name, minio_url, minio_user, minio_passwd)
-	file_service.CreateBucketIfNotExists(ctx)
+	public_url := strings.TrimSuffix(os.Getenv("PUBLIC_URL"), "/")
+	revisionLimit := services.DefaultRevisionLimit
+	if v := os.Getenv("PROFILE_REVISION_LIMIT"); v != "" {
+		revisionLimit, err = strconv.Atoi(v)
+		if err != nil || revisionLimit <= 0 {
+			log.Fatalf("PROFILE_REVISION_LIMIT must be a positive number, got %q", v)
+		}
+	}
+	gcGrace := services.DefaultGCGrace
+	if v := os.Getenv("STORAGE_GC_GRACE"); v != "" {
+		gcGrace, err = time.ParseDuration(v)
+		if err != nil || gcGrace < 0 {
+			log.Fatalf("STORAGE_GC_GRACE must be a duration such as 24h, got %q", v)
+		}
+	}
+	quarantineRetention := services.DefaultQuarantineRetention
+	if v := os.Getenv("QUARANTINE_RETENTION"); v != "" {
+		quarantineRetention, err = time.ParseDuration(v)
+		if err != nil || quarantineRetention < 0 {
+			log.Fatalf("QUARANTINE_RETENTION must be a duration such as 720h, got %q", v)
+		}
+	}
+	gcDryRun, _ := strconv.ParseBool(os.Getenv("STORAGE_GC_DRY_RUN"))
+	maxUploadSize, _ := strconv.ParseInt(os.Getenv("MAX_UPLOAD_SIZE"), 10, 64)
+	storageQuota, _ := strconv.ParseInt(os.Getenv("STORAGE_QUOTA"), 10, 64)
+	storage := newObjectStorage(ctx, os.Getenv("STORAGE_DRIVER"), bucket_name, minio_url, minio_user, minio_passwd, public_url)
+	file_service := services.NewFileService(storage, maxUploadSize, storageQuota)
 	UsersRepository := user_repository.NewDbUserRepository(pool)
+	pageRepository := page_repository.NewDbPageRepository(pool)
 	linksRepository := links_repository.NewDbLinksRepository(pool)
 	redisRepostory := cache_repository.NewRedisCacheRepository(redis_addr)
+	themeRepository := theme_repository.NewDbThemeRepository(pool)
+	clickRepository := click_repository.NewDbClickRepository(pool)
+	customDomainRepository := custom_domain_repository.NewDbCustomDomainRepository(pool)
+	publishedProfileRepository := published_profile_repository.NewDbPublishedProfileRepository(pool)
+	revisionRepository := revision_repository.NewDbRevisionRepository(pool)
+	collaboratorRepository := collaborator_repository.NewDbCollaboratorRepository(pool)
+	auditRepository := audit_repository.NewDbAuditRepository(pool)
+	uploadRepository := upload_repository.NewDbUploadRepository(pool)
+	referenceRepository := reference_repository.NewDbReferenceRepository(pool)
+	mediaRepository := media_repository.NewDbMediaRepository(pool)
+
+	platformHost := ""
+	if u, err := url.Parse(public_url); err == nil {
+		platformHost = u.Hostname()
+	}
+	domainService := services.NewDomainService(platformHost, net.DefaultResolver, pageRepository, customDomainRepository, redisRepostory)
+	go domainService.RunReverification(ctx, time.Hour)
+	var mailer services.Mailer
+	if address := os.Getenv("SMTP_ADDRESS"); address != "" {
+		from := os.Getenv("SMTP_FROM")
+		if from == "" {
+			log.Fatal("SMTP_FROM is required when SMTP_ADDRESS is set")
+		}
+		mailer = services.NewSMTPMailer(address, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	} else {
+		log.Print("SMTP_ADDRESS is not set, collaborator invitations are disabled")
+	}
+	var scanner services.ContentScanner = services.NoopScanner{}
+	if address := os.Getenv("CLAMD_ADDRESS"); address != "" {
+		scanner = services.NewClamdScanner(address)
+	}
+	imageService := services.NewImageService(pageRepository, publishedProfileRepository, revisionRepository, themeRepository, linksRepository, mediaRepository, uploadRepository, file_service, scanner)
+	go func() {
+		if err := imageService.BackfillMedia(ctx); err != nil {
+			log.Printf("could not backfill the media library: %v", err)
+		}
+	}()
+	go imageService.RunRecovery(ctx, 10*time.Minute)
+	uploadService := services.NewUploadService(uploadRepository, imageService, file_service)
+	go uploadService.RunExpiry(ctx, 10*time.Minute)
+	embedService := services.NewEmbedService(linksRepository, redisRepostory, services.DefaultOEmbedProviders...)
+	go embedService.RunEmbedRefresh(ctx, time.Minute)
+	storageGCService := services.NewStorageGCService(gcGrace, quarantineRetention, gcDryRun, referenceRepository, mediaRepository, file_service)
+	go storageGCService.RunGarbageCollection(ctx, 6*time.Hour)
 
 	api := api.API{
-		Router:       r,
-		Validator:    validator.New(validator.WithRequiredStructEnabled()),
-		UserService:  services.NewUserService(UsersRepository),
-		LinksService: services.NewLinksService(UsersRepository, linksRepository),
-		AuthService:  services.NewAuthService(jwtSecret, refreshSecret, UsersRepository, redisRepostory),
-		JwtSecret:    jwtSecret,
-		FileService:  file_service,
+		Router:              r,
+		Validator:           validator.New(validator.WithRequiredStructEnabled()),
+		PageService:         services.NewPageService(pageRepository, publishedProfileRepository, collaboratorRepository),
+		LinksService:        services.NewLinksService(unlockSecret, pageRepository, linksRepository, publishedProfileRepository, mediaRepository, redisRepostory),
+		AuthService:         services.NewAuthService(jwtSecret, refreshSecret, UsersRepository, pageRepository, redisRepostory),
+		JwtSecret:           jwtSecret,
+		PublicURL:           public_url,
+		FileService:         file_service,
+		ImageService:        imageService,
+		UploadService:       uploadService,
+		ThemeService:        services.NewThemeService(pageRepository, themeRepository),
+		ShareImageService:   services.NewShareImageService(pageRepository, linksRepository, publishedProfileRepository, file_service),
+		QRCodeService:       services.NewQRCodeService(public_url, pageRepository, linksRepository, file_service),
+		ClickService:        services.NewClickService(clickRepository),
+		DomainService:       domainService,
+		CollaboratorService: services.NewCollaboratorService(public_url, pageRepository, UsersRepository, collaboratorRepository, auditRepository, mailer),
+		PublishService:      services.NewPublishService(revisionLimit, pageRepository, linksRepository, themeRepository, publishedProfileRepository, revisionRepository, redisRepostory),
 	}
 
 	server := &http.Server{
@@ -74,8 +180,68 @@ func main() {
 
 	api.BindRoutes()
 
+	if addr := os.Getenv("METRICS_ADDR"); addr != "" {
+		go func() {
+			if err := http.ListenAndServe(addr, expvar.Handler()); err != nil {
+				log.Printf("metrics server stopped: %v", err)
+			}
+		}()
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("could not shut the server down: %v", err)
+		}
+	}()
+
 	log.Println("Iniciando servidor na porta 8080")
-	if err := server.ListenAndServe(); err != nil {
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal(err)
 	}
+
+	// Uploads accepted before the shutdown are processed before exiting.
+	// Those cut short anyway are processed again by RunRecovery.
+	imageService.Wait()
+}
+
+// newObjectStorage picks the storage backend from STORAGE_DRIVER: "minio"
+// (the default), "local" to keep files under STORAGE_PATH, or "memory".
+// Presigned uploads of the last two are signed with STORAGE_SECRET and
+// accepted under /storage.
+func newObjectStorage(ctx context.Context, driver, bucket, minioURL, minioUser, minioPassword, publicURL string) storage_repository.ObjectStorage {
+	secret := os.Getenv("STORAGE_SECRET")
+	if (driver == "local" || driver == "memory") && secret == "" {
+		log.Fatalf("STORAGE_SECRET is required by the %s storage driver", driver)
+	}
+	signer := storage_repository.NewURLSigner(secret, publicURL+api.StoragePath)
+
+	switch driver {
+	case "", "minio":
+		storage, err := storage_repository.NewMinioObjectStorage(minioURL, minioUser, minioPassword, bucket)
+		if err != nil {
+			log.Fatalf("Failed to initialize minio client: %v", err)
+		}
+		if _, err := storage.CreateBucketIfNotExists(ctx); err != nil {
+			log.Fatalf("Failed to create bucket: %v", err)
+		}
+		return storage
+	case "local":
+		root := os.Getenv("STORAGE_PATH")
+		if root == "" {
+			root = "data"
+		}
+		storage, err := storage_repository.NewLocalObjectStorage(root, signer)
+		if err != nil {
+			log.Fatalf("Failed to initialize local storage: %v", err)
+		}
+		return storage
+	case "memory":
+		return storage_repository.NewInMemoryObjectStorage(signer)
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q", driver)
+		return nil
+	}
 }
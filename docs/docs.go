@@ -6,10 +6,10 @@ import "github.com/swaggo/swag/v2"
 
 const docTemplate = `{
     "schemes": {{ marshal .Schemes }},
-    "components": {"schemas":{"links.CreateLink":{"properties":{"description":{"type":"string"},"title":{"type":"string"},"url":{"type":"string"}},"required":["title","url"],"type":"object"},"user.AuthUser":{"properties":{"password":{"type":"string"},"username":{"type":"string"}},"required":["password","username"],"type":"object"},"user.CreateUser":{"properties":{"email":{"type":"string"},"name":{"type":"string"},"password":{"maxLength":100,"minLength":8,"type":"string"},"username":{"type":"string"}},"required":["email","name","password","username"],"type":"object"},"user.GetUser":{"properties":{"banner_picture":{"type":"string"},"bio":{"type":"string"},"created_at":{"type":"string"},"email":{"type":"string"},"id":{"type":"integer"},"name":{"type":"string"},"profile_picture":{"type":"string"},"username":{"type":"string"}},"type":"object"},"user.UpdateBioRequest":{"properties":{"bio":{"type":"string"}},"type":"object"}},"securitySchemes":{"BearerAuth":{"description":"Type \"Bearer\" followed by a space and JWT token.","in":"header","name":"Authorization","type":"apiKey"}}},
+    "components": {"schemas":{"audit.Entry":{"properties":{"account_id":{"type":"integer"},"action":{"type":"string"},"created_at":{"type":"string"},"details":{"additionalProperties":{"type":"string"},"type":"object"},"email":{"type":"string"},"id":{"type":"integer"}},"type":"object"},"collaborator.GetCollaborator":{"properties":{"account_id":{"type":"integer"},"created_at":{"type":"string"},"email":{"type":"string"},"name":{"type":"string"},"role":{"type":"string"}},"type":"object"},"collaborator.GetInvitation":{"properties":{"created_at":{"type":"string"},"email":{"type":"string"},"expires_at":{"type":"string"},"id":{"type":"integer"},"role":{"type":"string"}},"type":"object"},"collaborator.InviteCollaborator":{"properties":{"email":{"type":"string"},"role":{"enum":["editor","analyst"],"type":"string"}},"required":["email","role"],"type":"object"},"collaborator.Membership":{"properties":{"handle":{"type":"string"},"role":{"type":"string"}},"type":"object"},"customdomain.CreateCustomDomain":{"properties":{"domain":{"maxLength":253,"type":"string"}},"required":["domain"],"type":"object"},"customdomain.GetCustomDomain":{"properties":{"created_at":{"type":"string"},"domain":{"type":"string"},"failure_reason":{"type":"string"},"id":{"type":"integer"},"last_checked_at":{"type":"string"},"status":{"type":"string"},"verification_record":{"$ref":"#/components/schemas/customdomain.VerificationRecord"},"verified_at":{"type":"string"}},"type":"object"},"customdomain.VerificationRecord":{"properties":{"name":{"type":"string"},"type":{"type":"string"},"value":{"type":"string"}},"type":"object"},"links.CreateLink":{"properties":{"description":{"type":"string"},"handle":{"maxLength":255,"type":"string"},"password":{"maxLength":72,"minLength":8,"type":"string"},"platform":{"type":"string"},"sensitive":{"type":"boolean"},"slug":{"maxLength":64,"type":"string"},"thumbnail_media_id":{"description":"ThumbnailMediaID picks an image of the media library to show next to\nthe link.","minimum":1,"type":"integer"},"title":{"type":"string"},"url":{"type":"string"}},"type":"object"},"links.Embed":{"properties":{"height":{"type":"integer"},"html":{"type":"string"},"provider":{"type":"string"},"thumbnail_url":{"type":"string"},"title":{"type":"string"},"type":{"type":"string"},"width":{"type":"integer"}},"type":"object"},"links.GetLink":{"properties":{"created_at":{"type":"string"},"description":{"type":"string"},"embed":{"$ref":"#/components/schemas/links.Embed"},"handle":{"type":"string"},"id":{"type":"integer"},"password_protected":{"type":"boolean"},"platform":{"type":"string"},"sensitive":{"type":"boolean"},"short_code":{"type":"string"},"slug":{"type":"string"},"thumbnail":{"type":"string"},"title":{"type":"string"},"url":{"type":"string"}},"type":"object"},"links.UnlockLink":{"properties":{"confirm_sensitive":{"type":"boolean"},"password":{"maxLength":72,"type":"string"}},"type":"object"},"links.UnlockedLink":{"properties":{"token":{"type":"string"},"url":{"type":"string"}},"type":"object"},"media.Media":{"properties":{"content_type":{"type":"string"},"created_at":{"type":"string"},"height":{"type":"integer"},"id":{"type":"integer"},"in_use":{"type":"boolean"},"size":{"type":"integer"},"url":{"type":"string"},"width":{"type":"integer"}},"type":"object"},"page.CreatePage":{"properties":{"handle":{"maxLength":30,"minLength":3,"type":"string"},"name":{"type":"string"}},"required":["handle","name"],"type":"object"},"page.GetPage":{"properties":{"banner_picture":{"type":"string"},"banner_picture_state":{"type":"string"},"banner_picture_variants":{"additionalProperties":{"type":"string"},"type":"object"},"bio":{"type":"string"},"created_at":{"type":"string"},"handle":{"type":"string"},"id":{"type":"integer"},"name":{"type":"string"},"profile_picture":{"type":"string"},"profile_picture_state":{"type":"string"},"profile_picture_variants":{"additionalProperties":{"type":"string"},"type":"object"},"published_at":{"type":"string"},"role":{"type":"string"},"theme":{"$ref":"#/components/schemas/theme.Theme"},"visibility":{"type":"string"}},"type":"object"},"page.PagePreview":{"properties":{"has_unpublished_changes":{"type":"boolean"},"links":{"items":{"$ref":"#/components/schemas/links.GetLink"},"type":"array","uniqueItems":false},"page":{"$ref":"#/components/schemas/page.GetPage"},"socials":{"items":{"$ref":"#/components/schemas/links.GetLink"},"type":"array","uniqueItems":false}},"type":"object"},"page.PublishedPage":{"properties":{"published_at":{"type":"string"}},"type":"object"},"page.UpdateBioRequest":{"properties":{"bio":{"type":"string"}},"type":"object"},"page.UpdateVisibilityRequest":{"properties":{"visibility":{"enum":["public","unlisted","private"],"type":"string"}},"required":["visibility"],"type":"object"},"revision.Change":{"properties":{"field":{"type":"string"},"from":{},"to":{}},"type":"object"},"revision.GetRevision":{"properties":{"created_at":{"type":"string"},"id":{"type":"integer"},"reason":{"type":"string"}},"type":"object"},"revision.RevisionDiff":{"properties":{"changes":{"items":{"$ref":"#/components/schemas/revision.Change"},"type":"array","uniqueItems":false},"from":{"type":"integer"},"to":{"type":"integer"}},"type":"object"},"theme.Background":{"properties":{"color":{"type":"string"},"gradient":{"$ref":"#/components/schemas/theme.Gradient"},"image":{"type":"string"},"image_url":{"type":"string"},"type":{"enum":["color","gradient","image"],"type":"string"}},"required":["color"],"type":"object"},"theme.Button":{"properties":{"color":{"type":"string"},"fill":{"enum":["solid","outline"],"type":"string"},"shadow":{"enum":["none","soft","hard"],"type":"string"},"shape":{"enum":["square","rounded","pill"],"type":"string"},"text_color":{"type":"string"}},"required":["color","text_color"],"type":"object"},"theme.Gradient":{"properties":{"angle":{"maximum":360,"minimum":0,"type":"integer"},"from":{"type":"string"},"to":{"type":"string"}},"required":["from","to"],"type":"object"},"theme.Theme":{"properties":{"background":{"$ref":"#/components/schemas/theme.Background"},"button":{"$ref":"#/components/schemas/theme.Button"},"font":{"type":"string"},"text_color":{"type":"string"},"version":{"type":"integer"}},"required":["font","text_color"],"type":"object"},"upload.CreateUpload":{"properties":{"checksum_sha256":{"type":"string"},"content_type":{"enum":["image/jpeg","image/png","image/gif","image/webp"],"type":"string"},"kind":{"enum":["avatar","banner"],"type":"string"},"size":{"minimum":1,"type":"integer"}},"required":["content_type","kind","size"],"type":"object"},"upload.PresignedUpload":{"properties":{"expires_at":{"type":"string"},"fields":{"additionalProperties":{"type":"string"},"type":"object"},"id":{"type":"integer"},"method":{"type":"string"},"url":{"type":"string"}},"type":"object"},"upload.Usage":{"properties":{"max_upload_size":{"type":"integer"},"quota_bytes":{"type":"integer"},"used_bytes":{"type":"integer"}},"type":"object"},"user.AuthUser":{"properties":{"password":{"type":"string"},"username":{"type":"string"}},"required":["password","username"],"type":"object"},"user.CreateUser":{"properties":{"email":{"type":"string"},"name":{"type":"string"},"password":{"maxLength":100,"minLength":8,"type":"string"},"username":{"type":"string"}},"required":["email","name","password","username"],"type":"object"},"utm.UTM":{"properties":{"params":{"additionalProperties":{"type":"string"},"type":"object"},"utm_campaign":{"maxLength":100,"type":"string"},"utm_content":{"maxLength":100,"type":"string"},"utm_medium":{"maxLength":100,"type":"string"},"utm_source":{"maxLength":100,"type":"string"},"utm_term":{"maxLength":100,"type":"string"}},"type":"object"}},"securitySchemes":{"BearerAuth":{"description":"Type \"Bearer\" followed by a space and JWT token.","in":"header","name":"Authorization","type":"apiKey"}}},
     "info": {"description":"{{escape .Description}}","title":"{{.Title}}","version":"{{.Version}}"},
     "externalDocs": {"description":"","url":""},
-    "paths": {"/profile/banner":{"put":{"requestBody":{"content":{"multipart/form-data":{"schema":{"type":"file"}}},"description":"Imagem (jpg/png/webp)","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza a foto de banner","tags":["profile"]}},"/profile/bio":{"put":{"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/user.UpdateBioRequest"}}},"description":"payload","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza a bio de um perfil","tags":["profile"]}},"/profile/link":{"post":{"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/links.CreateLink"}}},"description":"payload","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Cria um novo link para um usuário autenticado","tags":["profile"]}},"/profile/links/{username}":{"get":{"parameters":[{"description":"username","in":"path","name":"username","required":true,"schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/user.GetUser"}}},"description":"OK"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"summary":"Busca os links de um usuario","tags":["profile"]}},"/profile/photo":{"put":{"requestBody":{"content":{"multipart/form-data":{"schema":{"type":"file"}}},"description":"Imagem (jpg/png/webp)","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza a foto de perfil","tags":["profile"]}},"/profile/{username}":{"get":{"parameters":[{"description":"username","in":"path","name":"username","required":true,"schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/user.GetUser"}}},"description":"OK"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"summary":"Busca um perfil pelo nome de usuario","tags":["profile"]}},"/users":{"post":{"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/user.CreateUser"}}},"description":"payload","required":true},"responses":{"201":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"integer"},"type":"object"}}},"description":"Created"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{},"type":"object"}}},"description":"Bad Request"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{},"type":"object"}}},"description":"Conflict"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"summary":"Cria um novo usuario","tags":["auth"]}},"/users/login":{"post":{"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/user.AuthUser"}}},"description":"payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"OK"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"summary":"autentica um usuário","tags":["auth"]}}},
+    "paths": {"/img/{key}":{"get":{"description":"URLs estaveis e cacheaveis para fotos, banners e fundos. Suporta ETag e Range. O parametro w redimensiona para uma das larguras permitidas (64, 128, 256, 512, 1024); cada largura e gerada uma vez e guardada, e copias redimensionadas nao sao redimensionadas de novo. Chaves antigas, sem pasta, nao sao servidas.","parameters":[{"description":"chave do objeto","in":"path","name":"key","required":true,"schema":{"type":"string"}},{"description":"largura","in":"query","name":"w","schema":{"type":"integer"}}],"responses":{"200":{"content":{"application/json":{"schema":{"type":"file"}},"image/jpeg":{"schema":{"format":"binary","type":"string"}}},"description":"OK"},"206":{"content":{"application/json":{"schema":{"type":"file"}},"image/jpeg":{"schema":{"format":"binary","type":"string"}}},"description":"Partial Content"},"304":{"content":{"application/json":{}},"description":"Not Modified"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"summary":"Serve uma imagem armazenada","tags":["images"]}},"/invitations/{token}/accept":{"post":{"description":"O convite so pode ser aceito pela conta com o email convidado.","parameters":[{"description":"token do convite","in":"path","name":"token","required":true,"schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/collaborator.Membership"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Conflict"},"410":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Gone"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Aceita um convite para colaborar em uma pagina","tags":["collaborators"]}},"/pages":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"items":{"$ref":"#/components/schemas/page.GetPage"},"type":"array"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Lista as paginas da conta","tags":["pages"]},"post":{"description":"A pagina criada pode ser editada nas rotas de /profile enviando o cabecalho X-Page com o handle.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/page.CreatePage"}}},"description":"payload","required":true},"responses":{"201":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/page.GetPage"}}},"description":"Created"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Conflict"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Cria uma nova pagina na conta","tags":["pages"]}},"/profile/audit":{"get":{"parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"items":{"$ref":"#/components/schemas/audit.Entry"},"type":"array"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Lista as ultimas alteracoes feitas na pagina e quem as fez","tags":["collaborators"]}},"/profile/banner":{"put":{"description":"Arquivos enviados respondem 202: o banner fica com estado \"pending\" enquanto passa pelo scanner de conteudo, e reorientado, tem os metadados EXIF removidos e e recortado na proporcao 3:1 em JPEG. Depois o estado passa a \"ready\", \"rejected\" (sinalizado pelo scanner) ou \"failed\". Envios cujos banners gerados nao cabem na cota sao recusados com 413 antes disso. Imagens da biblioteca ja foram verificadas e respondem 204.","requestBody":{"content":{"multipart/form-data":{"schema":{"type":"integer"}}},"description":"id de uma imagem da biblioteca, no lugar do arquivo"},"responses":{"202":{"content":{"application/json":{}},"description":"Accepted"},"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"413":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Request Entity Too Large"},"415":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unsupported Media Type"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza a foto de banner","tags":["profile"]}},"/profile/bio":{"put":{"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/page.UpdateBioRequest"}}},"description":"payload","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza a bio de um perfil","tags":["profile"]}},"/profile/collaborators":{"get":{"parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"items":{"$ref":"#/components/schemas/collaborator.GetCollaborator"},"type":"array"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Lista o dono e os colaboradores da pagina","tags":["collaborators"]}},"/profile/collaborators/invitations":{"get":{"parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"items":{"$ref":"#/components/schemas/collaborator.GetInvitation"},"type":"array"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Lista os convites pendentes da pagina","tags":["collaborators"]},"post":{"description":"Envia um convite de uso unico que expira em 7 dias. Apenas o dono da pagina pode convidar. Responde 503 quando nenhum servidor SMTP esta configurado.","parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}}],"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/collaborator.InviteCollaborator"}}},"description":"payload","required":true},"responses":{"201":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/collaborator.GetInvitation"}}},"description":"Created"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Conflict"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"},"503":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Service Unavailable"}},"security":[{"BearerAuth":[]}],"summary":"Convida um colaborador por email","tags":["collaborators"]}},"/profile/collaborators/{id}":{"delete":{"parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}},{"description":"id da conta do colaborador","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Remove um colaborador da pagina","tags":["collaborators"]}},"/profile/domains":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"items":{"$ref":"#/components/schemas/customdomain.GetCustomDomain"},"type":"array"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Lista os dominios personalizados do perfil","tags":["domains"]},"post":{"description":"Retorna o registro TXT que deve ser publicado no DNS para comprovar a posse do dominio.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/customdomain.CreateCustomDomain"}}},"description":"payload","required":true},"responses":{"201":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/customdomain.GetCustomDomain"}}},"description":"Created"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Conflict"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Adiciona um dominio personalizado ao perfil","tags":["domains"]}},"/profile/domains/{id}":{"delete":{"parameters":[{"description":"id do dominio","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Remove um dominio personalizado","tags":["domains"]}},"/profile/domains/{id}/verify":{"post":{"parameters":[{"description":"id do dominio","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/customdomain.GetCustomDomain"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Verifica o registro TXT de um dominio personalizado","tags":["domains"]}},"/profile/link":{"post":{"description":"thumbnail_media_id escolhe uma imagem da biblioteca de midia da pagina como miniatura do link; imagens usadas como miniatura nao podem ser apagadas da biblioteca.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/links.CreateLink"}}},"description":"payload","required":true},"responses":{"201":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/links.GetLink"}}},"description":"Created"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Conflict"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Cria um novo link para um usuário autenticado","tags":["profile"]}},"/profile/link/{id}/qr":{"get":{"description":"O QR code aponta para o link curto com source=qr, mantendo as restricoes do link. Apenas quem gerencia a pagina do link pode gera-lo.","parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}},{"description":"id do link","in":"path","name":"id","required":true,"schema":{"type":"integer"}},{"description":"png ou svg","in":"query","name":"format","schema":{"enum":["png","svg"],"type":"string"}},{"description":"tamanho em pixels (128 a 2048)","in":"query","name":"size","schema":{"type":"integer"}},{"description":"nivel de correcao de erros","in":"query","name":"level","schema":{"enum":["L","M","Q","H"],"type":"string"}},{"description":"cor dos modulos em hexadecimal","in":"query","name":"fg","schema":{"type":"string"}},{"description":"cor de fundo em hexadecimal","in":"query","name":"bg","schema":{"type":"string"}},{"description":"usa a foto de perfil no centro","in":"query","name":"logo","schema":{"type":"boolean"}}],"responses":{"200":{"content":{"application/json":{"schema":{"type":"file"}},"image/svg+xml":{"schema":{"type":"string"}}},"description":"OK"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Gera o QR code de um link","tags":["links"]}},"/profile/link/{id}/unlock":{"post":{"parameters":[{"description":"id do link","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/links.UnlockLink"}}},"description":"payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/links.UnlockedLink"}}},"description":"OK"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"429":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Too Many Requests"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"summary":"Desbloqueia um link sensivel ou protegido por senha","tags":["links"]}},"/profile/link/{id}/utm":{"put":{"description":"A alteracao fica no rascunho; os visitantes so recebem os novos parametros depois de publicar.","parameters":[{"description":"id do link","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/utm.UTM"}}},"description":"payload","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Sobrescreve os parametros UTM de um link","tags":["profile"]}},"/profile/links/{handle}":{"get":{"description":"Retorna apenas os links publicados.","parameters":[{"description":"handle","in":"path","name":"handle","required":true,"schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"additionalProperties":{"items":{"$ref":"#/components/schemas/links.GetLink"},"type":"array"},"type":"object"}}},"description":"OK"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"summary":"Busca os links de um usuario","tags":["profile"]}},"/profile/media":{"get":{"description":"Toda imagem enviada como foto, banner ou fundo do tema fica na biblioteca. O id pode ser enviado no campo media_id desses envios para reutiliza-la, ou em thumbnail_media_id ao criar um link para usa-la como miniatura.","parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"items":{"$ref":"#/components/schemas/media.Media"},"type":"array"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Lista a biblioteca de imagens da pagina","tags":["profile"]}},"/profile/media/{id}":{"delete":{"description":"Imagens usadas pelo rascunho, como miniatura de um link, pelo perfil publicado ou por uma revisao guardada nao podem ser removidas.","parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}},{"description":"id da imagem","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Conflict"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Remove uma imagem da biblioteca","tags":["profile"]}},"/profile/photo":{"put":{"description":"Arquivos enviados respondem 202: a foto fica com estado \"pending\" enquanto passa pelo scanner de conteudo, e reorientada, tem os metadados EXIF removidos e e salva em JPEG nos tamanhos 64, 256 e 1024 px. Depois o estado passa a \"ready\", \"rejected\" (sinalizada pelo scanner) ou \"failed\". Envios cujas fotos geradas nao cabem na cota sao recusados com 413 antes disso. Imagens da biblioteca ja foram verificadas e respondem 204.","requestBody":{"content":{"multipart/form-data":{"schema":{"type":"integer"}}},"description":"id de uma imagem da biblioteca, no lugar do arquivo"},"responses":{"202":{"content":{"application/json":{}},"description":"Accepted"},"204":{"content":{"application/json":{}},"description":"No Content"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"413":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Request Entity Too Large"},"415":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unsupported Media Type"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza a foto de perfil","tags":["profile"]}},"/profile/preview":{"get":{"description":"Retorna o perfil e os links como ficarao depois de publicados.","responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/page.PagePreview"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Pre-visualiza o rascunho do perfil","tags":["profile"]}},"/profile/publish":{"post":{"description":"Substitui a versao publica do perfil e dos links pelo rascunho atual.","responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/page.PublishedPage"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Publica o rascunho do perfil","tags":["profile"]}},"/profile/revisions":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"items":{"$ref":"#/components/schemas/revision.GetRevision"},"type":"array"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Lista as revisoes publicadas do perfil","tags":["revisions"]}},"/profile/revisions/diff":{"get":{"parameters":[{"description":"id da revisao de origem","in":"query","name":"from","required":true,"schema":{"type":"integer"}},{"description":"id da revisao de destino","in":"query","name":"to","required":true,"schema":{"type":"integer"}}],"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/revision.RevisionDiff"}}},"description":"OK"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Compara duas revisoes do perfil","tags":["revisions"]}},"/profile/revisions/{id}/restore":{"post":{"description":"Volta o rascunho e a versao publica para a revisao escolhida. Links criados depois dela voltam a ser rascunho.","parameters":[{"description":"id da revisao","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/revision.GetRevision"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Conflict"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Restaura uma revisao do perfil","tags":["revisions"]}},"/profile/theme":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/theme.Theme"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Busca o tema do perfil autenticado","tags":["profile"]},"put":{"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/theme.Theme"}}},"description":"payload","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza o tema do perfil autenticado","tags":["profile"]}},"/profile/theme/background":{"put":{"description":"A imagem enviada entra na biblioteca de imagens. A imagem anterior continua na biblioteca e pode ser escolhida de novo ou apagada em DELETE /profile/media/{id}.","requestBody":{"content":{"multipart/form-data":{"schema":{"type":"integer"}}},"description":"id de uma imagem da biblioteca, no lugar do arquivo"},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"413":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Request Entity Too Large"},"415":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unsupported Media Type"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"},"503":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Service Unavailable"}},"security":[{"BearerAuth":[]}],"summary":"Envia a imagem de fundo do tema","tags":["profile"]}},"/profile/uploads":{"post":{"description":"Retorna uma politica de POST assinada que aceita apenas o tamanho e o tipo declarados. O arquivo vai no campo \"file\", depois dos campos retornados, e o envio deve ser concluido em ate uma hora.","parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}}],"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/upload.CreateUpload"}}},"description":"payload","required":true},"responses":{"201":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/upload.PresignedUpload"}}},"description":"Created"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"413":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Request Entity Too Large"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Inicia um envio direto para o armazenamento","tags":["profile"]}},"/profile/uploads/{id}/complete":{"post":{"description":"Confere tamanho, tipo e checksum do arquivo enviado e o define como foto de perfil ou banner. Responde 202 e a foto fica com estado \"pending\" ate o scanner de conteudo terminar. Envios cujas fotos geradas nao cabem na cota sao recusados com 413 antes disso.","parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}},{"description":"id do envio","in":"path","name":"id","required":true,"schema":{"type":"integer"}}],"responses":{"202":{"content":{"application/json":{}},"description":"Accepted"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Conflict"},"410":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Gone"},"413":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Request Entity Too Large"},"415":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unsupported Media Type"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Conclui um envio direto","tags":["profile"]}},"/profile/usage":{"get":{"description":"Soma, em todas as paginas da conta, a biblioteca de imagens com as fotos e banners feitos a partir dela, o espaco reservado pelos envios pendentes (o tamanho declarado e, depois de aceitos, o que vao armazenar) e os arquivos em quarentena. Envios que ultrapassariam a cota ou o tamanho maximo por arquivo sao recusados com 413.","parameters":[{"description":"handle da pagina gerenciada","in":"header","name":"X-Page","schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/upload.Usage"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Forbidden"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Mostra o uso de armazenamento da conta","tags":["profile"]}},"/profile/utm":{"get":{"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/utm.UTM"}}},"description":"OK"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Busca os parametros UTM padrao do perfil","tags":["profile"]},"put":{"description":"A alteracao fica no rascunho; os visitantes so recebem os novos parametros depois de publicar.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/utm.UTM"}}},"description":"payload","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{}},"description":"Not Found"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza os parametros UTM padrao do perfil","tags":["profile"]}},"/profile/visibility":{"put":{"description":"Perfis nao listados ficam fora dos mecanismos de busca e perfis privados nao sao exibidos.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/page.UpdateVisibilityRequest"}}},"description":"payload","required":true},"responses":{"204":{"content":{"application/json":{}},"description":"No Content"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{}},"description":"Unauthorized"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"422":{"content":{"application/json":{}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"security":[{"BearerAuth":[]}],"summary":"Atualiza a visibilidade do perfil","tags":["profile"]}},"/profile/{handle}":{"get":{"description":"Retorna a ultima versao publicada do perfil. Paginas que nunca foram publicadas, como as de contas novas, respondem 404 ate a primeira publicacao.","parameters":[{"description":"handle","in":"path","name":"handle","required":true,"schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/page.GetPage"}}},"description":"OK"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"summary":"Busca uma pagina pelo handle","tags":["profile"]}},"/profile/{handle}/default-avatar":{"get":{"description":"Iniciais do handle sobre um gradiente derivado dele. E retornada no perfil enquanto nenhuma foto foi enviada.","parameters":[{"description":"handle","in":"path","name":"handle","required":true,"schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"type":"file"}},"image/svg+xml":{"schema":{"type":"string"}}},"description":"OK"}},"summary":"Gera a foto de perfil padrao","tags":["profile"]}},"/profile/{handle}/default-banner":{"get":{"description":"Gradiente derivado do handle. E retornado no perfil enquanto nenhum banner foi enviado.","parameters":[{"description":"handle","in":"path","name":"handle","required":true,"schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"type":"file"}},"image/svg+xml":{"schema":{"type":"string"}}},"description":"OK"}},"summary":"Gera o banner padrao","tags":["profile"]}},"/profile/{handle}/qr":{"get":{"description":"O QR code aponta para a pagina publica com source=qr para contabilizar as leituras.","parameters":[{"description":"handle","in":"path","name":"handle","required":true,"schema":{"type":"string"}},{"description":"png ou svg","in":"query","name":"format","schema":{"enum":["png","svg"],"type":"string"}},{"description":"tamanho em pixels (128 a 2048)","in":"query","name":"size","schema":{"type":"integer"}},{"description":"nivel de correcao de erros","in":"query","name":"level","schema":{"enum":["L","M","Q","H"],"type":"string"}},{"description":"cor dos modulos em hexadecimal","in":"query","name":"fg","schema":{"type":"string"}},{"description":"cor de fundo em hexadecimal","in":"query","name":"bg","schema":{"type":"string"}},{"description":"usa a foto de perfil no centro","in":"query","name":"logo","schema":{"type":"boolean"}}],"responses":{"200":{"content":{"application/json":{"schema":{"type":"file"}},"image/svg+xml":{"schema":{"type":"string"}}},"description":"OK"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"summary":"Gera o QR code de um perfil","tags":["profile"]}},"/s/{code}":{"get":{"parameters":[{"description":"codigo curto","in":"path","name":"code","required":true,"schema":{"type":"string"}},{"description":"token de desbloqueio para links protegidos","in":"query","name":"unlock_token","schema":{"type":"string"}},{"description":"origem do clique, por exemplo qr","in":"query","name":"source","schema":{"type":"string"}}],"responses":{"302":{"content":{"application/json":{}},"description":"Found"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{},"type":"object"}}},"description":"Forbidden"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"summary":"Redireciona para um link pelo codigo curto","tags":["links"]}},"/u/{handle}/{slug}":{"get":{"parameters":[{"description":"handle","in":"path","name":"handle","required":true,"schema":{"type":"string"}},{"description":"slug","in":"path","name":"slug","required":true,"schema":{"type":"string"}},{"description":"token de desbloqueio para links protegidos","in":"query","name":"unlock_token","schema":{"type":"string"}},{"description":"origem do clique, por exemplo qr","in":"query","name":"source","schema":{"type":"string"}}],"responses":{"302":{"content":{"application/json":{}},"description":"Found"},"403":{"content":{"application/json":{"schema":{"additionalProperties":{},"type":"object"}}},"description":"Forbidden"},"404":{"content":{"application/json":{}},"description":"Not Found"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"summary":"Redireciona para o link de um usuario pelo slug","tags":["links"]}},"/users":{"post":{"description":"O username deve ter de 3 a 30 letras, numeros, pontos ou underscores, e nao diferencia maiusculas de minusculas. Nomes reservados e caracteres parecidos com letras latinas sao recusados.","requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/user.CreateUser"}}},"description":"payload","required":true},"responses":{"201":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"integer"},"type":"object"}}},"description":"Created"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{},"type":"object"}}},"description":"Bad Request"},"409":{"content":{"application/json":{"schema":{"additionalProperties":{},"type":"object"}}},"description":"Conflict"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"summary":"Cria um novo usuario","tags":["auth"]}},"/users/login":{"post":{"requestBody":{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/user.AuthUser"}}},"description":"payload","required":true},"responses":{"200":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"OK"},"400":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Bad Request"},"401":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unauthorized"},"422":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Unprocessable Entity"},"500":{"content":{"application/json":{}},"description":"Internal Server Error"}},"summary":"autentica um usuário","tags":["auth"]}},"/{handle}/share.png":{"get":{"description":"Retorna o card PNG usado como og:image da pagina publica do perfil. O card e gerado quando o perfil e publicado.","parameters":[{"description":"handle","in":"path","name":"handle","required":true,"schema":{"type":"string"}}],"responses":{"200":{"content":{"application/json":{"schema":{"type":"file"}},"image/png":{"schema":{"format":"binary","type":"string"}}},"description":"OK"},"304":{"content":{"application/json":{}},"description":"Not Modified"},"404":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Not Found"},"500":{"content":{"application/json":{"schema":{"additionalProperties":{"type":"string"},"type":"object"}}},"description":"Internal Server Error"}},"summary":"Imagem de compartilhamento do perfil","tags":["profile"]}}},
     "openapi": "3.1.0"
 }`
 
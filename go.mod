@@ -14,6 +14,9 @@ require (
 	github.com/redis/go-redis/v9 v9.17.2
 	github.com/swaggo/swag/v2 v2.0.0-rc4
 	golang.org/x/crypto v0.42.0
+	golang.org/x/image v0.31.0
+	golang.org/x/net v0.43.0
+	rsc.io/qr v0.2.0
 )
 
 require (
@@ -46,7 +49,6 @@ require (
 	github.com/rs/xid v1.6.0 // indirect
 	github.com/sv-tools/openapi v0.2.1 // indirect
 	github.com/tinylib/msgp v1.3.0 // indirect
-	golang.org/x/net v0.43.0 // indirect
 	golang.org/x/sync v0.17.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 	golang.org/x/text v0.29.0 // indirect
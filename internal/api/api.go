@@ -7,11 +7,21 @@ import (
 )
 
 type API struct {
-	Router       *chi.Mux
-	Validator    *validator.Validate
-	UserService  *services.UserService
-	LinksService *services.LinksService
-	AuthService  *services.AuthService
-	FileService  *services.FileService
-	JwtSecret    string
+	Router              *chi.Mux
+	Validator           *validator.Validate
+	PageService         *services.PageService
+	LinksService        *services.LinksService
+	AuthService         *services.AuthService
+	FileService         *services.FileService
+	ImageService        *services.ImageService
+	UploadService       *services.UploadService
+	ThemeService        *services.ThemeService
+	ShareImageService   *services.ShareImageService
+	QRCodeService       *services.QRCodeService
+	ClickService        *services.ClickService
+	DomainService       *services.DomainService
+	PublishService      *services.PublishService
+	CollaboratorService *services.CollaboratorService
+	JwtSecret           string
+	PublicURL           string
 }
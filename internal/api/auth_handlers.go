@@ -7,12 +7,14 @@ import (
 	"net/http"
 
 	"github.com/theNixagen/linker/internal/domain/user"
+	"github.com/theNixagen/linker/internal/domain/username"
 	"github.com/theNixagen/linker/internal/repositories/user_repository"
 	"github.com/theNixagen/linker/internal/services"
 )
 
 // CreateUser godoc
 // @Summary      Cria um novo usuario
+// @Description  O username deve ter de 3 a 30 letras, numeros, pontos ou underscores, e nao diferencia maiusculas de minusculas. Nomes reservados e caracteres parecidos com letras latinas sao recusados.
 // @Tags         auth
 // @Produce      json
 // @Accept       json
@@ -44,6 +46,13 @@ func (api *API) CreateUser(w http.ResponseWriter, r *http.Request) {
 
 	id, err := api.AuthService.CreateUser(r.Context(), user)
 	if err != nil {
+		if username.IsPolicyViolation(err) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
 		if errors.Is(err, user_repository.ErrDuplicatedEmail) || errors.Is(err, user_repository.ErrDuplicatedUsername) {
 			w.WriteHeader(http.StatusConflict)
 			json.NewEncoder(w).Encode(map[string]string{
@@ -0,0 +1,258 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/collaborator"
+	"github.com/theNixagen/linker/internal/repositories/collaborator_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// ListCollaborators godoc
+// @Summary      Lista o dono e os colaboradores da pagina
+// @Tags         collaborators
+// @Produce      json
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Security BearerAuth
+// @Success      200  {array}   collaborator.GetCollaborator
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Router       /profile/collaborators [get]
+func (api *API) ListCollaborators(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	collaborators, err := api.CollaboratorService.ListCollaborators(r.Context(), handle)
+	if err != nil {
+		writeCollaboratorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(collaborators)
+}
+
+// ListInvitations godoc
+// @Summary      Lista os convites pendentes da pagina
+// @Tags         collaborators
+// @Produce      json
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Security BearerAuth
+// @Success      200  {array}   collaborator.GetInvitation
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Router       /profile/collaborators/invitations [get]
+func (api *API) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	invitations, err := api.CollaboratorService.ListInvitations(r.Context(), handle)
+	if err != nil {
+		writeCollaboratorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(invitations)
+}
+
+// InviteCollaborator godoc
+// @Summary      Convida um colaborador por email
+// @Description  Envia um convite de uso unico que expira em 7 dias. Apenas o dono da pagina pode convidar. Responde 503 quando nenhum servidor SMTP esta configurado.
+// @Tags         collaborators
+// @Produce      json
+// @Accept       json
+// @Param        X-Page   header  string                           false  "handle da pagina gerenciada"
+// @Param        request  body    collaborator.InviteCollaborator  true   "payload"
+// @Security BearerAuth
+// @Success      201  {object}  collaborator.GetInvitation
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  nil
+// @Failure      500  {object}  nil
+// @Failure      503  {object}  map[string]string
+// @Router       /profile/collaborators/invitations [post]
+func (api *API) InviteCollaborator(w http.ResponseWriter, r *http.Request) {
+	claims, ok := GetTokenClaims(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	handle, ok := GetPageHandle(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req collaborator.InviteCollaborator
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := api.Validator.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	invitation, err := api.CollaboratorService.Invite(r.Context(), handle, int32(claims.ID), req)
+	if err != nil {
+		writeCollaboratorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// RemoveCollaborator godoc
+// @Summary      Remove um colaborador da pagina
+// @Tags         collaborators
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Param        id      path    int     true   "id da conta do colaborador"
+// @Security BearerAuth
+// @Success      204  {object}  nil
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Router       /profile/collaborators/{id} [delete]
+func (api *API) RemoveCollaborator(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err := api.CollaboratorService.RemoveCollaborator(r.Context(), handle, int32(id)); err != nil {
+		writeCollaboratorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AcceptInvitation godoc
+// @Summary      Aceita um convite para colaborar em uma pagina
+// @Description  O convite so pode ser aceito pela conta com o email convidado.
+// @Tags         collaborators
+// @Produce      json
+// @Param        token  path  string  true  "token do convite"
+// @Security BearerAuth
+// @Success      200  {object}  collaborator.Membership
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      410  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Router       /invitations/{token}/accept [post]
+func (api *API) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	claims, ok := GetTokenClaims(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	membership, err := api.CollaboratorService.AcceptInvitation(r.Context(), int32(claims.ID), chi.URLParam(r, "token"))
+	if err != nil {
+		writeCollaboratorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(membership)
+}
+
+// ListAuditLog godoc
+// @Summary      Lista as ultimas alteracoes feitas na pagina e quem as fez
+// @Tags         collaborators
+// @Produce      json
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Security BearerAuth
+// @Success      200  {array}   audit.Entry
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Router       /profile/audit [get]
+func (api *API) ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	entries, err := api.CollaboratorService.ListAudit(r.Context(), handle)
+	if err != nil {
+		writeCollaboratorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(entries)
+}
+
+func writeCollaboratorError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, page_repository.ErrPageNotFound),
+		errors.Is(err, collaborator_repository.ErrCollaboratorNotFound),
+		errors.Is(err, collaborator_repository.ErrInvitationNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvitationMismatch):
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrAlreadyCollaborator):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrInvitationExpired):
+		w.WriteHeader(http.StatusGone)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+	case errors.Is(err, services.ErrMailerUnavailable):
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
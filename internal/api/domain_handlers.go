@@ -0,0 +1,244 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/customdomain"
+	"github.com/theNixagen/linker/internal/repositories/custom_domain_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// CustomDomainMiddleware serves the profile of the owner of a verified custom
+// domain. Requests to the platform host pass through. On a custom domain only
+// short links, the slugs and assets of the owner and images reach the main
+// router; everything else, such as other profiles or the API, is a 404.
+func (api *API) CustomDomainMiddleware(next http.Handler) http.Handler {
+	publicHost := ""
+	if u, err := url.Parse(api.PublicURL); err == nil {
+		publicHost = strings.ToLower(u.Hostname())
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := strings.ToLower(r.Host)
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if host == "" || host == publicHost || host == "localhost" || net.ParseIP(host) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		handle, err := api.DomainService.ResolveHost(r.Context(), host)
+		if err != nil {
+			if !errors.Is(err, custom_domain_repository.ErrDomainNotFound) {
+				log.Printf("could not resolve custom domain %s: %v", host, err)
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		switch r.URL.Path {
+		case "", "/":
+			api.renderProfile(w, r, handle)
+		case "/share.png":
+			api.serveShareImage(w, r, handle)
+		default:
+			if !servedOnCustomDomain(r.URL.Path, handle) {
+				api.renderPage(w, r, http.StatusNotFound, "not_found.html", nil)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+	})
+}
+
+// servedOnCustomDomain reports whether path may be served on the custom
+// domain of the page with handle.
+func servedOnCustomDomain(path, handle string) bool {
+	if strings.HasPrefix(path, "/s/") || strings.HasPrefix(path, ImagePath+"/") {
+		return true
+	}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "u":
+		return strings.EqualFold(segments[1], handle)
+	case len(segments) == 3 && segments[0] == "profile":
+		switch segments[2] {
+		case "default-avatar", "default-banner", "qr":
+			return strings.EqualFold(segments[1], handle)
+		}
+	}
+	return false
+}
+
+// ListCustomDomains godoc
+// @Summary      Lista os dominios personalizados do perfil
+// @Tags         domains
+// @Produce      json
+// @Security BearerAuth
+// @Success      200  {array}   customdomain.GetCustomDomain
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/domains [get]
+func (api *API) ListCustomDomains(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	domains, err := api.DomainService.ListDomains(r.Context(), handle)
+	if err != nil {
+		api.writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(domains)
+}
+
+// AddCustomDomain godoc
+// @Summary      Adiciona um dominio personalizado ao perfil
+// @Description  Retorna o registro TXT que deve ser publicado no DNS para comprovar a posse do dominio.
+// @Tags         domains
+// @Produce      json
+// @Accept       json
+// @Param        request  body  customdomain.CreateCustomDomain  true  "payload"
+// @Security BearerAuth
+// @Success      201  {object}  customdomain.GetCustomDomain
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/domains [post]
+func (api *API) AddCustomDomain(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req customdomain.CreateCustomDomain
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := api.Validator.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	domain, err := api.DomainService.AddDomain(r.Context(), handle, req)
+	if err != nil {
+		api.writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(domain)
+}
+
+// VerifyCustomDomain godoc
+// @Summary      Verifica o registro TXT de um dominio personalizado
+// @Tags         domains
+// @Produce      json
+// @Param        id  path  int  true  "id do dominio"
+// @Security BearerAuth
+// @Success      200  {object}  customdomain.GetCustomDomain
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/domains/{id}/verify [post]
+func (api *API) VerifyCustomDomain(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	domain, err := api.DomainService.VerifyDomain(r.Context(), handle, int32(id))
+	if err != nil {
+		api.writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(domain)
+}
+
+// DeleteCustomDomain godoc
+// @Summary      Remove um dominio personalizado
+// @Tags         domains
+// @Param        id  path  int  true  "id do dominio"
+// @Security BearerAuth
+// @Success      204  {object}  nil
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/domains/{id} [delete]
+func (api *API) DeleteCustomDomain(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if err := api.DomainService.DeleteDomain(r.Context(), handle, int32(id)); err != nil {
+		api.writeDomainError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *API) writeDomainError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, page_repository.ErrPageNotFound), errors.Is(err, custom_domain_repository.ErrDomainNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, customdomain.ErrInvalidDomain), errors.Is(err, customdomain.ErrReservedDomain), errors.Is(err, services.ErrTooManyDomains):
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+	case errors.Is(err, custom_domain_repository.ErrDuplicatedDomain):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/imaging"
+	"github.com/theNixagen/linker/internal/repositories/storage_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// ImagePath is where stored images are served with stable URLs.
+const ImagePath = "/img"
+
+// ServeImage godoc
+// @Summary      Serve uma imagem armazenada
+// @Description  URLs estaveis e cacheaveis para fotos, banners e fundos. Suporta ETag e Range. O parametro w redimensiona para uma das larguras permitidas (64, 128, 256, 512, 1024); cada largura e gerada uma vez e guardada, e copias redimensionadas nao sao redimensionadas de novo. Chaves antigas, sem pasta, nao sao servidas.
+// @Tags         images
+// @Produce      image/jpeg
+// @Param        key  path   string  true   "chave do objeto"
+// @Param        w    query  int     false  "largura"
+// @Success      200  {file}    binary
+// @Success      206  {file}    binary
+// @Success      304  {object}  nil
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /img/{key} [get]
+func (api *API) ServeImage(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "*")
+	if !services.IsPublicKey(key) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	width := 0
+	if raw := r.URL.Query().Get("w"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || !imaging.AllowedWidth(parsed) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": "unsupported width",
+			})
+			return
+		}
+		width = parsed
+	}
+
+	var object io.ReadCloser
+	var info storage_repository.ObjectInfo
+	var err error
+	if width == 0 {
+		object, info, err = api.FileService.GetObject(r.Context(), key)
+	} else {
+		object, info, err = api.FileService.ResizeImage(r.Context(), key, width)
+	}
+	if err != nil {
+		writeStorageError(w, err)
+		return
+	}
+	defer object.Close()
+
+	content, ok := object.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(object)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		content = bytes.NewReader(data)
+	}
+
+	// Keys are content addressed, so what they point at never changes.
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("ETag", `"`+strings.Trim(info.ETag, `"`)+`"`)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", info.LastModified, content)
+}
+
+// imageURL returns the stable URL of a stored image, or "" when the key is
+// not served, such as the plain file names pictures had before content
+// addressing, so callers fall back to their defaults.
+func (api *API) imageURL(key string) string {
+	if !services.IsPublicKey(key) {
+		return ""
+	}
+	return api.PublicURL + ImagePath + "/" + (&url.URL{Path: key}).EscapedPath()
+}
+
+func writeStorageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, storage_repository.ErrObjectNotFound), errors.Is(err, storage_repository.ErrInvalidKey):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, imaging.ErrUnsupportedType), errors.Is(err, imaging.ErrTooManyPixels), errors.Is(err, services.ErrNotResizable):
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
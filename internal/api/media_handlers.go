@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// maxFormOverhead leaves room for the multipart boundaries and fields sent
+// along with the file.
+const maxFormOverhead = 64 << 10
+
+var (
+	errMissingImage   = errors.New(`send a "photo" file or a "media_id"`)
+	errInvalidMediaID = errors.New("invalid media_id")
+)
+
+// ListMedia godoc
+// @Summary      Lista a biblioteca de imagens da pagina
+// @Description  Toda imagem enviada como foto, banner ou fundo do tema fica na biblioteca. O id pode ser enviado no campo media_id desses envios para reutiliza-la, ou em thumbnail_media_id ao criar um link para usa-la como miniatura.
+// @Tags         profile
+// @Produce      json
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Security BearerAuth
+// @Success      200  {array}   media.Media
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/media [get]
+func (api *API) ListMedia(w http.ResponseWriter, r *http.Request) {
+	access, ok := getPageAccess(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	library, err := api.ImageService.ListMedia(r.Context(), access.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	for i := range library {
+		library[i].URL = api.imageURL(library[i].URL)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(library)
+}
+
+// DeleteMedia godoc
+// @Summary      Remove uma imagem da biblioteca
+// @Description  Imagens usadas pelo rascunho, como miniatura de um link, pelo perfil publicado ou por uma revisao guardada nao podem ser removidas.
+// @Tags         profile
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Param        id      path    int     true   "id da imagem"
+// @Security BearerAuth
+// @Success      204  {object}  nil
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/media/{id} [delete]
+func (api *API) DeleteMedia(w http.ResponseWriter, r *http.Request) {
+	access, ok := getPageAccess(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		writeMediaError(w, media_repository.ErrMediaNotFound)
+		return
+	}
+
+	if err := api.ImageService.DeleteMedia(r.Context(), access.ID, int32(id)); err != nil {
+		writeMediaError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// imageSource reads the "photo" file of an upload form or, in its place, the
+// "media_id" of an image of the media library. Bodies larger than the upload
+// limit are cut short with ErrUploadTooLarge. The file must be closed by the
+// caller.
+func (api *API) imageSource(w http.ResponseWriter, r *http.Request) (multipart.File, int32, error) {
+	maxSize := api.FileService.MaxUploadSize
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+maxFormOverhead)
+
+	if err := r.ParseMultipartForm(maxSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, 0, services.ErrUploadTooLarge
+		}
+		if !errors.Is(err, http.ErrNotMultipart) {
+			return nil, 0, err
+		}
+		if err := r.ParseForm(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if value := r.FormValue("media_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, 0, errInvalidMediaID
+		}
+		return nil, int32(id), nil
+	}
+
+	if r.MultipartForm == nil {
+		return nil, 0, errMissingImage
+	}
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		return nil, 0, errMissingImage
+	}
+	return file, 0, nil
+}
+
+// writeImageSourceError answers 413 for bodies over the upload limit and 422
+// for forms without a usable image.
+func writeImageSourceError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrUploadTooLarge) {
+		writeImageError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
+	})
+}
+
+func writeMediaError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, media_repository.ErrMediaNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, services.ErrMediaInUse):
+		w.WriteHeader(http.StatusConflict)
+	default:
+		writeImageError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
+	})
+}
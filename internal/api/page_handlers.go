@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+const metaDescriptionLength = 160
+
+type profilePage struct {
+	Profile page.GetPage
+	Buttons []links.GetLink
+	Socials []links.GetLink
+	Theme   theme.Theme
+	Meta    pageMeta
+	JSONLD  jsonLDProfilePage
+}
+
+type pageMeta struct {
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	NoIndex     bool
+}
+
+type jsonLDProfilePage struct {
+	Context    string       `json:"@context"`
+	Type       string       `json:"@type"`
+	URL        string       `json:"url"`
+	MainEntity jsonLDPerson `json:"mainEntity"`
+}
+
+type jsonLDPerson struct {
+	Type          string   `json:"@type"`
+	Name          string   `json:"name"`
+	AlternateName string   `json:"alternateName"`
+	Description   string   `json:"description,omitempty"`
+	Image         string   `json:"image,omitempty"`
+	SameAs        []string `json:"sameAs,omitempty"`
+}
+
+// RenderProfilePage serves the public profile as HTML so crawlers and link
+// previews can read it without running the React app.
+func (api *API) RenderProfilePage(w http.ResponseWriter, r *http.Request) {
+	api.renderProfile(w, r, chi.URLParam(r, "handle"))
+}
+
+func (api *API) renderProfile(w http.ResponseWriter, r *http.Request, handle string) {
+	profile, err := api.PageService.GetPublishedPage(r.Context(), handle)
+	if err != nil {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
+			api.renderPage(w, r, http.StatusNotFound, "not_found.html", nil)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if source := r.URL.Query().Get("source"); source != "" {
+		if err := api.ClickService.RecordProfileView(r.Context(), profile.ID, source); err != nil {
+			log.Printf("could not record profile view of %s: %v", profile.Handle, err)
+		}
+	}
+
+	api.resolveProfileImages(&profile)
+
+	profileTheme := theme.Default()
+	if profile.Theme != nil {
+		profileTheme = *profile.Theme
+	}
+	api.resolveThemeImages(&profileTheme)
+
+	userLinks, err := api.LinksService.GetPublishedLinks(r.Context(), handle)
+	if err != nil && !errors.Is(err, links_repository.ErrLinksNotFound) {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	api.resolveLinkThumbnails(userLinks)
+	buttons, socials := links.SplitSocials(userLinks)
+
+	pageURL := fmt.Sprintf("%s/%s", api.PublicURL, profile.Handle)
+	meta := pageMeta{
+		Title:       fmt.Sprintf("%s (@%s) | Linker", profile.Name, profile.Handle),
+		Description: metaDescription(profile),
+		URL:         pageURL,
+		Image:       fmt.Sprintf("%s/share.png", pageURL),
+		NoIndex:     profile.Visibility == page.VisibilityUnlisted,
+	}
+	if meta.NoIndex {
+		w.Header().Set("X-Robots-Tag", "noindex")
+	}
+
+	sameAs := make([]string, 0, len(socials))
+	for _, social := range socials {
+		if social.URL != "" {
+			sameAs = append(sameAs, social.URL)
+		}
+	}
+
+	api.renderPage(w, r, http.StatusOK, "profile.html", profilePage{
+		Profile: profile,
+		Buttons: buttons,
+		Socials: socials,
+		Theme:   profileTheme,
+		Meta:    meta,
+		JSONLD: jsonLDProfilePage{
+			Context: "https://schema.org",
+			Type:    "ProfilePage",
+			URL:     pageURL,
+			MainEntity: jsonLDPerson{
+				Type:          "Person",
+				Name:          profile.Name,
+				AlternateName: "@" + profile.Handle,
+				Description:   profile.Bio,
+				Image:         profile.ProfilePicture,
+				SameAs:        sameAs,
+			},
+		},
+	})
+}
+
+func metaDescription(profile page.GetPage) string {
+	description := strings.Join(strings.Fields(profile.Bio), " ")
+	if description == "" {
+		return fmt.Sprintf("Confira os links de %s no Linker.", profile.Name)
+	}
+
+	if utf8.RuneCountInString(description) <= metaDescriptionLength {
+		return description
+	}
+
+	runes := []rune(description)
+	return strings.TrimSpace(string(runes[:metaDescriptionLength-1])) + "…"
+}
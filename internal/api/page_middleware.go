@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/theNixagen/linker/internal/domain/collaborator"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+const PageAccessKey contextKey = "pageAccess"
+
+// PageHeader selects which page a request edits. Without it the account's
+// first page is used.
+const PageHeader = "X-Page"
+
+// pageAccess is the page managed by the authenticated account and the role
+// the account has on it.
+type pageAccess struct {
+	ID     int32
+	Handle string
+	Role   string
+}
+
+func getPageAccess(ctx context.Context) (pageAccess, bool) {
+	access, ok := ctx.Value(PageAccessKey).(pageAccess)
+	return access, ok
+}
+
+func GetPageHandle(ctx context.Context) (string, bool) {
+	access, ok := getPageAccess(ctx)
+	return access.Handle, ok
+}
+
+func GetPageRole(ctx context.Context) (string, bool) {
+	access, ok := getPageAccess(ctx)
+	return access.Role, ok
+}
+
+// PageMiddleware resolves the page managed by the authenticated account, as
+// owner or collaborator. It must run after AuthMiddleware.
+func (api *API) PageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := GetTokenClaims(r.Context())
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		managed, role, err := api.PageService.ManagedPage(r.Context(), int32(claims.ID), r.Header.Get(PageHeader))
+		if err != nil {
+			if errors.Is(err, page_repository.ErrPageNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				json.NewEncoder(w).Encode(map[string]string{
+					"message": "page not found",
+				})
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), PageAccessKey, pageAccess{ID: managed.ID, Handle: managed.Handle, Role: role})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireRole rejects requests from collaborators without one of the given
+// roles. Owners are always allowed. It must run after PageMiddleware.
+func (api *API) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := GetPageRole(r.Context())
+			if !ok {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			if !collaborator.Allows(role, roles...) {
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(map[string]string{
+					"message": "your role can't perform this action",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuditMiddleware records successful changes to the managed page with the
+// account that made them. Reads are not recorded. It must run after
+// PageMiddleware.
+func (api *API) AuditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		if ww.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		claims, ok := GetTokenClaims(r.Context())
+		if !ok {
+			return
+		}
+		access, ok := getPageAccess(r.Context())
+		if !ok {
+			return
+		}
+
+		rctx := chi.RouteContext(r.Context())
+		details := map[string]string{}
+		for i, key := range rctx.URLParams.Keys {
+			if key != "*" {
+				details[key] = rctx.URLParams.Values[i]
+			}
+		}
+
+		action := r.Method + " " + rctx.RoutePattern()
+		if err := api.CollaboratorService.Record(r.Context(), access.ID, int32(claims.ID), action, details); err != nil {
+			log.Printf("could not record audit entry %s for %s: %v", action, access.Handle, err)
+		}
+	})
+}
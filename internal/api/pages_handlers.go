@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/username"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+// ListPages godoc
+// @Summary      Lista as paginas da conta
+// @Tags         pages
+// @Produce      json
+// @Security BearerAuth
+// @Success      200  {array}   page.GetPage
+// @Failure      401  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /pages [get]
+func (api *API) ListPages(w http.ResponseWriter, r *http.Request) {
+	claims, ok := GetTokenClaims(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	pages, err := api.PageService.ListPages(r.Context(), int32(claims.ID))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(pages)
+}
+
+// CreatePage godoc
+// @Summary      Cria uma nova pagina na conta
+// @Description  A pagina criada pode ser editada nas rotas de /profile enviando o cabecalho X-Page com o handle.
+// @Tags         pages
+// @Produce      json
+// @Accept       json
+// @Param        request  body  page.CreatePage  true  "payload"
+// @Security BearerAuth
+// @Success      201  {object}  page.GetPage
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /pages [post]
+func (api *API) CreatePage(w http.ResponseWriter, r *http.Request) {
+	claims, ok := GetTokenClaims(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req page.CreatePage
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := api.Validator.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	created, err := api.PageService.CreatePage(r.Context(), int32(claims.ID), req)
+	if err != nil {
+		if username.IsPolicyViolation(err) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, page_repository.ErrDuplicatedHandle) {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
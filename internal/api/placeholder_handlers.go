@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/placeholder"
+)
+
+// GetDefaultAvatar godoc
+// @Summary      Gera a foto de perfil padrao
+// @Description  Iniciais do handle sobre um gradiente derivado dele. E retornada no perfil enquanto nenhuma foto foi enviada.
+// @Tags         profile
+// @Produce      image/svg+xml
+// @Param        handle  path  string  true  "handle"
+// @Success      200  {file}  file
+// @Router       /profile/{handle}/default-avatar [get]
+func (api *API) GetDefaultAvatar(w http.ResponseWriter, r *http.Request) {
+	writePlaceholder(w, placeholder.Avatar(chi.URLParam(r, "handle")))
+}
+
+// GetDefaultBanner godoc
+// @Summary      Gera o banner padrao
+// @Description  Gradiente derivado do handle. E retornado no perfil enquanto nenhum banner foi enviado.
+// @Tags         profile
+// @Produce      image/svg+xml
+// @Param        handle  path  string  true  "handle"
+// @Success      200  {file}  file
+// @Router       /profile/{handle}/default-banner [get]
+func (api *API) GetDefaultBanner(w http.ResponseWriter, r *http.Request) {
+	writePlaceholder(w, placeholder.Banner(chi.URLParam(r, "handle")))
+}
+
+func writePlaceholder(w http.ResponseWriter, svg []byte) {
+	w.Header().Set("Content-Type", placeholder.ContentType)
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.WriteHeader(http.StatusOK)
+	w.Write(svg)
+}
+
+func (api *API) defaultAvatarURL(handle string) string {
+	return api.PublicURL + "/profile/" + url.PathEscape(handle) + "/default-avatar"
+}
+
+func (api *API) defaultBannerURL(handle string) string {
+	return api.PublicURL + "/profile/" + url.PathEscape(handle) + "/default-banner"
+}
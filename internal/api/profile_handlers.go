@@ -6,30 +6,34 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/imaging"
 	"github.com/theNixagen/linker/internal/domain/links"
-	"github.com/theNixagen/linker/internal/domain/user"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/platform"
 	"github.com/theNixagen/linker/internal/repositories/links_repository"
-	"github.com/theNixagen/linker/internal/repositories/user_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/services"
 )
 
 // GetProfile godoc
-// @Summary      Busca um perfil pelo nome de usuario
+// @Summary      Busca uma pagina pelo handle
+// @Description  Retorna a ultima versao publicada do perfil. Paginas que nunca foram publicadas, como as de contas novas, respondem 404 ate a primeira publicacao.
 // @Tags         profile
 // @Produce      json
-// @Param        username  path      string  true  "username"
-// @Success      200  {object}  user.GetUser
+// @Param        handle  path      string  true  "handle"
+// @Success      200  {object}  page.GetPage
 // @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
-// @Router       /profile/{username} [get]
+// @Router       /profile/{handle} [get]
 func (api *API) GetProfile(w http.ResponseWriter, r *http.Request) {
-	user := chi.URLParam(r, "username")
+	handle := chi.URLParam(r, "handle")
 
-	profile, err := api.UserService.GetUser(r.Context(), user)
+	profile, err := api.PageService.GetPublishedPage(r.Context(), handle)
 	if err != nil {
-		if errors.Is(err, user_repository.ErrUserNotFound) {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode(map[string]string{
-				"message": "user not found",
+				"message": "page not found",
 			})
 			return
 		}
@@ -40,22 +44,10 @@ func (api *API) GetProfile(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	profileURL, err := api.FileService.GetSignedURL(r.Context(), profile.ProfilePicture, api.FileService.BucketName)
-	if err != nil {
-		profile.ProfilePicture = ""
-	}
-
-	bannerURL, err := api.FileService.GetSignedURL(r.Context(), profile.BannerPicture, api.FileService.BucketName)
-	if err != nil {
-		profile.BannerPicture = ""
-	}
-
-	if profileURL != nil {
-		profile.ProfilePicture = profileURL.String()
-	}
+	api.resolveProfileImages(&profile)
 
-	if bannerURL != nil {
-		profile.BannerPicture = bannerURL.String()
+	if profile.Theme != nil {
+		api.resolveThemeImages(profile.Theme)
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -67,7 +59,7 @@ func (api *API) GetProfile(w http.ResponseWriter, r *http.Request) {
 // @Tags         profile
 // @Produce      json
 // @Accept       json
-// @Param        request  body  user.UpdateBioRequest  true  "payload"
+// @Param        request  body  page.UpdateBioRequest  true  "payload"
 // @Security BearerAuth
 // @Success      204  {object}  nil
 // @Failure      404  {object}  map[string]string
@@ -76,9 +68,9 @@ func (api *API) GetProfile(w http.ResponseWriter, r *http.Request) {
 // @Failure      422  {object}  map[string]string
 // @Router       /profile/bio [put]
 func (api *API) UpdateBio(w http.ResponseWriter, r *http.Request) {
-	userClaims, ok := GetTokenClaims(r.Context())
+	handle, ok := GetPageHandle(r.Context())
 
-	var req user.UpdateBioRequest
+	var req page.UpdateBioRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		w.WriteHeader(http.StatusUnprocessableEntity)
@@ -96,11 +88,11 @@ func (api *API) UpdateBio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := api.UserService.UpdateBio(r.Context(), userClaims.Username, req.Bio); err != nil {
-		if errors.Is(err, user_repository.ErrUserNotFound) {
+	if err := api.PageService.UpdateBio(r.Context(), handle, req.Bio); err != nil {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			json.NewEncoder(w).Encode(map[string]string{
-				"message": "user not found",
+				"message": "page not found",
 			})
 			return
 		}
@@ -119,52 +111,52 @@ func (api *API) UpdateBio(w http.ResponseWriter, r *http.Request) {
 // @Tags         profile
 // @Produce      json
 // @Accept       multipart/form-data
-// @Param        photo  formData  file  true  "Imagem (jpg/png/webp)"
+// @Description  Arquivos enviados respondem 202: a foto fica com estado "pending" enquanto passa pelo scanner de conteudo, e reorientada, tem os metadados EXIF removidos e e salva em JPEG nos tamanhos 64, 256 e 1024 px. Depois o estado passa a "ready", "rejected" (sinalizada pelo scanner) ou "failed". Envios cujas fotos geradas nao cabem na cota sao recusados com 413 antes disso. Imagens da biblioteca ja foram verificadas e respondem 204.
+// @Param        photo     formData  file  false  "Imagem (jpg/png/gif/webp)"
+// @Param        media_id  formData  int   false  "id de uma imagem da biblioteca, no lugar do arquivo"
 // @Security BearerAuth
+// @Success      202  {object}  nil
 // @Success      204  {object}  nil
-// @Failure      404  {object}  nil
+// @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  nil
 // @Failure      401  {object}  nil
-// @Failure      422  {object}  nil
+// @Failure      413  {object}  map[string]string
+// @Failure      415  {object}  map[string]string
+// @Failure      422  {object}  map[string]string
 // @Router       /profile/photo [put]
 func (api *API) UploadProfilePicture(w http.ResponseWriter, r *http.Request) {
-	claims, ok := GetTokenClaims(r.Context())
+	handle, ok := GetPageHandle(r.Context())
 
 	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	err := r.ParseMultipartForm(10 << 20)
-	if err != nil {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		return
-	}
-
-	file, header, err := r.FormFile("photo")
+	file, mediaID, err := api.imageSource(w, r)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeImageSourceError(w, err)
 		return
 	}
 
-	defer file.Close()
-
-	info, err := api.FileService.PutObject(r.Context(), header.Filename, file, header.Size)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	if file != nil {
+		defer file.Close()
+		err = api.ImageService.UploadProfilePhoto(r.Context(), handle, file)
+	} else {
+		err = api.ImageService.AttachMedia(r.Context(), handle, services.ImageKindAvatar, mediaID)
 	}
-
-	err = api.UserService.UploadProfilePhoto(r.Context(), claims.Username, info.Key)
 	if err != nil {
-		if errors.Is(err, user_repository.ErrUserNotFound) {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		w.WriteHeader(http.StatusInternalServerError)
+		writeMediaError(w, err)
 		return
 	}
 
+	if file != nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -173,64 +165,67 @@ func (api *API) UploadProfilePicture(w http.ResponseWriter, r *http.Request) {
 // @Tags         profile
 // @Produce      json
 // @Accept       multipart/form-data
-// @Param        photo  formData  file  true  "Imagem (jpg/png/webp)"
+// @Description  Arquivos enviados respondem 202: o banner fica com estado "pending" enquanto passa pelo scanner de conteudo, e reorientado, tem os metadados EXIF removidos e e recortado na proporcao 3:1 em JPEG. Depois o estado passa a "ready", "rejected" (sinalizado pelo scanner) ou "failed". Envios cujos banners gerados nao cabem na cota sao recusados com 413 antes disso. Imagens da biblioteca ja foram verificadas e respondem 204.
+// @Param        photo     formData  file  false  "Imagem (jpg/png/gif/webp)"
+// @Param        media_id  formData  int   false  "id de uma imagem da biblioteca, no lugar do arquivo"
 // @Security BearerAuth
+// @Success      202  {object}  nil
 // @Success      204  {object}  nil
-// @Failure      404  {object}  nil
+// @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  nil
 // @Failure      401  {object}  nil
-// @Failure      422  {object}  nil
+// @Failure      413  {object}  map[string]string
+// @Failure      415  {object}  map[string]string
+// @Failure      422  {object}  map[string]string
 // @Router       /profile/banner [put]
 func (api *API) UploadBanner(w http.ResponseWriter, r *http.Request) {
-	claims, ok := GetTokenClaims(r.Context())
+	handle, ok := GetPageHandle(r.Context())
 
 	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	err := r.ParseMultipartForm(10 << 20)
-	if err != nil {
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		return
-	}
-
-	file, header, err := r.FormFile("photo")
+	file, mediaID, err := api.imageSource(w, r)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
+		writeImageSourceError(w, err)
 		return
 	}
 
-	defer file.Close()
-
-	info, err := api.FileService.PutObject(r.Context(), header.Filename, file, header.Size)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		return
+	if file != nil {
+		defer file.Close()
+		err = api.ImageService.UploadBanner(r.Context(), handle, file)
+	} else {
+		err = api.ImageService.AttachMedia(r.Context(), handle, services.ImageKindBanner, mediaID)
 	}
-
-	err = api.UserService.UploadBanner(r.Context(), claims.Username, info.Key)
 	if err != nil {
-		if errors.Is(err, user_repository.ErrUserNotFound) {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
-		w.WriteHeader(http.StatusInternalServerError)
+		writeMediaError(w, err)
 		return
 	}
 
+	if file != nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
 
 // CreateLink godoc
 // @Summary      Cria um novo link para um usuário autenticado
+// @Description  thumbnail_media_id escolhe uma imagem da biblioteca de midia da pagina como miniatura do link; imagens usadas como miniatura nao podem ser apagadas da biblioteca.
 // @Tags         profile
 // @Produce      json
 // @Accept       json
 // @Param        request  body  links.CreateLink  true  "payload"
 // @Security BearerAuth
-// @Success      204  {object}  nil
+// @Success      201  {object}  links.GetLink
+// @Failure      400  {object}  map[string]string
 // @Failure      404  {object}  nil
+// @Failure      409  {object}  map[string]string
 // @Failure      500  {object}  nil
 // @Failure      401  {object}  nil
 // @Failure      422  {object}  nil
@@ -238,7 +233,7 @@ func (api *API) UploadBanner(w http.ResponseWriter, r *http.Request) {
 func (api *API) CreateNewLink(w http.ResponseWriter, r *http.Request) {
 	var link links.CreateLink
 
-	claims, ok := GetTokenClaims(r.Context())
+	handle, ok := GetPageHandle(r.Context())
 
 	if !ok {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -258,34 +253,53 @@ func (api *API) CreateNewLink(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := api.LinksService.CreateLink(r.Context(), claims.Username, link.URL, link.Title, link.Description)
+	created, err := api.LinksService.CreateLink(r.Context(), handle, link)
 	if err != nil {
-		if errors.Is(err, user_repository.ErrUserNotFound) {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, links.ErrInvalidSlug) || errors.Is(err, links.ErrReservedSlug) ||
+			errors.Is(err, platform.ErrUnknownPlatform) || errors.Is(err, platform.ErrInvalidHandle) ||
+			errors.Is(err, links_repository.ErrThumbnailNotFound) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, links_repository.ErrDuplicatedSlug) {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
+	created.Thumbnail = api.imageURL(created.Thumbnail)
 	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
 }
 
 // GetUserLinks godoc
 // @Summary      Busca os links de um usuario
+// @Description  Retorna apenas os links publicados.
 // @Tags         profile
 // @Produce      json
-// @Param        username  path      string  true  "username"
-// @Success      200  {object}  user.GetUser
+// @Param        handle  path      string  true  "handle"
+// @Success      200  {object}  map[string][]links.GetLink
 // @Failure      404  {object}  map[string]string
 // @Failure      500  {object}  map[string]string
-// @Router       /profile/links/{username} [get]
+// @Router       /profile/links/{handle} [get]
 func (api *API) GetUserLinks(w http.ResponseWriter, r *http.Request) {
-	username := chi.URLParam(r, "username")
+	handle := chi.URLParam(r, "handle")
 
-	links, err := api.LinksService.GetAllLinksFromAUser(r.Context(), username)
+	userLinks, err := api.LinksService.GetPublishedLinks(r.Context(), handle)
 	if err != nil {
-		if errors.Is(err, links_repository.ErrLinksNotFound) {
+		if errors.Is(err, links_repository.ErrLinksNotFound) || errors.Is(err, page_repository.ErrPageNotFound) {
 			w.WriteHeader(http.StatusNotFound)
 			return
 		}
@@ -293,8 +307,31 @@ func (api *API) GetUserLinks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	api.resolveLinkThumbnails(userLinks)
+	buttons, socials := links.SplitSocials(userLinks)
+
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]any{
-		"links": links,
+		"links":   buttons,
+		"socials": socials,
+	})
+}
+
+func writeImageError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUploadTooLarge), errors.Is(err, services.ErrQuotaExceeded):
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	case errors.Is(err, imaging.ErrUnsupportedType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Is(err, imaging.ErrTooManyPixels), errors.Is(err, services.ErrContentRejected):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Is(err, services.ErrScannerUnavailable):
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
 	})
 }
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/theNixagen/linker/internal/domain/imaging"
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// PreviewProfile godoc
+// @Summary      Pre-visualiza o rascunho do perfil
+// @Description  Retorna o perfil e os links como ficarao depois de publicados.
+// @Tags         profile
+// @Produce      json
+// @Security BearerAuth
+// @Success      200  {object}  page.PagePreview
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/preview [get]
+func (api *API) PreviewProfile(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	preview, err := api.PublishService.Preview(r.Context(), handle)
+	if err != nil {
+		writePublishError(w, err)
+		return
+	}
+
+	api.resolveProfileImages(&preview.Page)
+	api.resolveLinkThumbnails(preview.Links)
+	api.resolveLinkThumbnails(preview.Socials)
+	if preview.Page.Theme != nil {
+		api.resolveThemeImages(preview.Page.Theme)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(preview)
+}
+
+// PublishProfile godoc
+// @Summary      Publica o rascunho do perfil
+// @Description  Substitui a versao publica do perfil e dos links pelo rascunho atual.
+// @Tags         profile
+// @Produce      json
+// @Security BearerAuth
+// @Success      200  {object}  page.PublishedPage
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/publish [post]
+func (api *API) PublishProfile(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	published, err := api.PublishService.Publish(r.Context(), handle)
+	if err != nil {
+		writePublishError(w, err)
+		return
+	}
+	api.renderShareImage(r, handle)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(published)
+}
+
+// UpdateVisibility godoc
+// @Summary      Atualiza a visibilidade do perfil
+// @Description  Perfis nao listados ficam fora dos mecanismos de busca e perfis privados nao sao exibidos.
+// @Tags         profile
+// @Produce      json
+// @Accept       json
+// @Param        request  body  page.UpdateVisibilityRequest  true  "payload"
+// @Security BearerAuth
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      422  {object}  nil
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/visibility [put]
+func (api *API) UpdateVisibility(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req page.UpdateVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := api.Validator.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := api.PublishService.UpdateVisibility(r.Context(), handle, req.Visibility); err != nil {
+		writePublishError(w, err)
+		return
+	}
+	api.renderShareImage(r, handle)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resolveProfileImages turns picture keys into URLs, falling back to the
+// generated defaults when nothing was uploaded or the key is not served.
+func (api *API) resolveProfileImages(profile *page.GetPage) {
+	profile.ProfilePictureVariants = api.variantURLs(profile.ProfilePicture, imaging.AvatarVariants)
+	profile.BannerPictureVariants = api.variantURLs(profile.BannerPicture, imaging.BannerVariants)
+	profile.ProfilePicture = api.imageURL(profile.ProfilePicture)
+	profile.BannerPicture = api.imageURL(profile.BannerPicture)
+
+	if profile.ProfilePicture == "" {
+		profile.ProfilePicture = api.defaultAvatarURL(profile.Handle)
+	}
+	if profile.BannerPicture == "" {
+		profile.BannerPicture = api.defaultBannerURL(profile.Handle)
+	}
+}
+
+// resolveLinkThumbnails turns the thumbnail keys of links into URLs.
+func (api *API) resolveLinkThumbnails(all []links.GetLink) {
+	for i := range all {
+		all[i].Thumbnail = api.imageURL(all[i].Thumbnail)
+	}
+}
+
+func (api *API) variantURLs(key string, variants []imaging.Variant) map[string]string {
+	keys := imaging.VariantKeys(key, variants)
+	if keys == nil || !services.IsPublicKey(key) {
+		return nil
+	}
+
+	urls := make(map[string]string, len(keys))
+	for name, variantKey := range keys {
+		urls[name] = api.imageURL(variantKey)
+	}
+	return urls
+}
+
+func writePublishError(w http.ResponseWriter, err error) {
+	if errors.Is(err, page_repository.ErrPageNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "page not found",
+		})
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
+	})
+}
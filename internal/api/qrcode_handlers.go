@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/qrcode"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+// GetProfileQRCode godoc
+// @Summary      Gera o QR code de um perfil
+// @Description  O QR code aponta para a pagina publica com source=qr para contabilizar as leituras.
+// @Tags         profile
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        handle  path   string  true   "handle"
+// @Param        format    query  string  false  "png ou svg"  Enums(png, svg)
+// @Param        size      query  int     false  "tamanho em pixels (128 a 2048)"
+// @Param        level     query  string  false  "nivel de correcao de erros"  Enums(L, M, Q, H)
+// @Param        fg        query  string  false  "cor dos modulos em hexadecimal"
+// @Param        bg        query  string  false  "cor de fundo em hexadecimal"
+// @Param        logo      query  bool    false  "usa a foto de perfil no centro"
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/{handle}/qr [get]
+func (api *API) GetProfileQRCode(w http.ResponseWriter, r *http.Request) {
+	opts, ok := api.decodeQRCodeOptions(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := api.QRCodeService.ProfileQRCode(r.Context(), chi.URLParam(r, "handle"), opts)
+	if err != nil {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": "page not found",
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "could not generate qr code",
+		})
+		return
+	}
+
+	writeQRCode(w, opts, code, "public, max-age=3600")
+}
+
+// GetLinkQRCode godoc
+// @Summary      Gera o QR code de um link
+// @Description  O QR code aponta para o link curto com source=qr, mantendo as restricoes do link. Apenas quem gerencia a pagina do link pode gera-lo.
+// @Tags         links
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Param        id      path   int     true   "id do link"
+// @Param        format  query  string  false  "png ou svg"  Enums(png, svg)
+// @Param        size    query  int     false  "tamanho em pixels (128 a 2048)"
+// @Param        level   query  string  false  "nivel de correcao de erros"  Enums(L, M, Q, H)
+// @Param        fg      query  string  false  "cor dos modulos em hexadecimal"
+// @Param        bg      query  string  false  "cor de fundo em hexadecimal"
+// @Param        logo    query  bool    false  "usa a foto de perfil no centro"
+// @Security BearerAuth
+// @Success      200  {file}    file
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/link/{id}/qr [get]
+func (api *API) GetLinkQRCode(w http.ResponseWriter, r *http.Request) {
+	access, ok := getPageAccess(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	opts, ok := api.decodeQRCodeOptions(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := api.QRCodeService.LinkQRCode(r.Context(), access.ID, int32(id), opts)
+	if err != nil {
+		if errors.Is(err, links_repository.ErrLinkNotFound) || errors.Is(err, page_repository.ErrPageNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "could not generate qr code",
+		})
+		return
+	}
+
+	writeQRCode(w, opts, code, "private, max-age=3600")
+}
+
+func (api *API) decodeQRCodeOptions(w http.ResponseWriter, r *http.Request) (qrcode.Options, bool) {
+	query := r.URL.Query()
+	opts := qrcode.DefaultOptions()
+
+	if format := query.Get("format"); format != "" {
+		opts.Format = strings.ToLower(format)
+	}
+	if level := query.Get("level"); level != "" {
+		opts.Level = strings.ToUpper(level)
+	}
+	if fg := query.Get("fg"); fg != "" {
+		opts.Foreground = fg
+	}
+	if bg := query.Get("bg"); bg != "" {
+		opts.Background = bg
+	}
+
+	if size := query.Get("size"); size != "" {
+		parsed, err := strconv.Atoi(size)
+		if err != nil {
+			writeQRCodeOptionsError(w, errors.New("size must be a number"))
+			return qrcode.Options{}, false
+		}
+		opts.Size = parsed
+	}
+
+	if logo := query.Get("logo"); logo != "" {
+		parsed, err := strconv.ParseBool(logo)
+		if err != nil {
+			writeQRCodeOptionsError(w, errors.New("logo must be a boolean"))
+			return qrcode.Options{}, false
+		}
+		opts.Logo = parsed
+	}
+
+	if err := api.Validator.Struct(opts); err != nil {
+		writeQRCodeOptionsError(w, err)
+		return qrcode.Options{}, false
+	}
+
+	if err := opts.Validate(); err != nil {
+		writeQRCodeOptionsError(w, err)
+		return qrcode.Options{}, false
+	}
+
+	return opts, true
+}
+
+func writeQRCodeOptionsError(w http.ResponseWriter, err error) {
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
+	})
+}
+
+func writeQRCode(w http.ResponseWriter, opts qrcode.Options, code []byte, cacheControl string) {
+	w.Header().Set("Content-Type", opts.ContentType())
+	w.Header().Set("Cache-Control", cacheControl)
+	w.WriteHeader(http.StatusOK)
+	w.Write(code)
+}
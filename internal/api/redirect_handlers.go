@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// ResolveSlug godoc
+// @Summary      Redireciona para o link de um usuario pelo slug
+// @Tags         links
+// @Param        handle      path   string  true   "handle"
+// @Param        slug          path   string  true   "slug"
+// @Param        unlock_token  query  string  false  "token de desbloqueio para links protegidos"
+// @Param        source        query  string  false  "origem do clique, por exemplo qr"
+// @Success      302  {object}  nil
+// @Failure      403  {object}  map[string]any
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /u/{handle}/{slug} [get]
+func (api *API) ResolveSlug(w http.ResponseWriter, r *http.Request) {
+	handle := chi.URLParam(r, "handle")
+	slug := chi.URLParam(r, "slug")
+
+	link, err := api.LinksService.ResolveSlug(r.Context(), handle, slug)
+	if err != nil {
+		if errors.Is(err, links_repository.ErrLinkNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	api.redirectToLink(w, r, link)
+}
+
+// ResolveShortCode godoc
+// @Summary      Redireciona para um link pelo codigo curto
+// @Tags         links
+// @Param        code          path   string  true   "codigo curto"
+// @Param        unlock_token  query  string  false  "token de desbloqueio para links protegidos"
+// @Param        source        query  string  false  "origem do clique, por exemplo qr"
+// @Success      302  {object}  nil
+// @Failure      403  {object}  map[string]any
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /s/{code} [get]
+func (api *API) ResolveShortCode(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+
+	link, err := api.LinksService.ResolveShortCode(r.Context(), code)
+	if err != nil {
+		if errors.Is(err, links_repository.ErrLinkNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	api.redirectToLink(w, r, link)
+}
+
+func (api *API) redirectToLink(w http.ResponseWriter, r *http.Request, link links.ResolvedLink) {
+	if err := api.LinksService.CheckGate(link, r.URL.Query().Get("unlock_token")); err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]any{
+			"message":            err.Error(),
+			"link_id":            link.ID,
+			"sensitive":          link.Sensitive,
+			"password_protected": link.Protected,
+		})
+		return
+	}
+
+	if err := api.ClickService.RecordLinkClick(r.Context(), link.ID, r.URL.Query().Get("source")); err != nil {
+		log.Printf("could not record click on link %d: %v", link.ID, err)
+	}
+
+	http.Redirect(w, r, link.URL, http.StatusFound)
+}
+
+// UnlockLink godoc
+// @Summary      Desbloqueia um link sensivel ou protegido por senha
+// @Tags         links
+// @Produce      json
+// @Accept       json
+// @Param        id       path  int                true  "id do link"
+// @Param        request  body  links.UnlockLink  true  "payload"
+// @Success      200  {object}  links.UnlockedLink
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      404  {object}  nil
+// @Failure      422  {object}  nil
+// @Failure      429  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Router       /profile/link/{id}/unlock [post]
+func (api *API) UnlockLink(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var req links.UnlockLink
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := api.Validator.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	unlocked, err := api.LinksService.UnlockLink(r.Context(), int32(id), clientIP(r), req)
+	if err != nil {
+		if errors.Is(err, links_repository.ErrLinkNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, services.ErrTooManyUnlockAttempts) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(services.UnlockAttemptWindow.Seconds())))
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrSensitiveNotConfirmed) {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidLinkPassword) {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(unlocked)
+}
+
+// clientIP returns the address the request came from, without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/theNixagen/linker/internal/domain/links"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+var pageTemplates = template.Must(template.New("pages").Funcs(template.FuncMap{
+	"linkHref":  linkHref,
+	"embedHTML": embedHTML,
+}).ParseFS(templatesFS, "templates/*.html"))
+
+// linkHref sends gated links through the short link redirect, which enforces
+// the gate, since their destination is not exposed.
+func linkHref(link links.GetLink) string {
+	if link.IsGated() || link.URL == "" {
+		return "/s/" + link.ShortCode
+	}
+	return link.URL
+}
+
+// embedHTML trusts the markup because embeds only come from the registered
+// oEmbed providers.
+func embedHTML(embed *links.Embed) template.HTML {
+	return template.HTML(embed.HTML)
+}
+
+func (api *API) renderPage(w http.ResponseWriter, r *http.Request, status int, name string, data any) {
+	var buf bytes.Buffer
+	if err := pageTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := fmt.Sprintf(`W/"%s"`, hex.EncodeToString(sum[:16]))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Vary", "Accept-Encoding")
+	if status == http.StatusOK {
+		w.Header().Set("Cache-Control", "public, max-age=60, stale-while-revalidate=300")
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+
+	if status == http.StatusOK && r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
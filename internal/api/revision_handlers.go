@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+)
+
+// ListRevisions godoc
+// @Summary      Lista as revisoes publicadas do perfil
+// @Tags         revisions
+// @Produce      json
+// @Security BearerAuth
+// @Success      200  {array}   revision.GetRevision
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/revisions [get]
+func (api *API) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	revisions, err := api.PublishService.ListRevisions(r.Context(), handle)
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// DiffRevisions godoc
+// @Summary      Compara duas revisoes do perfil
+// @Tags         revisions
+// @Produce      json
+// @Param        from  query  int  true  "id da revisao de origem"
+// @Param        to    query  int  true  "id da revisao de destino"
+// @Security BearerAuth
+// @Success      200  {object}  revision.RevisionDiff
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/revisions/diff [get]
+func (api *API) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	from, errFrom := strconv.ParseInt(r.URL.Query().Get("from"), 10, 32)
+	to, errTo := strconv.ParseInt(r.URL.Query().Get("to"), 10, 32)
+	if errFrom != nil || errTo != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "from and to must be revision ids",
+		})
+		return
+	}
+
+	diff, err := api.PublishService.DiffRevisions(r.Context(), handle, int32(from), int32(to))
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(diff)
+}
+
+// RestoreRevision godoc
+// @Summary      Restaura uma revisao do perfil
+// @Description  Volta o rascunho e a versao publica para a revisao escolhida. Links criados depois dela voltam a ser rascunho.
+// @Tags         revisions
+// @Produce      json
+// @Param        id  path  int  true  "id da revisao"
+// @Security BearerAuth
+// @Success      200  {object}  revision.GetRevision
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /profile/revisions/{id}/restore [post]
+func (api *API) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		writeRevisionError(w, revision_repository.ErrRevisionNotFound)
+		return
+	}
+
+	restored, err := api.PublishService.RestoreRevision(r.Context(), handle, int32(id))
+	if err != nil {
+		writeRevisionError(w, err)
+		return
+	}
+	api.renderShareImage(r, handle)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(restored)
+}
+
+func writeRevisionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, page_repository.ErrPageNotFound) || errors.Is(err, revision_repository.ErrRevisionNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, revision_repository.ErrRestoreConflict) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
+	})
+}
@@ -6,11 +6,12 @@ import (
 	"github.com/MarceloPetrucio/go-scalar-api-reference"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/theNixagen/linker/internal/domain/collaborator"
 )
 
 func (api *API) BindRoutes() {
 	r := api.Router
-	r.Use(middleware.Logger, middleware.AllowContentType("application/json", "multipart/form-data"), api.SetContentTypeMiddleware("application/json"))
+	r.Use(middleware.Logger, middleware.AllowContentType("application/json", "multipart/form-data"), api.SetContentTypeMiddleware("application/json"), api.CustomDomainMiddleware)
 
 	r.Handle("/docs/*", http.StripPrefix("/docs/", http.FileServer(http.Dir("./docs"))))
 
@@ -38,15 +39,70 @@ func (api *API) BindRoutes() {
 		r.Post("/refresh-session", api.RefreshSession)
 	})
 
+	r.Route("/pages", func(r chi.Router) {
+		r.Use(api.AuthMiddleware)
+		r.Get("/", api.ListPages)
+		r.Post("/", api.CreatePage)
+	})
+
+	r.With(api.AuthMiddleware).Post("/invitations/{token}/accept", api.AcceptInvitation)
+
 	r.Route("/profile", func(r chi.Router) {
 		r.Group(func(r chi.Router) {
-			r.Use(api.AuthMiddleware)
-			r.Put("/bio", api.UpdateBio)
-			r.Put("/photo", api.UploadProfilePicture)
-			r.Put("/banner", api.UploadBanner)
-			r.Post("/link", api.CreateNewLink)
+			r.Use(api.AuthMiddleware, api.PageMiddleware)
+			r.Get("/utm", api.GetDefaultUTM)
+			r.Get("/theme", api.GetTheme)
+			r.Get("/domains", api.ListCustomDomains)
+			r.Get("/preview", api.PreviewProfile)
+			r.Get("/revisions", api.ListRevisions)
+			r.Get("/revisions/diff", api.DiffRevisions)
+			r.Get("/collaborators", api.ListCollaborators)
+			r.Get("/audit", api.ListAuditLog)
+			r.Get("/media", api.ListMedia)
+			r.Get("/link/{id}/qr", api.GetLinkQRCode)
+			r.Get("/usage", api.GetUsage)
+
+			r.Group(func(r chi.Router) {
+				r.Use(api.RequireRole(collaborator.RoleEditor), api.AuditMiddleware)
+				r.Put("/bio", api.UpdateBio)
+				r.Put("/photo", api.UploadProfilePicture)
+				r.Put("/banner", api.UploadBanner)
+				r.Post("/uploads", api.CreateUpload)
+				r.Post("/uploads/{id}/complete", api.CompleteUpload)
+				r.Post("/link", api.CreateNewLink)
+				r.Put("/utm", api.UpdateDefaultUTM)
+				r.Put("/link/{id}/utm", api.UpdateLinkUTM)
+				r.Put("/theme", api.UpdateTheme)
+				r.Put("/theme/background", api.UploadThemeBackground)
+				r.Delete("/media/{id}", api.DeleteMedia)
+				r.Post("/publish", api.PublishProfile)
+				r.Post("/revisions/{id}/restore", api.RestoreRevision)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(api.RequireRole(), api.AuditMiddleware)
+				r.Put("/visibility", api.UpdateVisibility)
+				r.Post("/domains", api.AddCustomDomain)
+				r.Post("/domains/{id}/verify", api.VerifyCustomDomain)
+				r.Delete("/domains/{id}", api.DeleteCustomDomain)
+				r.Get("/collaborators/invitations", api.ListInvitations)
+				r.Post("/collaborators/invitations", api.InviteCollaborator)
+				r.Delete("/collaborators/{id}", api.RemoveCollaborator)
+			})
 		})
-		r.Get("/{username}", api.GetProfile)
-		r.Get("/links/{username}", api.GetUserLinks)
+		r.Get("/{handle}", api.GetProfile)
+		r.Get("/{handle}/qr", api.GetProfileQRCode)
+		r.Get("/{handle}/default-avatar", api.GetDefaultAvatar)
+		r.Get("/{handle}/default-banner", api.GetDefaultBanner)
+		r.Get("/links/{handle}", api.GetUserLinks)
+		r.Post("/link/{id}/unlock", api.UnlockLink)
 	})
+
+	r.Get(ImagePath+"/*", api.ServeImage)
+	r.Post(StoragePath, api.UploadStorageObject)
+
+	r.Get("/u/{handle}/{slug}", api.ResolveSlug)
+	r.Get("/s/{code}", api.ResolveShortCode)
+	r.Get("/{handle}/share.png", api.GetShareImage)
+	r.Get("/{handle}", api.RenderProfilePage)
 }
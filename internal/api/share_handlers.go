@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// GetShareImage godoc
+// @Summary      Imagem de compartilhamento do perfil
+// @Description  Retorna o card PNG usado como og:image da pagina publica do perfil. O card e gerado quando o perfil e publicado.
+// @Tags         profile
+// @Produce      png
+// @Param        handle  path      string  true  "handle"
+// @Success      200  {file}    file
+// @Success      304  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /{handle}/share.png [get]
+func (api *API) GetShareImage(w http.ResponseWriter, r *http.Request) {
+	api.serveShareImage(w, r, chi.URLParam(r, "handle"))
+}
+
+func (api *API) serveShareImage(w http.ResponseWriter, r *http.Request, handle string) {
+	key, err := api.ShareImageService.GetShareImage(r.Context(), handle)
+	if err != nil {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": "page not found",
+			})
+			return
+		}
+		if errors.Is(err, services.ErrShareImageNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": err.Error(),
+			})
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "could not generate share image",
+		})
+		return
+	}
+
+	etag := `"` + strings.TrimSuffix(path.Base(key), ".png") + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	object, _, err := api.FileService.GetObject(r.Context(), key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "could not load share image",
+		})
+		return
+	}
+	defer object.Close()
+
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, object); err != nil {
+		log.Printf("could not stream share image of %s: %v", handle, err)
+	}
+}
+
+// renderShareImage renders the share card after the published profile
+// changed. A failure only leaves the card missing until the next publish.
+func (api *API) renderShareImage(r *http.Request, handle string) {
+	if _, err := api.ShareImageService.RenderShareImage(r.Context(), handle); err != nil && !errors.Is(err, page_repository.ErrPageNotFound) {
+		log.Printf("could not render share image of %s: %v", handle, err)
+	}
+}
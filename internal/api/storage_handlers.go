@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/theNixagen/linker/internal/repositories/storage_repository"
+)
+
+// StoragePath is where the API accepts the presigned uploads of storage
+// backends that cannot accept them on their own.
+const StoragePath = "/storage"
+
+const (
+	// maxPolicyFieldSize bounds each form field read before the file.
+	maxPolicyFieldSize = 1 << 10
+	// maxPolicyFields bounds how many fields are read before the file.
+	// PresignPost signs five.
+	maxPolicyFields = 8
+)
+
+func (api *API) selfSignedStorage() (storage_repository.SelfSignedStorage, bool) {
+	storage, ok := api.FileService.Storage.(storage_repository.SelfSignedStorage)
+	return storage, ok
+}
+
+// UploadStorageObject accepts a multipart upload built from the fields of
+// PresignPost. The fields must come before the part named "file", whose
+// content must match the signed content type.
+func (api *API) UploadStorageObject(w http.ResponseWriter, r *http.Request) {
+	storage, ok := api.selfSignedStorage()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	fields := map[string]string{}
+	for read := 0; ; read++ {
+		part, err := reader.NextPart()
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if part.FormName() != "file" {
+			if read >= maxPolicyFields {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			value, err := io.ReadAll(io.LimitReader(part, maxPolicyFieldSize))
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fields[part.FormName()] = string(value)
+			continue
+		}
+
+		key, contentType, size, err := storage.VerifyPost(fields)
+		if err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, size+1))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if int64(len(data)) != size {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if http.DetectContentType(data) != contentType {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		if err := storage.Put(r.Context(), key, bytes.NewReader(data), size, contentType); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// GetTheme godoc
+// @Summary      Busca o tema do perfil autenticado
+// @Tags         profile
+// @Produce      json
+// @Security BearerAuth
+// @Success      200  {object}  theme.Theme
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/theme [get]
+func (api *API) GetTheme(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	t, err := api.ThemeService.GetTheme(r.Context(), handle)
+	if err != nil {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	api.resolveThemeImages(&t)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(t)
+}
+
+// UpdateTheme godoc
+// @Summary      Atualiza o tema do perfil autenticado
+// @Tags         profile
+// @Produce      json
+// @Accept       json
+// @Param        request  body  theme.Theme  true  "payload"
+// @Security BearerAuth
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  nil
+// @Failure      422  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/theme [put]
+func (api *API) UpdateTheme(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var t theme.Theme
+	if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := api.Validator.Struct(t); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if err := api.ThemeService.UpdateTheme(r.Context(), handle, t); err != nil {
+		writeThemeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UploadThemeBackground godoc
+// @Summary      Envia a imagem de fundo do tema
+// @Description  A imagem enviada entra na biblioteca de imagens. A imagem anterior continua na biblioteca e pode ser escolhida de novo ou apagada em DELETE /profile/media/{id}.
+// @Tags         profile
+// @Produce      json
+// @Accept       multipart/form-data
+// @Param        photo     formData  file  false  "Imagem (jpg/png/gif/webp)"
+// @Param        media_id  formData  int   false  "id de uma imagem da biblioteca, no lugar do arquivo"
+// @Security BearerAuth
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      415  {object}  map[string]string
+// @Failure      422  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Failure      503  {object}  map[string]string
+// @Router       /profile/theme/background [put]
+func (api *API) UploadThemeBackground(w http.ResponseWriter, r *http.Request) {
+	access, ok := getPageAccess(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	file, mediaID, err := api.imageSource(w, r)
+	if err != nil {
+		writeImageSourceError(w, err)
+		return
+	}
+
+	var key string
+	if file != nil {
+		defer file.Close()
+		key, err = api.ImageService.PutBackground(r.Context(), access.ID, file)
+	} else {
+		key, err = api.ImageService.MediaKey(r.Context(), access.ID, mediaID)
+	}
+	if err != nil {
+		writeMediaError(w, err)
+		return
+	}
+
+	if err := api.ImageService.SetBackground(r.Context(), access.Handle, key, api.ThemeService.SetBackgroundImage); err != nil {
+		writeThemeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *API) resolveThemeImages(t *theme.Theme) {
+	if t.Background.Image == "" {
+		return
+	}
+
+	t.Background.ImageURL = api.imageURL(t.Background.Image)
+}
+
+func writeThemeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, page_repository.ErrPageNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, theme.ErrUnsupportedVersion) || errors.Is(err, theme.ErrInvalidColor) ||
+		errors.Is(err, theme.ErrFontNotAllowed) || errors.Is(err, theme.ErrMissingImage) ||
+		errors.Is(err, theme.ErrLowContrast) || errors.Is(err, services.ErrBackgroundNotUploaded) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/upload"
+	"github.com/theNixagen/linker/internal/repositories/upload_repository"
+	"github.com/theNixagen/linker/internal/services"
+)
+
+// CreateUpload godoc
+// @Summary      Inicia um envio direto para o armazenamento
+// @Description  Retorna uma politica de POST assinada que aceita apenas o tamanho e o tipo declarados. O arquivo vai no campo "file", depois dos campos retornados, e o envio deve ser concluido em ate uma hora.
+// @Tags         profile
+// @Produce      json
+// @Accept       json
+// @Param        X-Page   header  string               false  "handle da pagina gerenciada"
+// @Param        request  body    upload.CreateUpload  true   "payload"
+// @Security BearerAuth
+// @Success      201  {object}  upload.PresignedUpload
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      422  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/uploads [post]
+func (api *API) CreateUpload(w http.ResponseWriter, r *http.Request) {
+	access, ok := getPageAccess(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req upload.CreateUpload
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	if err := api.Validator.Struct(req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+
+	presigned, err := api.UploadService.CreateUpload(r.Context(), access.ID, req)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(presigned)
+}
+
+// CompleteUpload godoc
+// @Summary      Conclui um envio direto
+// @Description  Confere tamanho, tipo e checksum do arquivo enviado e o define como foto de perfil ou banner. Responde 202 e a foto fica com estado "pending" ate o scanner de conteudo terminar. Envios cujas fotos geradas nao cabem na cota sao recusados com 413 antes disso.
+// @Tags         profile
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Param        id      path    int     true   "id do envio"
+// @Security BearerAuth
+// @Success      202  {object}  nil
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      410  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      415  {object}  map[string]string
+// @Failure      422  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Router       /profile/uploads/{id}/complete [post]
+func (api *API) CompleteUpload(w http.ResponseWriter, r *http.Request) {
+	access, ok := getPageAccess(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": upload_repository.ErrUploadNotFound.Error(),
+		})
+		return
+	}
+
+	if err := api.UploadService.CompleteUpload(r.Context(), access.ID, access.Handle, int32(id)); err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// GetUsage godoc
+// @Summary      Mostra o uso de armazenamento da conta
+// @Description  Soma, em todas as paginas da conta, a biblioteca de imagens com as fotos e banners feitos a partir dela, o espaco reservado pelos envios pendentes (o tamanho declarado e, depois de aceitos, o que vao armazenar) e os arquivos em quarentena. Envios que ultrapassariam a cota ou o tamanho maximo por arquivo sao recusados com 413.
+// @Tags         profile
+// @Produce      json
+// @Param        X-Page  header  string  false  "handle da pagina gerenciada"
+// @Security BearerAuth
+// @Success      200  {object}  upload.Usage
+// @Failure      401  {object}  nil
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  nil
+// @Router       /profile/usage [get]
+func (api *API) GetUsage(w http.ResponseWriter, r *http.Request) {
+	access, ok := getPageAccess(r.Context())
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	usage, err := api.UploadService.Usage(r.Context(), access.ID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(usage)
+}
+
+func writeUploadError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, upload_repository.ErrUploadNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, services.ErrUploadIncomplete):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, services.ErrUploadExpired):
+		w.WriteHeader(http.StatusGone)
+	case errors.Is(err, services.ErrUploadMismatch):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	default:
+		writeImageError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": err.Error(),
+	})
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/theNixagen/linker/internal/domain/utm"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+// GetDefaultUTM godoc
+// @Summary      Busca os parametros UTM padrao do perfil
+// @Tags         profile
+// @Produce      json
+// @Security BearerAuth
+// @Success      200  {object}  utm.UTM
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/utm [get]
+func (api *API) GetDefaultUTM(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	tags, err := api.LinksService.GetDefaultUTM(r.Context(), handle)
+	if err != nil {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tags)
+}
+
+// UpdateDefaultUTM godoc
+// @Summary      Atualiza os parametros UTM padrao do perfil
+// @Description  A alteracao fica no rascunho; os visitantes so recebem os novos parametros depois de publicar.
+// @Tags         profile
+// @Produce      json
+// @Accept       json
+// @Param        request  body  utm.UTM  true  "payload"
+// @Security BearerAuth
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  nil
+// @Failure      422  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/utm [put]
+func (api *API) UpdateDefaultUTM(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	tags, ok := api.decodeUTM(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.LinksService.UpdateDefaultUTM(r.Context(), handle, tags); err != nil {
+		api.writeUTMError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateLinkUTM godoc
+// @Summary      Sobrescreve os parametros UTM de um link
+// @Description  A alteracao fica no rascunho; os visitantes so recebem os novos parametros depois de publicar.
+// @Tags         profile
+// @Produce      json
+// @Accept       json
+// @Param        id       path  int      true  "id do link"
+// @Param        request  body  utm.UTM  true  "payload"
+// @Security BearerAuth
+// @Success      204  {object}  nil
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  nil
+// @Failure      404  {object}  nil
+// @Failure      422  {object}  nil
+// @Failure      500  {object}  nil
+// @Router       /profile/link/{id}/utm [put]
+func (api *API) UpdateLinkUTM(w http.ResponseWriter, r *http.Request) {
+	handle, ok := GetPageHandle(r.Context())
+
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	tags, ok := api.decodeUTM(w, r)
+	if !ok {
+		return
+	}
+
+	if err := api.LinksService.UpdateLinkUTM(r.Context(), handle, int32(id), tags); err != nil {
+		api.writeUTMError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (api *API) decodeUTM(w http.ResponseWriter, r *http.Request) (utm.UTM, bool) {
+	var tags utm.UTM
+
+	if err := json.NewDecoder(r.Body).Decode(&tags); err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return utm.UTM{}, false
+	}
+
+	if err := api.Validator.Struct(tags); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return utm.UTM{}, false
+	}
+
+	return tags, true
+}
+
+func (api *API) writeUTMError(w http.ResponseWriter, err error) {
+	if errors.Is(err, utm.ErrInvalidParam) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, page_repository.ErrPageNotFound) || errors.Is(err, links_repository.ErrLinkNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusInternalServerError)
+}
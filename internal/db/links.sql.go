@@ -7,43 +7,71 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createLink = `-- name: CreateLink :exec
+const createLink = `-- name: CreateLink :one
 INSERT INTO links(
-  user_id,
+  page_id,
 	url,
 	title,
 	description,
+	slug,
+	short_code,
+	platform,
+	handle,
+	sensitive,
+	password_hash,
+	thumbnail_media_id,
+	thumbnail,
 	created_at
 ) values(
-  $1,$2,$3,$4,NOW()
-)
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW()
+) RETURNING id
 `
 
 type CreateLinkParams struct {
-	UserID      int32
-	Url         string
-	Title       string
-	Description string
+	PageID           int32
+	Url              string
+	Title            string
+	Description      string
+	Slug             pgtype.Text
+	ShortCode        string
+	Platform         string
+	Handle           string
+	Sensitive        bool
+	PasswordHash     string
+	ThumbnailMediaID pgtype.Int4
+	Thumbnail        string
 }
 
-func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) error {
-	_, err := q.db.Exec(ctx, createLink,
-		arg.UserID,
+func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createLink,
+		arg.PageID,
 		arg.Url,
 		arg.Title,
 		arg.Description,
+		arg.Slug,
+		arg.ShortCode,
+		arg.Platform,
+		arg.Handle,
+		arg.Sensitive,
+		arg.PasswordHash,
+		arg.ThumbnailMediaID,
+		arg.Thumbnail,
 	)
-	return err
+	var id int32
+	err := row.Scan(&id)
+	return id, err
 }
 
-const findAllLinksFromAUser = `-- name: FindAllLinksFromAUser :many
-SELECT id, user_id, url, title, description, created_at FROM links where user_id = $1
+const findAllLinksFromAPage = `-- name: FindAllLinksFromAPage :many
+SELECT id, page_id, url, title, description, created_at, slug, short_code, platform, handle, embed, embed_fetched_at, sensitive, password_hash, utm, published, published_utm, thumbnail_media_id, thumbnail FROM links where page_id = $1
 `
 
-func (q *Queries) FindAllLinksFromAUser(ctx context.Context, userID int32) ([]Link, error) {
-	rows, err := q.db.Query(ctx, findAllLinksFromAUser, userID)
+func (q *Queries) FindAllLinksFromAPage(ctx context.Context, pageID int32) ([]Link, error) {
+	rows, err := q.db.Query(ctx, findAllLinksFromAPage, pageID)
 	if err != nil {
 		return nil, err
 	}
@@ -53,11 +81,24 @@ func (q *Queries) FindAllLinksFromAUser(ctx context.Context, userID int32) ([]Li
 		var i Link
 		if err := rows.Scan(
 			&i.ID,
-			&i.UserID,
+			&i.PageID,
 			&i.Url,
 			&i.Title,
 			&i.Description,
 			&i.CreatedAt,
+			&i.Slug,
+			&i.ShortCode,
+			&i.Platform,
+			&i.Handle,
+			&i.Embed,
+			&i.EmbedFetchedAt,
+			&i.Sensitive,
+			&i.PasswordHash,
+			&i.Utm,
+			&i.Published,
+			&i.PublishedUtm,
+			&i.ThumbnailMediaID,
+			&i.Thumbnail,
 		); err != nil {
 			return nil, err
 		}
@@ -68,3 +109,313 @@ func (q *Queries) FindAllLinksFromAUser(ctx context.Context, userID int32) ([]Li
 	}
 	return items, nil
 }
+
+const findLinkByID = `-- name: FindLinkByID :one
+SELECT id, page_id, url, title, description, created_at, slug, short_code, platform, handle, embed, embed_fetched_at, sensitive, password_hash, utm, published, published_utm, thumbnail_media_id, thumbnail FROM links where id = $1
+`
+
+func (q *Queries) FindLinkByID(ctx context.Context, id int32) (Link, error) {
+	row := q.db.QueryRow(ctx, findLinkByID, id)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.PageID,
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Slug,
+		&i.ShortCode,
+		&i.Platform,
+		&i.Handle,
+		&i.Embed,
+		&i.EmbedFetchedAt,
+		&i.Sensitive,
+		&i.PasswordHash,
+		&i.Utm,
+		&i.Published,
+		&i.PublishedUtm,
+		&i.ThumbnailMediaID,
+		&i.Thumbnail,
+	)
+	return i, err
+}
+
+const findLinkByShortCode = `-- name: FindLinkByShortCode :one
+SELECT id, page_id, url, title, description, created_at, slug, short_code, platform, handle, embed, embed_fetched_at, sensitive, password_hash, utm, published, published_utm, thumbnail_media_id, thumbnail FROM links where short_code = $1
+`
+
+func (q *Queries) FindLinkByShortCode(ctx context.Context, shortCode string) (Link, error) {
+	row := q.db.QueryRow(ctx, findLinkByShortCode, shortCode)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.PageID,
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Slug,
+		&i.ShortCode,
+		&i.Platform,
+		&i.Handle,
+		&i.Embed,
+		&i.EmbedFetchedAt,
+		&i.Sensitive,
+		&i.PasswordHash,
+		&i.Utm,
+		&i.Published,
+		&i.PublishedUtm,
+		&i.ThumbnailMediaID,
+		&i.Thumbnail,
+	)
+	return i, err
+}
+
+const findLinkBySlug = `-- name: FindLinkBySlug :one
+SELECT id, page_id, url, title, description, created_at, slug, short_code, platform, handle, embed, embed_fetched_at, sensitive, password_hash, utm, published, published_utm, thumbnail_media_id, thumbnail FROM links where page_id = $1 and slug = $2
+`
+
+type FindLinkBySlugParams struct {
+	PageID int32
+	Slug   pgtype.Text
+}
+
+func (q *Queries) FindLinkBySlug(ctx context.Context, arg FindLinkBySlugParams) (Link, error) {
+	row := q.db.QueryRow(ctx, findLinkBySlug, arg.PageID, arg.Slug)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.PageID,
+		&i.Url,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.Slug,
+		&i.ShortCode,
+		&i.Platform,
+		&i.Handle,
+		&i.Embed,
+		&i.EmbedFetchedAt,
+		&i.Sensitive,
+		&i.PasswordHash,
+		&i.Utm,
+		&i.Published,
+		&i.PublishedUtm,
+		&i.ThumbnailMediaID,
+		&i.Thumbnail,
+	)
+	return i, err
+}
+
+const findPublishedLinksFromAPage = `-- name: FindPublishedLinksFromAPage :many
+SELECT id, page_id, url, title, description, created_at, slug, short_code, platform, handle, embed, embed_fetched_at, sensitive, password_hash, utm, published, published_utm, thumbnail_media_id, thumbnail FROM links where page_id = $1 and published = TRUE
+`
+
+func (q *Queries) FindPublishedLinksFromAPage(ctx context.Context, pageID int32) ([]Link, error) {
+	rows, err := q.db.Query(ctx, findPublishedLinksFromAPage, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Link
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.PageID,
+			&i.Url,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.Slug,
+			&i.ShortCode,
+			&i.Platform,
+			&i.Handle,
+			&i.Embed,
+			&i.EmbedFetchedAt,
+			&i.Sensitive,
+			&i.PasswordHash,
+			&i.Utm,
+			&i.Published,
+			&i.PublishedUtm,
+			&i.ThumbnailMediaID,
+			&i.Thumbnail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStaleEmbedLinks = `-- name: ListStaleEmbedLinks :many
+SELECT id, url, embed FROM links
+WHERE platform = '' AND (embed_fetched_at IS NULL OR embed_fetched_at < $1)
+ORDER BY embed_fetched_at NULLS FIRST, id
+LIMIT $2
+`
+
+type ListStaleEmbedLinksParams struct {
+	EmbedFetchedAt pgtype.Timestamp
+	Limit          int32
+}
+
+type ListStaleEmbedLinksRow struct {
+	ID    int32
+	Url   string
+	Embed []byte
+}
+
+func (q *Queries) ListStaleEmbedLinks(ctx context.Context, arg ListStaleEmbedLinksParams) ([]ListStaleEmbedLinksRow, error) {
+	rows, err := q.db.Query(ctx, listStaleEmbedLinks, arg.EmbedFetchedAt, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStaleEmbedLinksRow
+	for rows.Next() {
+		var i ListStaleEmbedLinksRow
+		if err := rows.Scan(&i.ID, &i.Url, &i.Embed); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const publishLinks = `-- name: PublishLinks :exec
+UPDATE links set published = TRUE, published_utm = utm where page_id = $1
+`
+
+func (q *Queries) PublishLinks(ctx context.Context, pageID int32) error {
+	_, err := q.db.Exec(ctx, publishLinks, pageID)
+	return err
+}
+
+const restoreLink = `-- name: RestoreLink :exec
+INSERT INTO links(
+  id,
+  page_id,
+  url,
+  title,
+  description,
+  slug,
+  short_code,
+  platform,
+  handle,
+  sensitive,
+  password_hash,
+  utm,
+  published_utm,
+  published,
+  created_at,
+  thumbnail_media_id,
+  thumbnail
+) values(
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$12,TRUE,$13,$14,$15
+) ON CONFLICT (id) DO UPDATE SET
+  url = EXCLUDED.url,
+  title = EXCLUDED.title,
+  description = EXCLUDED.description,
+  slug = EXCLUDED.slug,
+  platform = EXCLUDED.platform,
+  handle = EXCLUDED.handle,
+  sensitive = EXCLUDED.sensitive,
+  password_hash = EXCLUDED.password_hash,
+  utm = EXCLUDED.utm,
+  published_utm = EXCLUDED.published_utm,
+  published = TRUE,
+  thumbnail_media_id = EXCLUDED.thumbnail_media_id,
+  thumbnail = EXCLUDED.thumbnail,
+  embed = CASE WHEN links.url = EXCLUDED.url THEN links.embed END,
+  embed_fetched_at = CASE WHEN links.url = EXCLUDED.url THEN links.embed_fetched_at END
+WHERE links.page_id = EXCLUDED.page_id
+`
+
+type RestoreLinkParams struct {
+	ID               int32
+	PageID           int32
+	Url              string
+	Title            string
+	Description      string
+	Slug             pgtype.Text
+	ShortCode        string
+	Platform         string
+	Handle           string
+	Sensitive        bool
+	PasswordHash     string
+	Utm              []byte
+	CreatedAt        pgtype.Timestamp
+	ThumbnailMediaID pgtype.Int4
+	Thumbnail        string
+}
+
+func (q *Queries) RestoreLink(ctx context.Context, arg RestoreLinkParams) error {
+	_, err := q.db.Exec(ctx, restoreLink,
+		arg.ID,
+		arg.PageID,
+		arg.Url,
+		arg.Title,
+		arg.Description,
+		arg.Slug,
+		arg.ShortCode,
+		arg.Platform,
+		arg.Handle,
+		arg.Sensitive,
+		arg.PasswordHash,
+		arg.Utm,
+		arg.CreatedAt,
+		arg.ThumbnailMediaID,
+		arg.Thumbnail,
+	)
+	return err
+}
+
+const unpublishLinks = `-- name: UnpublishLinks :exec
+UPDATE links set published = FALSE where page_id = $1
+`
+
+func (q *Queries) UnpublishLinks(ctx context.Context, pageID int32) error {
+	_, err := q.db.Exec(ctx, unpublishLinks, pageID)
+	return err
+}
+
+const updateLinkEmbed = `-- name: UpdateLinkEmbed :exec
+UPDATE links set embed = $1, embed_fetched_at = NOW() where id = $2 and url = $3
+`
+
+type UpdateLinkEmbedParams struct {
+	Embed []byte
+	ID    int32
+	Url   string
+}
+
+func (q *Queries) UpdateLinkEmbed(ctx context.Context, arg UpdateLinkEmbedParams) error {
+	_, err := q.db.Exec(ctx, updateLinkEmbed, arg.Embed, arg.ID, arg.Url)
+	return err
+}
+
+const updateLinkUTM = `-- name: UpdateLinkUTM :execrows
+UPDATE links set utm = $1 where id = $2 and page_id = $3
+`
+
+type UpdateLinkUTMParams struct {
+	Utm    []byte
+	ID     int32
+	PageID int32
+}
+
+func (q *Queries) UpdateLinkUTM(ctx context.Context, arg UpdateLinkUTMParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateLinkUTM, arg.Utm, arg.ID, arg.PageID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -8,23 +8,149 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AuditLog struct {
+	ID        int32
+	PageID    int32
+	AccountID pgtype.Int4
+	Action    string
+	Details   []byte
+	CreatedAt pgtype.Timestamp
+}
+
+type Click struct {
+	ID        int32
+	PageID    int32
+	LinkID    pgtype.Int4
+	Source    string
+	CreatedAt pgtype.Timestamp
+}
+
+type CustomDomain struct {
+	ID                int32
+	PageID            int32
+	Domain            string
+	VerificationToken string
+	Status            string
+	FailureReason     string
+	LastCheckedAt     pgtype.Timestamp
+	VerifiedAt        pgtype.Timestamp
+	CreatedAt         pgtype.Timestamp
+}
+
 type Link struct {
+	ID               int32
+	PageID           int32
+	Url              string
+	Title            string
+	Description      string
+	CreatedAt        pgtype.Timestamp
+	Slug             pgtype.Text
+	ShortCode        string
+	Platform         string
+	Handle           string
+	Embed            []byte
+	EmbedFetchedAt   pgtype.Timestamp
+	Sensitive        bool
+	PasswordHash     string
+	Utm              []byte
+	Published        bool
+	PublishedUtm     []byte
+	ThumbnailMediaID pgtype.Int4
+	Thumbnail        string
+}
+
+type Medium struct {
 	ID          int32
-	UserID      int32
-	Url         string
-	Title       string
-	Description string
+	PageID      int32
+	ObjectKey   string
+	ContentType string
+	Width       int32
+	Height      int32
+	Size        int64
+	AvatarSize  int64
+	BannerSize  int64
 	CreatedAt   pgtype.Timestamp
 }
 
-type User struct {
-	ID             int32
-	Email          string
-	Password       string
-	CreatedAt      pgtype.Timestamp
-	ProfilePicture string
+type Page struct {
+	ID                  int32
+	AccountID           int32
+	Handle              string
+	Name                string
+	Bio                 string
+	ProfilePicture      string
+	BannerPicture       string
+	Utm                 []byte
+	Visibility          string
+	CreatedAt           pgtype.Timestamp
+	HandleSkeleton      pgtype.Text
+	ProfilePictureState string
+	BannerPictureState  string
+}
+
+type PageCollaborator struct {
+	PageID    int32
+	AccountID int32
+	Role      string
+	CreatedAt pgtype.Timestamp
+}
+
+type PageInvitation struct {
+	ID        int32
+	PageID    int32
+	Email     string
+	Role      string
+	TokenHash string
+	InvitedBy pgtype.Int4
+	ExpiresAt pgtype.Timestamp
+	CreatedAt pgtype.Timestamp
+}
+
+type ProfileRevision struct {
+	ID        int32
+	PageID    int32
+	Reason    string
+	Snapshot  []byte
+	CreatedAt pgtype.Timestamp
+}
+
+type ProfileTheme struct {
+	PageID    int32
+	Version   int32
+	Theme     []byte
+	UpdatedAt pgtype.Timestamp
+}
+
+type PublishedProfile struct {
+	PageID         int32
+	Name           string
 	Bio            string
+	ProfilePicture string
 	BannerPicture  string
-	Name           pgtype.Text
-	Username       pgtype.Text
+	Theme          []byte
+	Utm            []byte
+	PublishedAt    pgtype.Timestamp
+}
+
+type Upload struct {
+	ID              int32
+	PageID          int32
+	Kind            string
+	ObjectKey       string
+	ContentType     string
+	Size            int64
+	Checksum        string
+	ExpiresAt       pgtype.Timestamp
+	CreatedAt       pgtype.Timestamp
+	ProcessingSince pgtype.Timestamp
+	ReservedSize    int64
+}
+
+type User struct {
+	ID        int32
+	Email     string
+	Password  string
+	CreatedAt pgtype.Timestamp
+	Name      pgtype.Text
+	Username  pgtype.Text
 }
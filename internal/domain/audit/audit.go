@@ -0,0 +1,12 @@
+package audit
+
+import "time"
+
+type Entry struct {
+	ID        int32             `json:"id"`
+	AccountID int32             `json:"account_id,omitempty"`
+	Email     string            `json:"email,omitempty"`
+	Action    string            `json:"action"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
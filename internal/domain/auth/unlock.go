@@ -0,0 +1,9 @@
+package auth
+
+import "github.com/golang-jwt/jwt/v5"
+
+type UnlockClaims struct {
+	LinkID int32  `json:"lid"`
+	Typ    string `json:"typ"`
+	jwt.RegisteredClaims
+}
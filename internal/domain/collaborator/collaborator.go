@@ -0,0 +1,50 @@
+package collaborator
+
+import "time"
+
+const (
+	RoleOwner   = "owner"
+	RoleEditor  = "editor"
+	RoleAnalyst = "analyst"
+)
+
+// Allows reports whether role is one of the allowed roles. Owners are always
+// allowed.
+func Allows(role string, allowed ...string) bool {
+	if role == RoleOwner {
+		return true
+	}
+	for _, a := range allowed {
+		if role == a {
+			return true
+		}
+	}
+	return false
+}
+
+type InviteCollaborator struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=editor analyst"`
+}
+
+type GetCollaborator struct {
+	AccountID int32     `json:"account_id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetInvitation struct {
+	ID        int32     `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Membership is the page an account joined by accepting an invitation.
+type Membership struct {
+	Handle string `json:"handle"`
+	Role   string `json:"role"`
+}
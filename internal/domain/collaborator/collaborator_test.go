@@ -0,0 +1,23 @@
+package collaborator
+
+import "testing"
+
+func TestAllows(t *testing.T) {
+	tests := []struct {
+		role    string
+		allowed []string
+		want    bool
+	}{
+		{RoleOwner, nil, true},
+		{RoleEditor, []string{RoleEditor}, true},
+		{RoleAnalyst, []string{RoleEditor, RoleAnalyst}, true},
+		{RoleAnalyst, []string{RoleEditor}, false},
+		{"", []string{RoleEditor}, false},
+	}
+
+	for _, tt := range tests {
+		if got := Allows(tt.role, tt.allowed...); got != tt.want {
+			t.Errorf("Allows(%q, %v) = %v, want %v", tt.role, tt.allowed, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package customdomain
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusVerified = "verified"
+	StatusFailed   = "failed"
+
+	// VerificationPrefix is the label where users publish the TXT record.
+	VerificationPrefix = "_linker-verification"
+	verificationValue  = "linker-verification="
+)
+
+var (
+	ErrInvalidDomain  = errors.New("domain must be a hostname like links.example.com")
+	ErrReservedDomain = errors.New("domain belongs to linker and cannot be used")
+)
+
+type CreateCustomDomain struct {
+	Domain string `json:"domain" validate:"required,max=253"`
+}
+
+type VerificationRecord struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type GetCustomDomain struct {
+	ID            int32              `json:"id"`
+	Domain        string             `json:"domain"`
+	Status        string             `json:"status"`
+	FailureReason string             `json:"failure_reason,omitempty"`
+	Record        VerificationRecord `json:"verification_record"`
+	LastCheckedAt *time.Time         `json:"last_checked_at,omitempty"`
+	VerifiedAt    *time.Time         `json:"verified_at,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// Normalize lowercases the domain and strips a trailing dot. Only plain
+// hostnames with at least two labels are accepted, IP addresses are not.
+func Normalize(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if len(domain) == 0 || len(domain) > 253 || net.ParseIP(domain) != nil {
+		return "", ErrInvalidDomain
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", ErrInvalidDomain
+	}
+
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", ErrInvalidDomain
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return "", ErrInvalidDomain
+			}
+		}
+	}
+
+	return domain, nil
+}
+
+// IsSubdomainOf reports whether domain is host itself or below it.
+func IsSubdomainOf(domain, host string) bool {
+	return domain == host || strings.HasSuffix(domain, "."+host)
+}
+
+func NewVerificationRecord(domain, token string) VerificationRecord {
+	return VerificationRecord{
+		Type:  "TXT",
+		Name:  VerificationPrefix + "." + domain,
+		Value: verificationValue + token,
+	}
+}
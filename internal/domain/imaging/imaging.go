@@ -0,0 +1,308 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"path"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxPixels and MaxDimension are checked against the image header before
+	// decoding, so a tiny file that expands to gigabytes is never decoded.
+	// 24 MP covers phone cameras and keeps a decoded RGBA frame under 100 MB.
+	MaxPixels    = 24_000_000
+	MaxDimension = 10_000
+
+	// Extension is the file extension of every stored variant.
+	Extension   = ".jpg"
+	ContentType = "image/jpeg"
+
+	jpegQuality = 85
+)
+
+var (
+	ErrUnsupportedType = errors.New("file is not a supported image")
+	ErrTooManyPixels   = errors.New("image dimensions are too large")
+)
+
+var allowedTypes = map[string]bool{
+	"image/gif":  true,
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// Variant is one resized copy produced for every upload. The last variant
+// of a list is the largest one and is the key stored on the page.
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+}
+
+var (
+	AvatarVariants = []Variant{
+		{Name: "sm", Width: 64, Height: 64},
+		{Name: "md", Width: 256, Height: 256},
+		{Name: "lg", Width: 1024, Height: 1024},
+	}
+	BannerVariants = []Variant{
+		{Name: "sm", Width: 600, Height: 200},
+		{Name: "md", Width: 1500, Height: 500},
+		{Name: "lg", Width: 3000, Height: 1000},
+	}
+)
+
+// ProxyWidths are the widths the image endpoint resizes to on request. Any
+// other width is refused so the endpoint cannot be used to fill caches
+// with arbitrary sizes.
+var ProxyWidths = []int{64, 128, 256, 512, 1024}
+
+func AllowedWidth(width int) bool {
+	for _, w := range ProxyWidths {
+		if w == width {
+			return true
+		}
+	}
+	return false
+}
+
+// Sniff returns the MIME type detected from the content of data, ignoring
+// whatever the client declared.
+func Sniff(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if !allowedTypes[contentType] {
+		return "", ErrUnsupportedType
+	}
+	return contentType, nil
+}
+
+// Decode validates and decodes an uploaded image and applies its EXIF
+// orientation. Metadata is not carried over, so encoding the result strips
+// EXIF and GPS tags.
+func Decode(data []byte) (image.Image, error) {
+	contentType, err := Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > MaxDimension || config.Height > MaxDimension || config.Width*config.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+
+	if contentType == "image/jpeg" {
+		img = Orient(img, Orientation(data))
+	}
+	return img, nil
+}
+
+// Fill center-crops src to the aspect ratio of v and scales it down to the
+// variant size. Images smaller than the variant are cropped but never
+// upscaled.
+func Fill(src image.Image, v Variant) image.Image {
+	b := src.Bounds()
+	crop := b
+	if b.Dx()*v.Height > b.Dy()*v.Width {
+		w := b.Dy() * v.Width / v.Height
+		crop.Min.X = b.Min.X + (b.Dx()-w)/2
+		crop.Max.X = crop.Min.X + w
+	} else {
+		h := b.Dx() * v.Height / v.Width
+		crop.Min.Y = b.Min.Y + (b.Dy()-h)/2
+		crop.Max.Y = crop.Min.Y + h
+	}
+
+	width, height := v.Width, v.Height
+	if crop.Dx() < width {
+		width, height = crop.Dx(), crop.Dy()
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, max(width, 1), max(height, 1)))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// FitWidth scales src down to width, keeping its aspect ratio. Narrower
+// images are returned as they are.
+func FitWidth(src image.Image, width int) image.Image {
+	b := src.Bounds()
+	if b.Dx() <= width {
+		return src
+	}
+
+	height := max(b.Dy()*width/b.Dx(), 1)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+// EncodeJPEG flattens transparent pixels onto white and encodes img.
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	canvas := image.NewRGBA(img.Bounds())
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(canvas, canvas.Bounds(), img, img.Bounds().Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// VariantKey returns the key of variant v stored under base.
+func VariantKey(base string, v Variant) string {
+	return path.Join(base, v.Name+Extension)
+}
+
+// VariantKeys maps each variant name to its key, given the key stored on the
+// page. It returns nil for keys that were not produced by the pipeline, such
+// as uploads made before variants existed.
+func VariantKeys(key string, variants []Variant) map[string]string {
+	if len(variants) == 0 || path.Base(key) != variants[len(variants)-1].Name+Extension {
+		return nil
+	}
+
+	base := path.Dir(key)
+	keys := make(map[string]string, len(variants))
+	for _, v := range variants {
+		keys[v.Name] = VariantKey(base, v)
+	}
+	return keys
+}
+
+// Orientation reads the EXIF orientation tag of a JPEG file. It returns 1,
+// the identity, when the tag is missing or unreadable.
+func Orientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// Start of scan, no metadata follows.
+		if marker == 0xDA {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		if marker == 0xE1 {
+			if o, ok := exifOrientation(data[i+4 : end]); ok {
+				return o
+			}
+		}
+		i = end
+	}
+	return 1
+}
+
+func exifOrientation(segment []byte) (int, bool) {
+	if len(segment) < 14 || string(segment[:6]) != "Exif\x00\x00" {
+		return 0, false
+	}
+	tiff := segment[6:]
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0, false
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 0, false
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 0, false
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			o := int(order.Uint16(tiff[entry+8:]))
+			if o < 1 || o > 8 {
+				return 0, false
+			}
+			return o, true
+		}
+	}
+	return 0, false
+}
+
+// Orient rotates and flips img according to an EXIF orientation value so
+// it displays upright without the tag. Pixels are copied directly between
+// RGBA buffers instead of going through At and Set.
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	src, ok := img.(*image.RGBA)
+	if !ok {
+		b := img.Bounds()
+		src = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(src, src.Rect, img, b.Min, draw.Src)
+	}
+
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		row := dst.Pix[y*dst.Stride : y*dst.Stride+dw*4]
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			i := src.PixOffset(src.Rect.Min.X+sx, src.Rect.Min.Y+sy)
+			copy(row[x*4:x*4+4], src.Pix[i:i+4])
+		}
+	}
+	return dst
+}
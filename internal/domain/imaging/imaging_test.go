@@ -0,0 +1,143 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"testing"
+)
+
+func encodeJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	img.Set(0, 0, color.RGBA{R: 0xff, A: 0xff})
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return buf.Bytes()
+}
+
+// withOrientation inserts a big endian EXIF segment right after the SOI marker.
+func withOrientation(data []byte, orientation byte) []byte {
+	exif := []byte{
+		'E', 'x', 'i', 'f', 0, 0,
+		'M', 'M', 0, 42, 0, 0, 0, 8,
+		0, 1,
+		0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0,
+		0, 0, 0, 0,
+	}
+	segment := append([]byte{0xFF, 0xE1, 0, byte(len(exif) + 2)}, exif...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestSniff(t *testing.T) {
+	if _, err := Sniff([]byte("<html><body>hi</body></html>")); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType for html, got %v", err)
+	}
+
+	contentType, err := Sniff(encodeJPEG(t, 2, 2))
+	if err != nil || contentType != "image/jpeg" {
+		t.Errorf("expected image/jpeg, got %q (%v)", contentType, err)
+	}
+}
+
+func TestDecode_RejectsDecompressionBomb(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, image.NewPaletted(image.Rect(0, 0, 1, 1), []color.Color{color.Black}), nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	data := buf.Bytes()
+	// Logical screen width and height of the GIF header.
+	data[6], data[7], data[8], data[9] = 0xFF, 0xFF, 0xFF, 0xFF
+
+	if _, err := Decode(data); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("expected ErrTooManyPixels, got %v", err)
+	}
+}
+
+func TestDecode_AppliesOrientation(t *testing.T) {
+	data := withOrientation(encodeJPEG(t, 40, 20), 6)
+
+	if got := Orientation(data); got != 6 {
+		t.Fatalf("expected orientation 6, got %d", got)
+	}
+
+	img, err := Decode(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 40 {
+		t.Errorf("expected 20x40 after rotation, got %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	red := color.RGBA{R: 0xff, A: 0xff}
+	src.Set(0, 0, red)
+
+	tests := []struct {
+		orientation int
+		x, y        int
+	}{
+		{2, 2, 0},
+		{3, 2, 1},
+		{4, 0, 1},
+		{5, 0, 0},
+		{6, 1, 0},
+		{7, 1, 2},
+		{8, 0, 2},
+	}
+
+	for _, tt := range tests {
+		got := Orient(src, tt.orientation)
+		if got.At(tt.x, tt.y) != color.Color(red) {
+			t.Errorf("orientation %d: expected top-left pixel at %d,%d", tt.orientation, tt.x, tt.y)
+		}
+	}
+}
+
+func TestFill(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2000, 1000))
+
+	if b := Fill(src, Variant{Name: "md", Width: 256, Height: 256}).Bounds(); b.Dx() != 256 || b.Dy() != 256 {
+		t.Errorf("expected 256x256, got %dx%d", b.Dx(), b.Dy())
+	}
+
+	if b := Fill(src, Variant{Name: "lg", Width: 3000, Height: 1000}).Bounds(); b.Dx() != 2000 || b.Dy() != 666 {
+		t.Errorf("expected no upscaling, got %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+func TestVariantKeys(t *testing.T) {
+	keys := VariantKeys("images/abc/lg.jpg", AvatarVariants)
+	if keys["sm"] != "images/abc/sm.jpg" || keys["lg"] != "images/abc/lg.jpg" {
+		t.Errorf("unexpected variant keys %v", keys)
+	}
+
+	if keys := VariantKeys("avatar.png", AvatarVariants); keys != nil {
+		t.Errorf("expected no variants for legacy keys, got %v", keys)
+	}
+}
+
+func TestFitWidth(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+
+	if b := FitWidth(src, 256).Bounds(); b.Dx() != 256 || b.Dy() != 128 {
+		t.Errorf("expected 256x128, got %dx%d", b.Dx(), b.Dy())
+	}
+	if b := FitWidth(src, 2000).Bounds(); b.Dx() != 1000 {
+		t.Errorf("expected no upscaling, got %dx%d", b.Dx(), b.Dy())
+	}
+	if AllowedWidth(300) || !AllowedWidth(256) {
+		t.Errorf("unexpected width allowlist")
+	}
+}
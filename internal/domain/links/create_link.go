@@ -1,7 +1,15 @@
 package links
 
 type CreateLink struct {
-	URL         string `json:"url" validate:"required,url"`
-	Title       string `json:"title" validate:"required"`
+	URL         string `json:"url" validate:"required_without=Handle,omitempty,url"`
+	Title       string `json:"title" validate:"required_without=Platform"`
 	Description string `json:"description"`
+	Slug        string `json:"slug" validate:"omitempty,max=64"`
+	Platform    string `json:"platform" validate:"required_with=Handle"`
+	Handle      string `json:"handle" validate:"omitempty,max=255"`
+	Sensitive   bool   `json:"sensitive"`
+	Password    string `json:"password" validate:"omitempty,min=8,max=72"`
+	// ThumbnailMediaID picks an image of the media library to show next to
+	// the link.
+	ThumbnailMediaID int32 `json:"thumbnail_media_id" validate:"omitempty,min=1"`
 }
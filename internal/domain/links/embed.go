@@ -0,0 +1,11 @@
+package links
+
+type Embed struct {
+	Type         string `json:"type"`
+	Provider     string `json:"provider"`
+	Title        string `json:"title,omitempty"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
@@ -0,0 +1,42 @@
+package links
+
+import "time"
+
+type GetLink struct {
+	ID          int32     `json:"id"`
+	URL         string    `json:"url,omitempty"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Slug        string    `json:"slug,omitempty"`
+	ShortCode   string    `json:"short_code"`
+	Platform    string    `json:"platform,omitempty"`
+	Handle      string    `json:"handle,omitempty"`
+	Embed       *Embed    `json:"embed,omitempty"`
+	Thumbnail   string    `json:"thumbnail,omitempty"`
+	Sensitive   bool      `json:"sensitive"`
+	Protected   bool      `json:"password_protected"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (l GetLink) IsGated() bool {
+	return l.Sensitive || l.Protected
+}
+
+func (l GetLink) IsSocial() bool {
+	return l.Platform != ""
+}
+
+// SplitSocials separates links recognized as social profiles, rendered as an
+// icon row, from regular buttons.
+func SplitSocials(all []GetLink) (buttons []GetLink, socials []GetLink) {
+	buttons = []GetLink{}
+	socials = []GetLink{}
+	for _, link := range all {
+		if link.IsSocial() {
+			socials = append(socials, link)
+			continue
+		}
+		buttons = append(buttons, link)
+	}
+	return buttons, socials
+}
@@ -0,0 +1,12 @@
+package links
+
+type ResolvedLink struct {
+	ID        int32  `json:"id"`
+	URL       string `json:"url"`
+	Sensitive bool   `json:"sensitive"`
+	Protected bool   `json:"password_protected"`
+}
+
+func (l ResolvedLink) IsGated() bool {
+	return l.Sensitive || l.Protected
+}
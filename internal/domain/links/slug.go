@@ -0,0 +1,50 @@
+package links
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidSlug  = errors.New("slug must contain only lowercase letters, numbers and hyphens")
+	ErrReservedSlug = errors.New("slug is reserved")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// reservedSlugs are words kept for current or future routes under a user's
+// namespace, e.g. /u/{username}/{slug}.
+var reservedSlugs = map[string]struct{}{
+	"admin":    {},
+	"api":      {},
+	"assets":   {},
+	"click":    {},
+	"edit":     {},
+	"embed":    {},
+	"help":     {},
+	"links":    {},
+	"login":    {},
+	"logout":   {},
+	"new":      {},
+	"profile":  {},
+	"qr":       {},
+	"settings": {},
+	"share":    {},
+	"static":   {},
+	"unlock":   {},
+}
+
+func NormalizeSlug(slug string) (string, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+
+	if !slugPattern.MatchString(slug) {
+		return "", ErrInvalidSlug
+	}
+
+	if _, ok := reservedSlugs[slug]; ok {
+		return "", ErrReservedSlug
+	}
+
+	return slug, nil
+}
@@ -0,0 +1,11 @@
+package links
+
+type UnlockLink struct {
+	Password         string `json:"password" validate:"max=72"`
+	ConfirmSensitive bool   `json:"confirm_sensitive"`
+}
+
+type UnlockedLink struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
@@ -0,0 +1,16 @@
+package media
+
+import "time"
+
+// Media is an image of the media library of a page. Its ID can be sent in
+// place of a file to set the avatar, banner or theme background.
+type Media struct {
+	ID          int32     `json:"id"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type"`
+	Width       int32     `json:"width"`
+	Height      int32     `json:"height"`
+	Size        int64     `json:"size"`
+	InUse       bool      `json:"in_use"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package page
+
+import (
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+)
+
+// States of an uploaded picture. A picture is pending while it is scanned,
+// rejected when the scanner flagged it and failed when it could not be
+// processed; the previous picture stays in place in all three cases.
+const (
+	PictureReady    = "ready"
+	PicturePending  = "pending"
+	PictureRejected = "rejected"
+	PictureFailed   = "failed"
+)
+
+type CreatePage struct {
+	Handle string `json:"handle" validate:"required,min=3,max=30"`
+	Name   string `json:"name" validate:"required"`
+}
+
+type GetPage struct {
+	ID                     int32             `json:"id"`
+	Handle                 string            `json:"handle"`
+	Name                   string            `json:"name"`
+	Bio                    string            `json:"bio"`
+	ProfilePicture         string            `json:"profile_picture"`
+	BannerPicture          string            `json:"banner_picture"`
+	ProfilePictureVariants map[string]string `json:"profile_picture_variants,omitempty"`
+	BannerPictureVariants  map[string]string `json:"banner_picture_variants,omitempty"`
+	ProfilePictureState    string            `json:"profile_picture_state,omitempty"`
+	BannerPictureState     string            `json:"banner_picture_state,omitempty"`
+	Theme                  *theme.Theme      `json:"theme,omitempty"`
+	Visibility             string            `json:"visibility,omitempty"`
+	PublishedAt            *time.Time        `json:"published_at,omitempty"`
+	Role                   string            `json:"role,omitempty"`
+	CreatedAt              time.Time         `json:"created_at"`
+}
+
+type UpdateBioRequest struct {
+	Bio string
+}
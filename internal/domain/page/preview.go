@@ -0,0 +1,11 @@
+package page
+
+import "github.com/theNixagen/linker/internal/domain/links"
+
+// PagePreview is the draft page as it will look once published.
+type PagePreview struct {
+	Page                  GetPage         `json:"page"`
+	Links                 []links.GetLink `json:"links"`
+	Socials               []links.GetLink `json:"socials"`
+	HasUnpublishedChanges bool            `json:"has_unpublished_changes"`
+}
@@ -0,0 +1,17 @@
+package page
+
+import "time"
+
+const (
+	VisibilityPublic   = "public"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPrivate  = "private"
+)
+
+type UpdateVisibilityRequest struct {
+	Visibility string `json:"visibility" validate:"required,oneof=public unlisted private"`
+}
+
+type PublishedPage struct {
+	PublishedAt time.Time `json:"published_at"`
+}
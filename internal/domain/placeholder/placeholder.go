@@ -0,0 +1,87 @@
+package placeholder
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	ContentType = "image/svg+xml"
+
+	avatarSize   = 256
+	bannerWidth  = 1500
+	bannerHeight = 500
+)
+
+// palette derives two hues from seed, so a handle always gets the same
+// colors and different handles rarely share them.
+func palette(seed string) (int, int) {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(seed)))
+	sum := h.Sum32()
+
+	from := int(sum % 360)
+	to := (from + 40 + int((sum>>16)%80)) % 360
+	return from, to
+}
+
+// Initials returns up to two uppercase letters taken from the words of
+// handle, split on dashes, underscores and dots.
+func Initials(handle string) string {
+	words := strings.FieldsFunc(handle, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || unicode.IsSpace(r)
+	})
+
+	var initials []rune
+	for _, word := range words {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				initials = append(initials, unicode.ToUpper(r))
+				break
+			}
+		}
+		if len(initials) == 2 {
+			break
+		}
+	}
+	if len(initials) == 0 {
+		return "?"
+	}
+	return string(initials)
+}
+
+// Avatar renders the initials of handle over a gradient seeded by it.
+func Avatar(handle string) []byte {
+	from, to := palette(handle)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, avatarSize, avatarSize, avatarSize, avatarSize)
+	writeGradient(&buf, from, to)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="url(#g)"/>`)
+	fmt.Fprintf(&buf, `<text x="50%%" y="50%%" dy=".35em" text-anchor="middle" fill="#fff" font-family="Helvetica, Arial, sans-serif" font-size="%d" font-weight="600">%s</text>`, avatarSize*2/5, html.EscapeString(Initials(handle)))
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+// Banner renders a 3:1 gradient seeded by handle.
+func Banner(handle string) []byte {
+	from, to := palette(handle)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, bannerWidth, bannerHeight, bannerWidth, bannerHeight)
+	writeGradient(&buf, from, to)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="url(#g)"/>`)
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
+
+func writeGradient(buf *bytes.Buffer, from, to int) {
+	fmt.Fprintf(buf, `<defs><linearGradient id="g" x1="0" y1="0" x2="1" y2="1">`)
+	fmt.Fprintf(buf, `<stop offset="0" stop-color="hsl(%d, 65%%, 50%%)"/>`, from)
+	fmt.Fprintf(buf, `<stop offset="1" stop-color="hsl(%d, 70%%, 40%%)"/>`, to)
+	buf.WriteString(`</linearGradient></defs>`)
+}
@@ -0,0 +1,37 @@
+package placeholder
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestInitials(t *testing.T) {
+	tests := []struct {
+		handle string
+		want   string
+	}{
+		{"johndoe", "J"},
+		{"john-doe", "JD"},
+		{"john_doe.dev", "JD"},
+		{"9lives", "9"},
+		{"--", "?"},
+	}
+
+	for _, tt := range tests {
+		if got := Initials(tt.handle); got != tt.want {
+			t.Errorf("Initials(%q) = %q, expected %q", tt.handle, got, tt.want)
+		}
+	}
+}
+
+func TestAvatar_IsDeterministic(t *testing.T) {
+	if !bytes.Equal(Avatar("johndoe"), Avatar("JohnDoe")) {
+		t.Errorf("expected the same avatar regardless of case")
+	}
+	if bytes.Equal(Banner("johndoe"), Banner("janedoe")) {
+		t.Errorf("expected different handles to get different banners")
+	}
+	if !bytes.Contains(Avatar("john-doe"), []byte(">JD</text>")) {
+		t.Errorf("expected the initials in the avatar, got %s", Avatar("john-doe"))
+	}
+}
@@ -0,0 +1,145 @@
+package platform
+
+import "regexp"
+
+func reserved(words ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+var Instagram = Platform{
+	ID:       "instagram",
+	Name:     "Instagram",
+	Hosts:    []string{"instagram.com", "instagr.am"},
+	Paths:    []*regexp.Regexp{regexp.MustCompile(`^/([A-Za-z0-9._]+)/?$`)},
+	Reserved: reserved("p", "reel", "reels", "explore", "stories", "accounts", "direct", "tv"),
+	Handle:   regexp.MustCompile(`^[A-Za-z0-9._]{1,30}$`),
+	Format:   "https://www.instagram.com/%s",
+}
+
+var TikTok = Platform{
+	ID:     "tiktok",
+	Name:   "TikTok",
+	Hosts:  []string{"tiktok.com"},
+	Paths:  []*regexp.Regexp{regexp.MustCompile(`^/@([A-Za-z0-9._]+)/?$`)},
+	Handle: regexp.MustCompile(`^[A-Za-z0-9._]{2,24}$`),
+	Format: "https://www.tiktok.com/@%s",
+}
+
+var GitHub = Platform{
+	ID:       "github",
+	Name:     "GitHub",
+	Hosts:    []string{"github.com"},
+	Paths:    []*regexp.Regexp{regexp.MustCompile(`^/([A-Za-z0-9-]+)/?$`)},
+	Reserved: reserved("about", "explore", "features", "login", "marketplace", "notifications", "orgs", "pricing", "settings", "topics", "sponsors"),
+	Handle:   regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9]|-[A-Za-z0-9]){0,38}$`),
+	Format:   "https://github.com/%s",
+}
+
+var YouTube = Platform{
+	ID:    "youtube",
+	Name:  "YouTube",
+	Hosts: []string{"youtube.com"},
+	Paths: []*regexp.Regexp{
+		regexp.MustCompile(`^/@([A-Za-z0-9._-]+)/?$`),
+		regexp.MustCompile(`^/c/([A-Za-z0-9._-]+)/?$`),
+		regexp.MustCompile(`^/user/([A-Za-z0-9._-]+)/?$`),
+	},
+	Handle: regexp.MustCompile(`^[A-Za-z0-9._-]{3,30}$`),
+	Format: "https://www.youtube.com/@%s",
+}
+
+var X = Platform{
+	ID:       "x",
+	Name:     "X",
+	Hosts:    []string{"x.com", "twitter.com"},
+	Paths:    []*regexp.Regexp{regexp.MustCompile(`^/([A-Za-z0-9_]+)/?$`)},
+	Reserved: reserved("home", "explore", "search", "i", "intent", "settings", "share", "messages", "notifications", "login", "signup", "tos", "privacy"),
+	Handle:   regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`),
+	Format:   "https://x.com/%s",
+}
+
+var LinkedIn = Platform{
+	ID:     "linkedin",
+	Name:   "LinkedIn",
+	Hosts:  []string{"linkedin.com"},
+	Paths:  []*regexp.Regexp{regexp.MustCompile(`^/in/([A-Za-z0-9-]+)/?$`)},
+	Handle: regexp.MustCompile(`^[A-Za-z0-9-]{3,100}$`),
+	Format: "https://www.linkedin.com/in/%s",
+}
+
+var Facebook = Platform{
+	ID:       "facebook",
+	Name:     "Facebook",
+	Hosts:    []string{"facebook.com", "fb.com"},
+	Paths:    []*regexp.Regexp{regexp.MustCompile(`^/([A-Za-z0-9.]+)/?$`)},
+	Reserved: reserved("groups", "events", "pages", "watch", "marketplace", "gaming", "login", "profile.php", "sharer", "share"),
+	Handle:   regexp.MustCompile(`^[A-Za-z0-9.]{5,50}$`),
+	Format:   "https://www.facebook.com/%s",
+}
+
+var Twitch = Platform{
+	ID:       "twitch",
+	Name:     "Twitch",
+	Hosts:    []string{"twitch.tv"},
+	Paths:    []*regexp.Regexp{regexp.MustCompile(`^/([A-Za-z0-9_]+)/?$`)},
+	Reserved: reserved("directory", "downloads", "jobs", "p", "settings", "subscriptions", "videos", "search"),
+	Handle:   regexp.MustCompile(`^[A-Za-z0-9_]{4,25}$`),
+	Format:   "https://www.twitch.tv/%s",
+}
+
+var Threads = Platform{
+	ID:     "threads",
+	Name:   "Threads",
+	Hosts:  []string{"threads.net", "threads.com"},
+	Paths:  []*regexp.Regexp{regexp.MustCompile(`^/@([A-Za-z0-9._]+)/?$`)},
+	Handle: regexp.MustCompile(`^[A-Za-z0-9._]{1,30}$`),
+	Format: "https://www.threads.net/@%s",
+}
+
+var Bluesky = Platform{
+	ID:     "bluesky",
+	Name:   "Bluesky",
+	Hosts:  []string{"bsky.app"},
+	Paths:  []*regexp.Regexp{regexp.MustCompile(`^/profile/([A-Za-z0-9.-]+)/?$`)},
+	Handle: regexp.MustCompile(`^[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)+$`),
+	Format: "https://bsky.app/profile/%s",
+}
+
+var Telegram = Platform{
+	ID:       "telegram",
+	Name:     "Telegram",
+	Hosts:    []string{"t.me", "telegram.me"},
+	Paths:    []*regexp.Regexp{regexp.MustCompile(`^/([A-Za-z0-9_]+)/?$`)},
+	Reserved: reserved("joinchat", "addstickers", "share", "proxy", "socks"),
+	Handle:   regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{4,31}$`),
+	Format:   "https://t.me/%s",
+}
+
+var Pinterest = Platform{
+	ID:       "pinterest",
+	Name:     "Pinterest",
+	Hosts:    []string{"pinterest.com", "br.pinterest.com"},
+	Paths:    []*regexp.Regexp{regexp.MustCompile(`^/([A-Za-z0-9_]+)/?$`)},
+	Reserved: reserved("pin", "search", "ideas", "today", "settings", "business"),
+	Handle:   regexp.MustCompile(`^[A-Za-z0-9_]{3,30}$`),
+	Format:   "https://www.pinterest.com/%s",
+}
+
+var Default = NewRegistry(
+	Instagram,
+	TikTok,
+	YouTube,
+	X,
+	GitHub,
+	LinkedIn,
+	Facebook,
+	Twitch,
+	Threads,
+	Bluesky,
+	Telegram,
+	Pinterest,
+)
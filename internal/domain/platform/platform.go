@@ -0,0 +1,131 @@
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUnknownPlatform = errors.New("unknown platform")
+	ErrInvalidHandle   = errors.New("invalid handle for platform")
+)
+
+type Platform struct {
+	ID    string
+	Name  string
+	Hosts []string
+	// Paths are matched against the URL path; the first capture group is the handle.
+	Paths []*regexp.Regexp
+	// Reserved holds first path segments that look like handles but are not profiles.
+	Reserved map[string]struct{}
+	Handle   *regexp.Regexp
+	// Format builds the canonical profile URL from a handle.
+	Format string
+}
+
+type Match struct {
+	Platform string `json:"platform"`
+	Handle   string `json:"handle"`
+	URL      string `json:"url"`
+}
+
+func (p Platform) ProfileURL(handle string) string {
+	return fmt.Sprintf(p.Format, handle)
+}
+
+type Registry struct {
+	platforms []Platform
+	byID      map[string]Platform
+	byHost    map[string]Platform
+}
+
+func NewRegistry(platforms ...Platform) *Registry {
+	r := &Registry{
+		byID:   make(map[string]Platform),
+		byHost: make(map[string]Platform),
+	}
+	for _, p := range platforms {
+		r.platforms = append(r.platforms, p)
+		r.byID[p.ID] = p
+		for _, host := range p.Hosts {
+			r.byHost[host] = p
+		}
+	}
+	return r
+}
+
+func (r *Registry) Platforms() []Platform {
+	return r.platforms
+}
+
+func (r *Registry) Get(id string) (Platform, bool) {
+	p, ok := r.byID[strings.ToLower(id)]
+	return p, ok
+}
+
+// Recognize checks whether rawURL points to a profile on a known platform and
+// returns its handle and canonical URL. URLs without a scheme are accepted.
+func (r *Registry) Recognize(rawURL string) (Match, bool) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return Match{}, false
+	}
+
+	p, ok := r.byHost[normalizeHost(u.Hostname())]
+	if !ok {
+		return Match{}, false
+	}
+
+	for _, path := range p.Paths {
+		groups := path.FindStringSubmatch(u.Path)
+		if groups == nil {
+			continue
+		}
+
+		handle := groups[1]
+		if _, reserved := p.Reserved[strings.ToLower(handle)]; reserved {
+			return Match{}, false
+		}
+		if !p.Handle.MatchString(handle) {
+			return Match{}, false
+		}
+
+		return Match{Platform: p.ID, Handle: handle, URL: p.ProfileURL(handle)}, true
+	}
+
+	return Match{}, false
+}
+
+// FromHandle builds a match from a bare handle such as "@johndoe".
+func (r *Registry) FromHandle(platformID, handle string) (Match, error) {
+	p, ok := r.Get(platformID)
+	if !ok {
+		return Match{}, ErrUnknownPlatform
+	}
+
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	if !p.Handle.MatchString(handle) {
+		return Match{}, ErrInvalidHandle
+	}
+	if _, reserved := p.Reserved[strings.ToLower(handle)]; reserved {
+		return Match{}, ErrInvalidHandle
+	}
+
+	return Match{Platform: p.ID, Handle: handle, URL: p.ProfileURL(handle)}, nil
+}
+
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, prefix := range []string{"www.", "m.", "mobile."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	return host
+}
@@ -0,0 +1,113 @@
+package platform
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegistry_Recognize(t *testing.T) {
+	tests := []struct {
+		url      string
+		platform string
+		handle   string
+		want     string
+	}{
+		{"https://www.instagram.com/johndoe", "instagram", "johndoe", "https://www.instagram.com/johndoe"},
+		{"http://instagram.com/john.doe_/", "instagram", "john.doe_", "https://www.instagram.com/john.doe_"},
+		{"instagram.com/johndoe?igshid=abc123", "instagram", "johndoe", "https://www.instagram.com/johndoe"},
+		{"https://instagr.am/johndoe", "instagram", "johndoe", "https://www.instagram.com/johndoe"},
+		{"https://www.tiktok.com/@johndoe", "tiktok", "johndoe", "https://www.tiktok.com/@johndoe"},
+		{"https://m.tiktok.com/@john.doe?lang=en", "tiktok", "john.doe", "https://www.tiktok.com/@john.doe"},
+		{"https://github.com/theNixagen", "github", "theNixagen", "https://github.com/theNixagen"},
+		{"https://GITHUB.com/john-doe/", "github", "john-doe", "https://github.com/john-doe"},
+		{"https://www.youtube.com/@johndoe", "youtube", "johndoe", "https://www.youtube.com/@johndoe"},
+		{"https://m.youtube.com/c/JohnDoe", "youtube", "JohnDoe", "https://www.youtube.com/@JohnDoe"},
+		{"https://youtube.com/user/johndoe", "youtube", "johndoe", "https://www.youtube.com/@johndoe"},
+		{"https://twitter.com/johndoe", "x", "johndoe", "https://x.com/johndoe"},
+		{"https://x.com/john_doe#top", "x", "john_doe", "https://x.com/john_doe"},
+		{"https://mobile.twitter.com/johndoe", "x", "johndoe", "https://x.com/johndoe"},
+		{"https://www.linkedin.com/in/john-doe-123/", "linkedin", "john-doe-123", "https://www.linkedin.com/in/john-doe-123"},
+		{"https://www.facebook.com/john.doe", "facebook", "john.doe", "https://www.facebook.com/john.doe"},
+		{"https://www.twitch.tv/johndoe", "twitch", "johndoe", "https://www.twitch.tv/johndoe"},
+		{"https://www.threads.net/@johndoe", "threads", "johndoe", "https://www.threads.net/@johndoe"},
+		{"https://bsky.app/profile/johndoe.bsky.social", "bluesky", "johndoe.bsky.social", "https://bsky.app/profile/johndoe.bsky.social"},
+		{"https://t.me/johndoe", "telegram", "johndoe", "https://t.me/johndoe"},
+		{"https://br.pinterest.com/johndoe/", "pinterest", "johndoe", "https://www.pinterest.com/johndoe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			match, ok := Default.Recognize(tt.url)
+			if !ok {
+				t.Fatalf("expected %q to be recognized", tt.url)
+			}
+			if match.Platform != tt.platform {
+				t.Errorf("expected platform %q, got %q", tt.platform, match.Platform)
+			}
+			if match.Handle != tt.handle {
+				t.Errorf("expected handle %q, got %q", tt.handle, match.Handle)
+			}
+			if match.URL != tt.want {
+				t.Errorf("expected url %q, got %q", tt.want, match.URL)
+			}
+		})
+	}
+}
+
+func TestRegistry_Recognize_NotAProfile(t *testing.T) {
+	tests := []string{
+		"https://example.com/johndoe",
+		"https://www.instagram.com/p/Cx1abc/",
+		"https://www.instagram.com/explore",
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://github.com/theNixagen/linker",
+		"https://github.com/settings",
+		"https://x.com/johndoe/status/123",
+		"https://x.com/home",
+		"https://www.tiktok.com/johndoe",
+		"https://www.linkedin.com/company/acme",
+		"https://t.me/joinchat",
+		"ftp://github.com/johndoe",
+		"https://instagram.com.evil.com/johndoe",
+		"not a url",
+		"",
+	}
+
+	for _, url := range tests {
+		t.Run(url, func(t *testing.T) {
+			if match, ok := Default.Recognize(url); ok {
+				t.Fatalf("expected %q not to be recognized, got %+v", url, match)
+			}
+		})
+	}
+}
+
+func TestRegistry_FromHandle(t *testing.T) {
+	tests := []struct {
+		platform string
+		handle   string
+		want     string
+		err      error
+	}{
+		{"instagram", "@johndoe", "https://www.instagram.com/johndoe", nil},
+		{"tiktok", "johndoe", "https://www.tiktok.com/@johndoe", nil},
+		{"GitHub", " theNixagen ", "https://github.com/theNixagen", nil},
+		{"x", "@john_doe", "https://x.com/john_doe", nil},
+		{"x", "this_handle_is_way_too_long", "", ErrInvalidHandle},
+		{"instagram", "john doe", "", ErrInvalidHandle},
+		{"instagram", "explore", "", ErrInvalidHandle},
+		{"myspace", "johndoe", "", ErrUnknownPlatform},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform+"/"+tt.handle, func(t *testing.T) {
+			match, err := Default.FromHandle(tt.platform, tt.handle)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if match.URL != tt.want {
+				t.Errorf("expected url %q, got %q", tt.want, match.URL)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package qrcode
+
+import (
+	"errors"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+
+	// Source is the click source recorded for visits coming from a QR code.
+	Source = "qr"
+
+	// MinContrastRatio keeps codes readable by phone cameras.
+	MinContrastRatio = 3
+)
+
+var (
+	ErrLowContrast     = errors.New("foreground and background colors do not have enough contrast")
+	ErrLightForeground = errors.New("foreground color must be darker than the background")
+)
+
+type Options struct {
+	Format     string `json:"format" validate:"oneof=png svg"`
+	Size       int    `json:"size" validate:"min=128,max=2048"`
+	Level      string `json:"level" validate:"oneof=L M Q H"`
+	Foreground string `json:"fg" validate:"hexcolor"`
+	Background string `json:"bg" validate:"hexcolor"`
+	Logo       bool   `json:"logo"`
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Format:     FormatPNG,
+		Size:       512,
+		Level:      "M",
+		Foreground: "#000000",
+		Background: "#ffffff",
+	}
+}
+
+// Validate checks the colors can still be scanned. Inverted codes are rejected
+// because many readers do not support them.
+func (o Options) Validate() error {
+	fg, err := theme.ParseColor(o.Foreground)
+	if err != nil {
+		return err
+	}
+	bg, err := theme.ParseColor(o.Background)
+	if err != nil {
+		return err
+	}
+
+	if fg.RelativeLuminance() >= bg.RelativeLuminance() {
+		return ErrLightForeground
+	}
+
+	ratio, err := theme.ContrastRatio(o.Foreground, o.Background)
+	if err != nil {
+		return err
+	}
+	if ratio < MinContrastRatio {
+		return ErrLowContrast
+	}
+	return nil
+}
+
+func (o Options) ContentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
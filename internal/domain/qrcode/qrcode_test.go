@@ -0,0 +1,36 @@
+package qrcode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		foreground string
+		background string
+		want       error
+	}{
+		{"default", "#000000", "#ffffff", nil},
+		{"dark brand color", "#1e3a8a", "#f8fafc", nil},
+		{"inverted", "#ffffff", "#000000", ErrLightForeground},
+		{"low contrast", "#777777", "#888888", ErrLowContrast},
+		{"invalid color", "black", "#ffffff", theme.ErrInvalidColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.Foreground = tt.foreground
+			opts.Background = tt.background
+
+			err := opts.Validate()
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package revision
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/domain/utm"
+)
+
+const (
+	ReasonPublish = "publish"
+	ReasonRestore = "restore"
+)
+
+// Snapshot is the published state of a profile: its fields, theme, default
+// UTM tags and links. UTM is nil in revisions recorded before it was part of
+// the snapshot.
+type Snapshot struct {
+	Name           string       `json:"name"`
+	Bio            string       `json:"bio"`
+	ProfilePicture string       `json:"profile_picture"`
+	BannerPicture  string       `json:"banner_picture"`
+	Theme          *theme.Theme `json:"theme,omitempty"`
+	UTM            *utm.UTM     `json:"utm,omitempty"`
+	Links          []Link       `json:"links"`
+}
+
+type Link struct {
+	ID           int32     `json:"id"`
+	URL          string    `json:"url"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Slug         string    `json:"slug,omitempty"`
+	ShortCode    string    `json:"short_code"`
+	Platform     string    `json:"platform,omitempty"`
+	Handle       string    `json:"handle,omitempty"`
+	Sensitive    bool      `json:"sensitive"`
+	PasswordHash string    `json:"password_hash,omitempty"`
+	UTM          utm.UTM   `json:"utm"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	ThumbnailMediaID int32  `json:"thumbnail_media_id,omitempty"`
+	Thumbnail        string `json:"thumbnail,omitempty"`
+}
+
+type GetRevision struct {
+	ID        int32     `json:"id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Change struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type RevisionDiff struct {
+	From    int32    `json:"from"`
+	To      int32    `json:"to"`
+	Changes []Change `json:"changes"`
+}
+
+// LinkSummary describes an added or removed link in a diff.
+type LinkSummary struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Diff lists what changed from one snapshot to the other. Password hashes are
+// never part of the result, only whether a link is protected.
+func Diff(from, to Snapshot) []Change {
+	changes := []Change{}
+	add := func(field string, a, b any) {
+		changes = append(changes, Change{Field: field, From: a, To: b})
+	}
+
+	if from.Name != to.Name {
+		add("name", from.Name, to.Name)
+	}
+	if from.Bio != to.Bio {
+		add("bio", from.Bio, to.Bio)
+	}
+	if from.ProfilePicture != to.ProfilePicture {
+		add("profile_picture", from.ProfilePicture, to.ProfilePicture)
+	}
+	if from.BannerPicture != to.BannerPicture {
+		add("banner_picture", from.BannerPicture, to.BannerPicture)
+	}
+	if !sameJSON(from.Theme, to.Theme) {
+		add("theme", from.Theme, to.Theme)
+	}
+	if from.UTM != nil && to.UTM != nil && !sameJSON(from.UTM, to.UTM) {
+		add("utm", from.UTM, to.UTM)
+	}
+
+	previous := make(map[int32]Link, len(from.Links))
+	for _, link := range from.Links {
+		previous[link.ID] = link
+	}
+
+	for _, b := range to.Links {
+		a, ok := previous[b.ID]
+		if !ok {
+			add(linkField(b.ID, ""), nil, LinkSummary{Title: b.Title, URL: b.URL})
+			continue
+		}
+		delete(previous, b.ID)
+
+		if a.URL != b.URL {
+			add(linkField(b.ID, "url"), a.URL, b.URL)
+		}
+		if a.Title != b.Title {
+			add(linkField(b.ID, "title"), a.Title, b.Title)
+		}
+		if a.Description != b.Description {
+			add(linkField(b.ID, "description"), a.Description, b.Description)
+		}
+		if a.Slug != b.Slug {
+			add(linkField(b.ID, "slug"), a.Slug, b.Slug)
+		}
+		if a.Platform != b.Platform || a.Handle != b.Handle {
+			add(linkField(b.ID, "platform"), a.Platform+":"+a.Handle, b.Platform+":"+b.Handle)
+		}
+		if a.Sensitive != b.Sensitive {
+			add(linkField(b.ID, "sensitive"), a.Sensitive, b.Sensitive)
+		}
+		if a.PasswordHash != b.PasswordHash {
+			add(linkField(b.ID, "protected"), a.PasswordHash != "", b.PasswordHash != "")
+		}
+		if !sameJSON(a.UTM, b.UTM) {
+			add(linkField(b.ID, "utm"), a.UTM, b.UTM)
+		}
+		if a.Thumbnail != b.Thumbnail {
+			add(linkField(b.ID, "thumbnail"), a.Thumbnail, b.Thumbnail)
+		}
+	}
+
+	for _, a := range from.Links {
+		if _, removed := previous[a.ID]; removed {
+			add(linkField(a.ID, ""), LinkSummary{Title: a.Title, URL: a.URL}, nil)
+		}
+	}
+
+	return changes
+}
+
+func linkField(id int32, field string) string {
+	if field == "" {
+		return fmt.Sprintf("links[%d]", id)
+	}
+	return fmt.Sprintf("links[%d].%s", id, field)
+}
+
+func sameJSON(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
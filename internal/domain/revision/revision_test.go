@@ -0,0 +1,56 @@
+package revision
+
+import (
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/utm"
+)
+
+func TestDiff(t *testing.T) {
+	from := Snapshot{
+		Name: "John Doe",
+		Bio:  "old bio",
+		Links: []Link{
+			{ID: 1, URL: "https://example.com", Title: "Example"},
+			{ID: 2, URL: "https://example.com/old", Title: "Old", PasswordHash: "hash"},
+		},
+	}
+	to := Snapshot{
+		Name: "John Doe",
+		Bio:  "new bio",
+		Links: []Link{
+			{ID: 1, URL: "https://example.com", Title: "Example!", UTM: utm.UTM{Source: "linker"}},
+			{ID: 3, URL: "https://example.com/new", Title: "New"},
+		},
+	}
+
+	changes := Diff(from, to)
+
+	want := []string{"bio", "links[1].title", "links[1].utm", "links[3]", "links[2]"}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %d changes, got %+v", len(want), changes)
+	}
+	for i, field := range want {
+		if changes[i].Field != field {
+			t.Fatalf("expected change %d to be %q, got %q", i, field, changes[i].Field)
+		}
+	}
+
+	if removed, ok := changes[4].From.(LinkSummary); !ok || removed.Title != "Old" || changes[4].To != nil {
+		t.Fatalf("expected removed link summary, got %+v", changes[4])
+	}
+
+	if len(Diff(to, to)) != 0 {
+		t.Fatalf("expected no changes between equal snapshots")
+	}
+}
+
+func TestDiff_HidesPasswordHash(t *testing.T) {
+	from := Snapshot{Links: []Link{{ID: 1, PasswordHash: "a"}}}
+	to := Snapshot{Links: []Link{{ID: 1, PasswordHash: "b"}}}
+
+	changes := Diff(from, to)
+	if len(changes) != 1 || changes[0].Field != "links[1].protected" || changes[0].From != true || changes[0].To != true {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+}
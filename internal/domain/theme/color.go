@@ -0,0 +1,60 @@
+package theme
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+type RGB struct {
+	R, G, B uint8
+}
+
+func ParseColor(hex string) (RGB, error) {
+	if !hexColorPattern.MatchString(hex) {
+		return RGB{}, ErrInvalidColor
+	}
+
+	digits := hex[1:]
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return RGB{}, ErrInvalidColor
+	}
+
+	return RGB{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value)}, nil
+}
+
+// RelativeLuminance follows the WCAG 2.x definition.
+func (c RGB) RelativeLuminance() float64 {
+	channel := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.03928 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.R) + 0.7152*channel(c.G) + 0.0722*channel(c.B)
+}
+
+func ContrastRatio(fg, bg string) (float64, error) {
+	a, err := ParseColor(fg)
+	if err != nil {
+		return 0, err
+	}
+	b, err := ParseColor(bg)
+	if err != nil {
+		return 0, err
+	}
+
+	l1, l2 := a.RelativeLuminance(), b.RelativeLuminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05), nil
+}
@@ -0,0 +1,166 @@
+package theme
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const CurrentVersion = 1
+
+var (
+	ErrUnsupportedVersion = errors.New("unsupported theme version")
+	ErrInvalidColor       = errors.New("colors must be hex values like #1a2b3c")
+	ErrFontNotAllowed     = errors.New("font is not allowed")
+	ErrMissingImage       = errors.New("image background requires an image")
+	ErrLowContrast        = errors.New("contrast ratio is too low")
+)
+
+// MinContrastRatio is the WCAG AA ratio for normal text.
+const MinContrastRatio = 4.5
+
+var AllowedFonts = []string{
+	"Inter",
+	"Roboto",
+	"Open Sans",
+	"Lato",
+	"Montserrat",
+	"Poppins",
+	"Merriweather",
+	"Playfair Display",
+	"Space Mono",
+}
+
+type Theme struct {
+	Version    int        `json:"version"`
+	Background Background `json:"background"`
+	Button     Button     `json:"button"`
+	Font       string     `json:"font" validate:"required"`
+	TextColor  string     `json:"text_color" validate:"required"`
+}
+
+type Background struct {
+	Type     string    `json:"type" validate:"oneof=color gradient image"`
+	Color    string    `json:"color" validate:"required"`
+	Gradient *Gradient `json:"gradient,omitempty"`
+	Image    string    `json:"image,omitempty"`
+	ImageURL string    `json:"image_url,omitempty"`
+}
+
+type Gradient struct {
+	From  string `json:"from" validate:"required"`
+	To    string `json:"to" validate:"required"`
+	Angle int    `json:"angle" validate:"min=0,max=360"`
+}
+
+type Button struct {
+	Shape     string `json:"shape" validate:"oneof=square rounded pill"`
+	Fill      string `json:"fill" validate:"oneof=solid outline"`
+	Shadow    string `json:"shadow" validate:"oneof=none soft hard"`
+	Color     string `json:"color" validate:"required"`
+	TextColor string `json:"text_color" validate:"required"`
+}
+
+func Default() Theme {
+	return Theme{
+		Version: CurrentVersion,
+		Background: Background{
+			Type:  "color",
+			Color: "#ffffff",
+		},
+		Button: Button{
+			Shape:     "rounded",
+			Fill:      "solid",
+			Shadow:    "none",
+			Color:     "#111111",
+			TextColor: "#ffffff",
+		},
+		Font:      "Inter",
+		TextColor: "#111111",
+	}
+}
+
+// Parse decodes a stored theme, upgrading older versions to CurrentVersion.
+func Parse(data []byte) (Theme, error) {
+	var t Theme
+	if err := json.Unmarshal(data, &t); err != nil {
+		return Theme{}, err
+	}
+
+	if t.Version == 0 {
+		t.Version = CurrentVersion
+	}
+
+	if t.Version != CurrentVersion {
+		return Theme{}, ErrUnsupportedVersion
+	}
+
+	return t, nil
+}
+
+// Validate checks what struct tags can't: the version, the font allowlist,
+// color syntax and WCAG contrast between text and what it is drawn on.
+func (t Theme) Validate() error {
+	if t.Version != CurrentVersion {
+		return ErrUnsupportedVersion
+	}
+
+	allowed := false
+	for _, font := range AllowedFonts {
+		if font == t.Font {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return ErrFontNotAllowed
+	}
+
+	if t.Background.Type == "image" && t.Background.Image == "" {
+		return ErrMissingImage
+	}
+
+	backgrounds := []string{t.Background.Color}
+	if t.Background.Type == "gradient" {
+		if t.Background.Gradient == nil {
+			return fmt.Errorf("%w: gradient background requires gradient colors", ErrInvalidColor)
+		}
+		backgrounds = []string{t.Background.Gradient.From, t.Background.Gradient.To}
+	}
+
+	for _, c := range append(backgrounds, t.Background.Color, t.TextColor, t.Button.Color, t.Button.TextColor) {
+		if _, err := ParseColor(c); err != nil {
+			return err
+		}
+	}
+
+	for _, bg := range backgrounds {
+		if err := checkContrast("text", t.TextColor, bg); err != nil {
+			return err
+		}
+		if t.Button.Fill == "outline" {
+			if err := checkContrast("button text", t.Button.TextColor, bg); err != nil {
+				return err
+			}
+		}
+	}
+
+	if t.Button.Fill == "solid" {
+		if err := checkContrast("button text", t.Button.TextColor, t.Button.Color); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkContrast(name, fg, bg string) error {
+	ratio, err := ContrastRatio(fg, bg)
+	if err != nil {
+		return err
+	}
+	if ratio < MinContrastRatio {
+		return fmt.Errorf("%w: %s %s on %s is %.2f:1, minimum is %.1f:1", ErrLowContrast, name, fg, bg, ratio, MinContrastRatio)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package theme
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		fg, bg string
+		want   float64
+	}{
+		{"#000000", "#ffffff", 21},
+		{"#fff", "#fff", 1},
+		{"#777777", "#ffffff", 4.48},
+		{"#0000ff", "#ffffff", 8.59},
+	}
+
+	for _, tt := range tests {
+		got, err := ContrastRatio(tt.fg, tt.bg)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("expected contrast %s on %s to be %.2f, got %.2f", tt.fg, tt.bg, tt.want, got)
+		}
+	}
+}
+
+func TestTheme_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Theme)
+		err    error
+	}{
+		{"default", func(t *Theme) {}, nil},
+		{"gradient", func(t *Theme) {
+			t.Background = Background{Type: "gradient", Color: "#000000", Gradient: &Gradient{From: "#000000", To: "#1a1a40", Angle: 90}}
+			t.TextColor = "#ffffff"
+		}, nil},
+		{"image", func(t *Theme) {
+			t.Background = Background{Type: "image", Color: "#ffffff", Image: "background.png"}
+		}, nil},
+		{"image without key", func(t *Theme) {
+			t.Background = Background{Type: "image", Color: "#ffffff"}
+		}, ErrMissingImage},
+		{"unknown version", func(t *Theme) { t.Version = 2 }, ErrUnsupportedVersion},
+		{"font outside allowlist", func(t *Theme) { t.Font = "Comic Sans MS" }, ErrFontNotAllowed},
+		{"invalid color", func(t *Theme) { t.TextColor = "red" }, ErrInvalidColor},
+		{"low text contrast", func(t *Theme) { t.TextColor = "#eeeeee" }, ErrLowContrast},
+		{"low contrast on gradient end", func(t *Theme) {
+			t.Background = Background{Type: "gradient", Color: "#ffffff", Gradient: &Gradient{From: "#ffffff", To: "#222222"}}
+		}, ErrLowContrast},
+		{"low button contrast", func(t *Theme) { t.Button.TextColor = "#333333" }, ErrLowContrast},
+		{"outline button uses background", func(t *Theme) {
+			t.Button.Fill = "outline"
+			t.Button.TextColor = "#ffffff"
+		}, ErrLowContrast},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme := Default()
+			tt.modify(&theme)
+			if err := theme.Validate(); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	theme, err := Parse([]byte(`{"background":{"type":"color","color":"#fff"},"font":"Inter"}`))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if theme.Version != CurrentVersion {
+		t.Fatalf("expected version %d, got %d", CurrentVersion, theme.Version)
+	}
+
+	if _, err := Parse([]byte(`{"version":99}`)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatalf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
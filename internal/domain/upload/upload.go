@@ -0,0 +1,39 @@
+package upload
+
+import "time"
+
+const (
+	KindAvatar = "avatar"
+	KindBanner = "banner"
+
+	// URLTTL is how long the presigned policy accepts the upload. Pending
+	// uploads are kept for Expiry so a transfer that started just before the
+	// policy expired can still be completed.
+	URLTTL = 15 * time.Minute
+	Expiry = time.Hour
+)
+
+type CreateUpload struct {
+	Kind           string `json:"kind" validate:"required,oneof=avatar banner"`
+	ContentType    string `json:"content_type" validate:"required,oneof=image/jpeg image/png image/gif image/webp"`
+	Size           int64  `json:"size" validate:"required,min=1"`
+	ChecksumSHA256 string `json:"checksum_sha256,omitempty" validate:"omitempty,len=64,hexadecimal"`
+}
+
+// PresignedUpload tells the client where to POST the file: a multipart form
+// with Fields followed by the file in a field named "file".
+type PresignedUpload struct {
+	ID        int32             `json:"id"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Fields    map[string]string `json:"fields"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// Usage is how much of its storage quota an account uses over all of its
+// pages, in bytes.
+type Usage struct {
+	UsedBytes     int64 `json:"used_bytes"`
+	QuotaBytes    int64 `json:"quota_bytes"`
+	MaxUploadSize int64 `json:"max_upload_size"`
+}
@@ -1,5 +0,0 @@
-package user
-
-type UpdateBioRequest struct {
-	Bio string
-}
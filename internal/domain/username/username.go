@@ -0,0 +1,155 @@
+package username
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MinLength = 3
+	MaxLength = 30
+)
+
+var (
+	ErrInvalidLength     = fmt.Errorf("username must have between %d and %d characters", MinLength, MaxLength)
+	ErrInvalidCharacters = errors.New("username must contain only letters, numbers, dots and underscores, and can't start, end or repeat dots and underscores")
+	ErrConfusable        = errors.New("username contains characters that look like latin letters")
+	ErrReserved          = errors.New("username is reserved")
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9]+(?:[._][a-z0-9]+)*$`)
+
+// reserved are names that clash with top-level routes, such as /{username} and
+// /u/{username}/{slug}, or that could be used to impersonate the platform.
+var reserved = map[string]struct{}{
+	"about":         {},
+	"account":       {},
+	"admin":         {},
+	"administrator": {},
+	"api":           {},
+	"app":           {},
+	"assets":        {},
+	"auth":          {},
+	"blog":          {},
+	"dashboard":     {},
+	"docs":          {},
+	"help":          {},
+	"img":           {},
+	"invitations":   {},
+	"link":          {},
+	"linker":        {},
+	"links":         {},
+	"login":         {},
+	"logout":        {},
+	"me":            {},
+	"null":          {},
+	"pages":         {},
+	"privacy":       {},
+	"profile":       {},
+	"reference":     {},
+	"root":          {},
+	"security":      {},
+	"settings":      {},
+	"share":         {},
+	"signup":        {},
+	"static":        {},
+	"status":        {},
+	"storage":       {},
+	"support":       {},
+	"system":        {},
+	"terms":         {},
+	"undefined":     {},
+	"users":         {},
+	"www":           {},
+}
+
+// confusables maps letters from other scripts to the latin letter they are
+// commonly mistaken for. New names can't use them, but handles registered
+// before the policy may, so Skeleton folds them too.
+var confusables = map[rune]rune{
+	'а': 'a', 'в': 'b', 'е': 'e', 'з': '3', 'і': 'i', 'ј': 'j', 'к': 'k', 'м': 'm',
+	'н': 'h', 'о': 'o', 'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's',
+	'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'ɡ': 'g', 'ı': 'i', 'ℓ': 'l',
+	'α': 'a', 'β': 'b', 'ε': 'e', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p',
+	'τ': 't', 'υ': 'u', 'χ': 'x', 'ω': 'w',
+}
+
+// lookalikes folds characters of the allowed set that are easy to mistake for
+// one another, so "adm1n" is treated like "admin" when checking reserved names.
+var lookalikes = strings.NewReplacer("0", "o", "1", "l", "i", "l", "5", "s", "_", "", ".", "")
+
+// Normalize validates a username and returns it in its canonical form: trimmed
+// and lowercased. Usernames are compared case-insensitively.
+func Normalize(raw string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(raw))
+
+	if n := utf8.RuneCountInString(name); n < MinLength || n > MaxLength {
+		return "", ErrInvalidLength
+	}
+
+	for _, r := range name {
+		if r < utf8.RuneSelf {
+			continue
+		}
+		if isConfusable(r) {
+			return "", ErrConfusable
+		}
+		return "", ErrInvalidCharacters
+	}
+
+	if !usernamePattern.MatchString(name) {
+		return "", ErrInvalidCharacters
+	}
+
+	if IsReserved(name) {
+		return "", ErrReserved
+	}
+
+	return name, nil
+}
+
+// IsReserved reports whether name, or a lookalike of it, is reserved.
+func IsReserved(name string) bool {
+	name = strings.ToLower(name)
+	if _, ok := reserved[name]; ok {
+		return true
+	}
+
+	skeleton := Skeleton(name)
+	for r := range reserved {
+		if Skeleton(r) == skeleton {
+			return true
+		}
+	}
+	return false
+}
+
+// Skeleton reduces a name to a form where lookalike characters are equal, so
+// "j0hn_doe" and "johndoe" share one. Handles are unique by skeleton; the
+// pages_handle_skeleton migration computes the same folding in SQL.
+func Skeleton(name string) string {
+	return lookalikes.Replace(strings.Map(foldConfusable, strings.ToLower(name)))
+}
+
+func isConfusable(r rune) bool {
+	return foldConfusable(r) != r
+}
+
+func foldConfusable(r rune) rune {
+	if latin, ok := confusables[r]; ok {
+		return latin
+	}
+	// Fullwidth forms of ASCII letters and digits.
+	if r >= '０' && r <= 'ｚ' {
+		return r - '０' + '0'
+	}
+	return r
+}
+
+// IsPolicyViolation reports whether err was returned by Normalize.
+func IsPolicyViolation(err error) bool {
+	return errors.Is(err, ErrInvalidLength) || errors.Is(err, ErrInvalidCharacters) || errors.Is(err, ErrConfusable) || errors.Is(err, ErrReserved)
+}
@@ -0,0 +1,61 @@
+package username
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+		err  error
+	}{
+		{"johndoe", "johndoe", nil},
+		{"  JohnDoe ", "johndoe", nil},
+		{"john.doe", "john.doe", nil},
+		{"john_doe_99", "john_doe_99", nil},
+		{"jd", "", ErrInvalidLength},
+		{"averyveryveryverylongusername12", "", ErrInvalidLength},
+		{"john doe", "", ErrInvalidCharacters},
+		{"john-doe", "", ErrInvalidCharacters},
+		{".johndoe", "", ErrInvalidCharacters},
+		{"johndoe_", "", ErrInvalidCharacters},
+		{"john..doe", "", ErrInvalidCharacters},
+		{"joão", "", ErrInvalidCharacters},
+		{"jоhndoe", "", ErrConfusable},
+		{"ｊｏｈｎ", "", ErrConfusable},
+		{"admin", "", ErrReserved},
+		{"Docs", "", ErrReserved},
+		{"adm1n", "", ErrReserved},
+		{"l1nker", "", ErrReserved},
+		{"pages", "", ErrReserved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := Normalize(tt.raw)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSkeleton(t *testing.T) {
+	if Skeleton("Adm1n") != Skeleton("admin") {
+		t.Fatalf("expected lookalikes to share a skeleton")
+	}
+	if Skeleton("j0hn_doe") != Skeleton("johndoe") {
+		t.Fatalf("expected digits and separators to be folded")
+	}
+	if Skeleton("јоhndое") != Skeleton("johndoe") || Skeleton("ｊｏｈｎｄｏｅ") != Skeleton("johndoe") {
+		t.Fatalf("expected confusables of legacy handles to be folded")
+	}
+	if Skeleton("johndoe") == Skeleton("janedoe") {
+		t.Fatalf("expected different names to have different skeletons")
+	}
+}
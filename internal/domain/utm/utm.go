@@ -0,0 +1,137 @@
+package utm
+
+import (
+	"cmp"
+	"errors"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidParam = errors.New("custom params must have keys with letters, numbers, '_', '-' or '.'")
+	ErrInvalidURL   = errors.New("url cannot be tagged")
+)
+
+var paramKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,50}$`)
+
+type UTM struct {
+	Source   string            `json:"utm_source,omitempty" validate:"max=100"`
+	Medium   string            `json:"utm_medium,omitempty" validate:"max=100"`
+	Campaign string            `json:"utm_campaign,omitempty" validate:"max=100"`
+	Term     string            `json:"utm_term,omitempty" validate:"max=100"`
+	Content  string            `json:"utm_content,omitempty" validate:"max=100"`
+	Params   map[string]string `json:"params,omitempty" validate:"max=10,dive,max=200"`
+}
+
+func (u UTM) Validate() error {
+	for key := range u.Params {
+		if !paramKeyPattern.MatchString(key) {
+			return ErrInvalidParam
+		}
+	}
+	return nil
+}
+
+func (u UTM) IsZero() bool {
+	return u.Source == "" && u.Medium == "" && u.Campaign == "" && u.Term == "" && u.Content == "" && len(u.Params) == 0
+}
+
+// Merge returns u with every non-empty value of override applied on top.
+func (u UTM) Merge(override UTM) UTM {
+	merged := UTM{
+		Source:   cmp.Or(override.Source, u.Source),
+		Medium:   cmp.Or(override.Medium, u.Medium),
+		Campaign: cmp.Or(override.Campaign, u.Campaign),
+		Term:     cmp.Or(override.Term, u.Term),
+		Content:  cmp.Or(override.Content, u.Content),
+	}
+
+	if len(u.Params) > 0 || len(override.Params) > 0 {
+		merged.Params = make(map[string]string, len(u.Params)+len(override.Params))
+		for k, v := range u.Params {
+			merged.Params[k] = v
+		}
+		for k, v := range override.Params {
+			merged.Params[k] = v
+		}
+	}
+
+	return merged
+}
+
+// pairs lists the parameters in a stable order, UTM fields first.
+func (u UTM) pairs() [][2]string {
+	var pairs [][2]string
+	for _, p := range [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	} {
+		if p[1] != "" {
+			pairs = append(pairs, p)
+		}
+	}
+
+	keys := make([]string, 0, len(u.Params))
+	for k := range u.Params {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if u.Params[k] != "" {
+			pairs = append(pairs, [2]string{k, u.Params[k]})
+		}
+	}
+
+	return pairs
+}
+
+// Apply appends the parameters to rawURL. The existing query string and
+// fragment are kept byte for byte, and parameters already present in the URL
+// are never overwritten.
+func Apply(rawURL string, u UTM) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return rawURL, ErrInvalidURL
+	}
+
+	pairs := u.pairs()
+	if len(pairs) == 0 {
+		return rawURL, nil
+	}
+
+	base, fragment, hasFragment := strings.Cut(rawURL, "#")
+	_, query, hasQuery := strings.Cut(base, "?")
+	existing, _ := url.ParseQuery(query)
+
+	var b strings.Builder
+	b.WriteString(base)
+	separator := "&"
+	if !hasQuery {
+		separator = "?"
+	} else if query == "" || strings.HasSuffix(query, "&") {
+		separator = ""
+	}
+
+	for _, p := range pairs {
+		if existing.Has(p[0]) {
+			continue
+		}
+		b.WriteString(separator)
+		b.WriteString(url.QueryEscape(p[0]))
+		b.WriteByte('=')
+		b.WriteString(url.QueryEscape(p[1]))
+		separator = "&"
+	}
+
+	if hasFragment {
+		b.WriteByte('#')
+		b.WriteString(fragment)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,21 @@
+package audit_repository
+
+import (
+	"context"
+	"time"
+)
+
+type Entry struct {
+	ID        int32
+	PageID    int32
+	AccountID int32
+	Email     string
+	Action    string
+	Details   map[string]string
+	CreatedAt time.Time
+}
+
+type AuditRepository interface {
+	CreateEntry(ctx context.Context, entry Entry) error
+	ListEntries(ctx context.Context, pageID int32, limit int32) ([]Entry, error)
+}
@@ -0,0 +1,68 @@
+package audit_repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+)
+
+type DbAuditRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbAuditRepository(pool *pgxpool.Pool) *DbAuditRepository {
+	return &DbAuditRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbAuditRepository) CreateEntry(ctx context.Context, entry Entry) error {
+	details := entry.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+
+	encoded, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.CreateAuditEntry(ctx, db.CreateAuditEntryParams{
+		PageID:    entry.PageID,
+		AccountID: pgtype.Int4{Int32: entry.AccountID, Valid: entry.AccountID != 0},
+		Action:    entry.Action,
+		Details:   encoded,
+	})
+}
+
+func (r *DbAuditRepository) ListEntries(ctx context.Context, pageID int32, limit int32) ([]Entry, error) {
+	rows, err := r.queries.ListAuditEntries(ctx, db.ListAuditEntriesParams{PageID: pageID, Limit: limit})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		var details map[string]string
+		if err := json.Unmarshal(row.Details, &details); err != nil {
+			return nil, err
+		}
+
+		result = append(result, Entry{
+			ID:        row.ID,
+			PageID:    row.PageID,
+			AccountID: row.AccountID.Int32,
+			Email:     row.Email.String,
+			Action:    row.Action,
+			Details:   details,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,33 @@
+package audit_repository
+
+import (
+	"context"
+	"time"
+)
+
+type InMemoryAuditRepository struct {
+	Entries []Entry
+}
+
+func NewInMemoryAuditRepository() *InMemoryAuditRepository {
+	return &InMemoryAuditRepository{
+		Entries: []Entry{},
+	}
+}
+
+func (r *InMemoryAuditRepository) CreateEntry(ctx context.Context, entry Entry) error {
+	entry.ID = int32(len(r.Entries) + 1)
+	entry.CreatedAt = time.Now()
+	r.Entries = append(r.Entries, entry)
+	return nil
+}
+
+func (r *InMemoryAuditRepository) ListEntries(ctx context.Context, pageID int32, limit int32) ([]Entry, error) {
+	result := []Entry{}
+	for i := len(r.Entries) - 1; i >= 0 && int32(len(result)) < limit; i-- {
+		if r.Entries[i].PageID == pageID {
+			result = append(result, r.Entries[i])
+		}
+	}
+	return result, nil
+}
@@ -9,4 +9,7 @@ type CacheRepository interface {
 	Set(ctx context.Context, key string, value any, exp time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
 	Del(ctx context.Context, key string) error
+	// Incr increments the counter at key and returns its new value. The
+	// counter expires exp after it was created.
+	Incr(ctx context.Context, key string, exp time.Duration) (int64, error)
 }
@@ -2,6 +2,7 @@ package cache_repository
 
 import (
 	"context"
+	"strconv"
 	"time"
 )
 
@@ -28,6 +29,13 @@ func (r *InMemoryCacheRepository) Get(ctx context.Context, key string) (string,
 	return value, nil
 }
 
+func (r *InMemoryCacheRepository) Incr(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	n, _ := strconv.ParseInt(r.data[key], 10, 64)
+	n++
+	r.data[key] = strconv.FormatInt(n, 10)
+	return n, nil
+}
+
 func (r *InMemoryCacheRepository) Del(ctx context.Context, key string) error {
 	delete(r.data, key)
 	return nil
@@ -33,6 +33,19 @@ func (rs *RedisCacheRepository) Get(ctx context.Context, key string) (string, er
 	return rs.Client.Get(ctx, key).Result()
 }
 
+func (rs *RedisCacheRepository) Incr(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	n, err := rs.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if n == 1 {
+		if err := rs.Client.Expire(ctx, key, exp).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
 func (rs *RedisCacheRepository) Del(ctx context.Context, key string) error {
 	return rs.Client.Del(ctx, key).Err()
 }
@@ -0,0 +1,16 @@
+package click_repository
+
+import (
+	"context"
+)
+
+type Click struct {
+	PageID int32
+	LinkID int32
+	Source string
+}
+
+type ClickRepository interface {
+	RecordLinkClick(ctx context.Context, linkID int32, source string) error
+	RecordProfileView(ctx context.Context, pageID int32, source string) error
+}
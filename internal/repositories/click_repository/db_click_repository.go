@@ -0,0 +1,34 @@
+package click_repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+)
+
+type DbClickRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbClickRepository(pool *pgxpool.Pool) *DbClickRepository {
+	return &DbClickRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbClickRepository) RecordLinkClick(ctx context.Context, linkID int32, source string) error {
+	return r.queries.RecordLinkClick(ctx, db.RecordLinkClickParams{
+		ID:     linkID,
+		Source: source,
+	})
+}
+
+func (r *DbClickRepository) RecordProfileView(ctx context.Context, pageID int32, source string) error {
+	return r.queries.RecordProfileView(ctx, db.RecordProfileViewParams{
+		PageID: pageID,
+		Source: source,
+	})
+}
@@ -0,0 +1,23 @@
+package click_repository
+
+import (
+	"context"
+)
+
+type InMemoryClickRepository struct {
+	Clicks []Click
+}
+
+func NewInMemoryClickRepository() *InMemoryClickRepository {
+	return &InMemoryClickRepository{}
+}
+
+func (r *InMemoryClickRepository) RecordLinkClick(ctx context.Context, linkID int32, source string) error {
+	r.Clicks = append(r.Clicks, Click{LinkID: linkID, Source: source})
+	return nil
+}
+
+func (r *InMemoryClickRepository) RecordProfileView(ctx context.Context, pageID int32, source string) error {
+	r.Clicks = append(r.Clicks, Click{PageID: pageID, Source: source})
+	return nil
+}
@@ -0,0 +1,43 @@
+package collaborator_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	ErrInvitationNotFound   = errors.New("invitation not found")
+)
+
+type Collaborator struct {
+	PageID    int32
+	AccountID int32
+	Role      string
+	Email     string
+	Name      string
+	CreatedAt time.Time
+}
+
+type Invitation struct {
+	ID        int32
+	PageID    int32
+	Email     string
+	Role      string
+	TokenHash string
+	InvitedBy int32
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+type CollaboratorRepository interface {
+	GetCollaborator(ctx context.Context, pageID, accountID int32) (Collaborator, error)
+	ListCollaborators(ctx context.Context, pageID int32) ([]Collaborator, error)
+	ListCollaborations(ctx context.Context, accountID int32) ([]Collaborator, error)
+	RemoveCollaborator(ctx context.Context, pageID, accountID int32) error
+	CreateInvitation(ctx context.Context, invitation Invitation) (Invitation, error)
+	FindInvitationByTokenHash(ctx context.Context, tokenHash string) (Invitation, error)
+	ListInvitations(ctx context.Context, pageID int32) ([]Invitation, error)
+	AcceptInvitation(ctx context.Context, invitation Invitation, accountID int32) error
+}
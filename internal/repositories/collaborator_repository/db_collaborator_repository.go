@@ -0,0 +1,173 @@
+package collaborator_repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+)
+
+type DbCollaboratorRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbCollaboratorRepository(pool *pgxpool.Pool) *DbCollaboratorRepository {
+	return &DbCollaboratorRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbCollaboratorRepository) GetCollaborator(ctx context.Context, pageID, accountID int32) (Collaborator, error) {
+	collaborator, err := r.queries.GetCollaborator(ctx, db.GetCollaboratorParams{PageID: pageID, AccountID: accountID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Collaborator{}, ErrCollaboratorNotFound
+		}
+		return Collaborator{}, err
+	}
+
+	return toCollaborator(collaborator), nil
+}
+
+func (r *DbCollaboratorRepository) ListCollaborators(ctx context.Context, pageID int32) ([]Collaborator, error) {
+	rows, err := r.queries.ListCollaborators(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Collaborator, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Collaborator{
+			PageID:    row.PageID,
+			AccountID: row.AccountID,
+			Role:      row.Role,
+			Email:     row.Email,
+			Name:      row.Name.String,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *DbCollaboratorRepository) ListCollaborations(ctx context.Context, accountID int32) ([]Collaborator, error) {
+	rows, err := r.queries.ListCollaborationsByAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Collaborator, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toCollaborator(row))
+	}
+
+	return result, nil
+}
+
+func (r *DbCollaboratorRepository) RemoveCollaborator(ctx context.Context, pageID, accountID int32) error {
+	rows, err := r.queries.RemoveCollaborator(ctx, db.RemoveCollaboratorParams{PageID: pageID, AccountID: accountID})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrCollaboratorNotFound
+	}
+
+	return nil
+}
+
+func (r *DbCollaboratorRepository) CreateInvitation(ctx context.Context, invitation Invitation) (Invitation, error) {
+	created, err := r.queries.UpsertInvitation(ctx, db.UpsertInvitationParams{
+		PageID:    invitation.PageID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		TokenHash: invitation.TokenHash,
+		InvitedBy: pgtype.Int4{Int32: invitation.InvitedBy, Valid: invitation.InvitedBy != 0},
+		ExpiresAt: pgtype.Timestamp{Time: invitation.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	return toInvitation(created), nil
+}
+
+func (r *DbCollaboratorRepository) FindInvitationByTokenHash(ctx context.Context, tokenHash string) (Invitation, error) {
+	invitation, err := r.queries.GetInvitationByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Invitation{}, ErrInvitationNotFound
+		}
+		return Invitation{}, err
+	}
+
+	return toInvitation(invitation), nil
+}
+
+func (r *DbCollaboratorRepository) ListInvitations(ctx context.Context, pageID int32) ([]Invitation, error) {
+	rows, err := r.queries.ListInvitations(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Invitation, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toInvitation(row))
+	}
+
+	return result, nil
+}
+
+// AcceptInvitation adds the account as a collaborator and consumes the
+// invitation in the same transaction.
+func (r *DbCollaboratorRepository) AcceptInvitation(ctx context.Context, invitation Invitation, accountID int32) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := r.queries.WithTx(tx)
+
+	if err := queries.AddCollaborator(ctx, db.AddCollaboratorParams{
+		PageID:    invitation.PageID,
+		AccountID: accountID,
+		Role:      invitation.Role,
+	}); err != nil {
+		return err
+	}
+
+	if err := queries.DeleteInvitation(ctx, invitation.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func toCollaborator(collaborator db.PageCollaborator) Collaborator {
+	return Collaborator{
+		PageID:    collaborator.PageID,
+		AccountID: collaborator.AccountID,
+		Role:      collaborator.Role,
+		CreatedAt: collaborator.CreatedAt.Time,
+	}
+}
+
+func toInvitation(invitation db.PageInvitation) Invitation {
+	return Invitation{
+		ID:        invitation.ID,
+		PageID:    invitation.PageID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		TokenHash: invitation.TokenHash,
+		InvitedBy: invitation.InvitedBy.Int32,
+		ExpiresAt: invitation.ExpiresAt.Time,
+		CreatedAt: invitation.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,110 @@
+package collaborator_repository
+
+import (
+	"context"
+	"time"
+)
+
+type InMemoryCollaboratorRepository struct {
+	Collaborators []Collaborator
+	Invitations   []Invitation
+}
+
+func NewInMemoryCollaboratorRepository() *InMemoryCollaboratorRepository {
+	return &InMemoryCollaboratorRepository{
+		Collaborators: []Collaborator{},
+		Invitations:   []Invitation{},
+	}
+}
+
+func (r *InMemoryCollaboratorRepository) GetCollaborator(ctx context.Context, pageID, accountID int32) (Collaborator, error) {
+	for _, c := range r.Collaborators {
+		if c.PageID == pageID && c.AccountID == accountID {
+			return c, nil
+		}
+	}
+	return Collaborator{}, ErrCollaboratorNotFound
+}
+
+func (r *InMemoryCollaboratorRepository) ListCollaborators(ctx context.Context, pageID int32) ([]Collaborator, error) {
+	result := []Collaborator{}
+	for _, c := range r.Collaborators {
+		if c.PageID == pageID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryCollaboratorRepository) ListCollaborations(ctx context.Context, accountID int32) ([]Collaborator, error) {
+	result := []Collaborator{}
+	for _, c := range r.Collaborators {
+		if c.AccountID == accountID {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryCollaboratorRepository) RemoveCollaborator(ctx context.Context, pageID, accountID int32) error {
+	for i, c := range r.Collaborators {
+		if c.PageID == pageID && c.AccountID == accountID {
+			r.Collaborators = append(r.Collaborators[:i], r.Collaborators[i+1:]...)
+			return nil
+		}
+	}
+	return ErrCollaboratorNotFound
+}
+
+func (r *InMemoryCollaboratorRepository) CreateInvitation(ctx context.Context, invitation Invitation) (Invitation, error) {
+	invitation.CreatedAt = time.Now()
+	for i, inv := range r.Invitations {
+		if inv.PageID == invitation.PageID && inv.Email == invitation.Email {
+			invitation.ID = inv.ID
+			r.Invitations[i] = invitation
+			return invitation, nil
+		}
+	}
+
+	invitation.ID = int32(len(r.Invitations) + 1)
+	r.Invitations = append(r.Invitations, invitation)
+	return invitation, nil
+}
+
+func (r *InMemoryCollaboratorRepository) FindInvitationByTokenHash(ctx context.Context, tokenHash string) (Invitation, error) {
+	for _, inv := range r.Invitations {
+		if inv.TokenHash == tokenHash {
+			return inv, nil
+		}
+	}
+	return Invitation{}, ErrInvitationNotFound
+}
+
+func (r *InMemoryCollaboratorRepository) ListInvitations(ctx context.Context, pageID int32) ([]Invitation, error) {
+	result := []Invitation{}
+	for _, inv := range r.Invitations {
+		if inv.PageID == pageID && inv.ExpiresAt.After(time.Now()) {
+			result = append(result, inv)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryCollaboratorRepository) AcceptInvitation(ctx context.Context, invitation Invitation, accountID int32) error {
+	r.RemoveCollaborator(ctx, invitation.PageID, accountID)
+	r.Collaborators = append(r.Collaborators, Collaborator{
+		PageID:    invitation.PageID,
+		AccountID: accountID,
+		Role:      invitation.Role,
+		Email:     invitation.Email,
+		CreatedAt: time.Now(),
+	})
+
+	for i, inv := range r.Invitations {
+		if inv.ID == invitation.ID {
+			r.Invitations = append(r.Invitations[:i], r.Invitations[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
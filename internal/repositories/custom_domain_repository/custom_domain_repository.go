@@ -0,0 +1,35 @@
+package custom_domain_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDomainNotFound   = errors.New("domain not found")
+	ErrDuplicatedDomain = errors.New("domain already registered")
+)
+
+type CustomDomain struct {
+	ID                int32
+	PageID            int32
+	Domain            string
+	VerificationToken string
+	Status            string
+	FailureReason     string
+	LastCheckedAt     time.Time
+	VerifiedAt        time.Time
+	CreatedAt         time.Time
+}
+
+type CustomDomainRepository interface {
+	CreateDomain(ctx context.Context, domain CustomDomain) (CustomDomain, error)
+	FindDomainByID(ctx context.Context, id int32) (CustomDomain, error)
+	FindVerifiedDomain(ctx context.Context, domain string) (CustomDomain, error)
+	ListDomainsByPage(ctx context.Context, pageID int32) ([]CustomDomain, error)
+	ListDomainsToVerify(ctx context.Context, checkedBefore time.Time, limit int32) ([]CustomDomain, error)
+	UpdateDomainStatus(ctx context.Context, id int32, status, reason string) error
+	DeleteDomain(ctx context.Context, pageID, id int32) error
+	DeleteExpiredDomains(ctx context.Context, createdBefore time.Time) (int64, error)
+}
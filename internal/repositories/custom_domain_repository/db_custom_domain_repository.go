@@ -0,0 +1,145 @@
+package custom_domain_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+)
+
+type DbCustomDomainRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbCustomDomainRepository(pool *pgxpool.Pool) *DbCustomDomainRepository {
+	return &DbCustomDomainRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbCustomDomainRepository) CreateDomain(ctx context.Context, domain CustomDomain) (CustomDomain, error) {
+	created, err := r.queries.CreateCustomDomain(ctx, db.CreateCustomDomainParams{
+		PageID:            domain.PageID,
+		Domain:            domain.Domain,
+		VerificationToken: domain.VerificationToken,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return CustomDomain{}, ErrDuplicatedDomain
+		}
+		return CustomDomain{}, err
+	}
+
+	return toCustomDomain(created), nil
+}
+
+func (r *DbCustomDomainRepository) FindDomainByID(ctx context.Context, id int32) (CustomDomain, error) {
+	domain, err := r.queries.FindCustomDomainByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return CustomDomain{}, ErrDomainNotFound
+		}
+		return CustomDomain{}, err
+	}
+
+	return toCustomDomain(domain), nil
+}
+
+func (r *DbCustomDomainRepository) FindVerifiedDomain(ctx context.Context, name string) (CustomDomain, error) {
+	domain, err := r.queries.FindVerifiedCustomDomain(ctx, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return CustomDomain{}, ErrDomainNotFound
+		}
+		return CustomDomain{}, err
+	}
+
+	return toCustomDomain(domain), nil
+}
+
+func (r *DbCustomDomainRepository) ListDomainsByPage(ctx context.Context, pageID int32) ([]CustomDomain, error) {
+	domains, err := r.queries.ListCustomDomainsByPage(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CustomDomain, 0, len(domains))
+	for _, domain := range domains {
+		result = append(result, toCustomDomain(domain))
+	}
+
+	return result, nil
+}
+
+func (r *DbCustomDomainRepository) ListDomainsToVerify(ctx context.Context, checkedBefore time.Time, limit int32) ([]CustomDomain, error) {
+	domains, err := r.queries.ListCustomDomainsToVerify(ctx, db.ListCustomDomainsToVerifyParams{
+		LastCheckedAt: pgtype.Timestamp{Time: checkedBefore, Valid: true},
+		Limit:         limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]CustomDomain, 0, len(domains))
+	for _, domain := range domains {
+		result = append(result, toCustomDomain(domain))
+	}
+
+	return result, nil
+}
+
+func (r *DbCustomDomainRepository) UpdateDomainStatus(ctx context.Context, id int32, status, reason string) error {
+	err := r.queries.UpdateCustomDomainStatus(ctx, db.UpdateCustomDomainStatusParams{
+		ID:            id,
+		Status:        status,
+		FailureReason: reason,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDuplicatedDomain
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *DbCustomDomainRepository) DeleteDomain(ctx context.Context, pageID, id int32) error {
+	rows, err := r.queries.DeleteCustomDomain(ctx, db.DeleteCustomDomainParams{ID: id, PageID: pageID})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrDomainNotFound
+	}
+
+	return nil
+}
+
+func (r *DbCustomDomainRepository) DeleteExpiredDomains(ctx context.Context, createdBefore time.Time) (int64, error) {
+	return r.queries.DeleteExpiredCustomDomains(ctx, pgtype.Timestamp{Time: createdBefore, Valid: true})
+}
+
+func toCustomDomain(domain db.CustomDomain) CustomDomain {
+	return CustomDomain{
+		ID:                domain.ID,
+		PageID:            domain.PageID,
+		Domain:            domain.Domain,
+		VerificationToken: domain.VerificationToken,
+		Status:            domain.Status,
+		FailureReason:     domain.FailureReason,
+		LastCheckedAt:     domain.LastCheckedAt.Time,
+		VerifiedAt:        domain.VerifiedAt.Time,
+		CreatedAt:         domain.CreatedAt.Time,
+	}
+}
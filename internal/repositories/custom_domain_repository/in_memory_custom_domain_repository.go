@@ -0,0 +1,117 @@
+package custom_domain_repository
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+type InMemoryCustomDomainRepository struct {
+	Domains []CustomDomain
+}
+
+func NewInMemoryCustomDomainRepository() *InMemoryCustomDomainRepository {
+	return &InMemoryCustomDomainRepository{
+		Domains: []CustomDomain{},
+	}
+}
+
+func (r *InMemoryCustomDomainRepository) CreateDomain(ctx context.Context, domain CustomDomain) (CustomDomain, error) {
+	for _, d := range r.Domains {
+		if d.Domain == domain.Domain && d.PageID == domain.PageID {
+			return CustomDomain{}, ErrDuplicatedDomain
+		}
+	}
+
+	domain.ID = int32(len(r.Domains) + 1)
+	domain.Status = "pending"
+	domain.CreatedAt = time.Now()
+	r.Domains = append(r.Domains, domain)
+
+	return domain, nil
+}
+
+func (r *InMemoryCustomDomainRepository) FindDomainByID(ctx context.Context, id int32) (CustomDomain, error) {
+	for _, d := range r.Domains {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return CustomDomain{}, ErrDomainNotFound
+}
+
+func (r *InMemoryCustomDomainRepository) FindVerifiedDomain(ctx context.Context, domain string) (CustomDomain, error) {
+	for _, d := range r.Domains {
+		if d.Domain == domain && d.Status == "verified" {
+			return d, nil
+		}
+	}
+	return CustomDomain{}, ErrDomainNotFound
+}
+
+func (r *InMemoryCustomDomainRepository) ListDomainsByPage(ctx context.Context, pageID int32) ([]CustomDomain, error) {
+	result := []CustomDomain{}
+	for _, d := range r.Domains {
+		if d.PageID == pageID {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryCustomDomainRepository) ListDomainsToVerify(ctx context.Context, checkedBefore time.Time, limit int32) ([]CustomDomain, error) {
+	result := []CustomDomain{}
+	for _, d := range r.Domains {
+		if d.LastCheckedAt.IsZero() || d.LastCheckedAt.Before(checkedBefore) {
+			result = append(result, d)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b CustomDomain) int {
+		return a.LastCheckedAt.Compare(b.LastCheckedAt)
+	})
+
+	if len(result) > int(limit) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (r *InMemoryCustomDomainRepository) UpdateDomainStatus(ctx context.Context, id int32, status, reason string) error {
+	for i, d := range r.Domains {
+		if d.ID != id {
+			continue
+		}
+		if status == "verified" && slices.ContainsFunc(r.Domains, func(o CustomDomain) bool {
+			return o.ID != id && o.Domain == d.Domain && o.Status == "verified"
+		}) {
+			return ErrDuplicatedDomain
+		}
+		r.Domains[i].Status = status
+		r.Domains[i].FailureReason = reason
+		r.Domains[i].LastCheckedAt = time.Now()
+		if status == "verified" && d.VerifiedAt.IsZero() {
+			r.Domains[i].VerifiedAt = time.Now()
+		}
+		return nil
+	}
+	return ErrDomainNotFound
+}
+
+func (r *InMemoryCustomDomainRepository) DeleteDomain(ctx context.Context, pageID, id int32) error {
+	for i, d := range r.Domains {
+		if d.ID == id && d.PageID == pageID {
+			r.Domains = slices.Delete(r.Domains, i, i+1)
+			return nil
+		}
+	}
+	return ErrDomainNotFound
+}
+
+func (r *InMemoryCustomDomainRepository) DeleteExpiredDomains(ctx context.Context, createdBefore time.Time) (int64, error) {
+	before := len(r.Domains)
+	r.Domains = slices.DeleteFunc(r.Domains, func(d CustomDomain) bool {
+		return d.Status == "pending" && d.CreatedAt.Before(createdBefore)
+	})
+	return int64(before - len(r.Domains)), nil
+}
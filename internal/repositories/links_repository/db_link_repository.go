@@ -2,11 +2,17 @@ package links_repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/theNixagen/linker/internal/db"
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/utm"
 )
 
 type DbLinksRepository struct {
@@ -21,22 +27,41 @@ func NewDbLinksRepository(pool *pgxpool.Pool) *DbLinksRepository {
 	}
 }
 
-func (r *DbLinksRepository) CreateLink(ctx context.Context, UserID int32, Url, Title, Description string) error {
-	err := r.queries.CreateLink(ctx, db.CreateLinkParams{
-		UserID:      UserID,
-		Url:         Url,
-		Title:       Title,
-		Description: Description,
+func (r *DbLinksRepository) CreateLink(ctx context.Context, link Link) (int32, error) {
+	id, err := r.queries.CreateLink(ctx, db.CreateLinkParams{
+		PageID:       link.PageID,
+		Url:          link.Url,
+		Title:        link.Title,
+		Description:  link.Description,
+		Slug:         pgtype.Text{String: link.Slug, Valid: link.Slug != ""},
+		ShortCode:    link.ShortCode,
+		Platform:     link.Platform,
+		Handle:       link.Handle,
+		Sensitive:    link.Sensitive,
+		PasswordHash: link.PasswordHash,
+
+		ThumbnailMediaID: pgtype.Int4{Int32: link.ThumbnailMediaID, Valid: link.ThumbnailMediaID != 0},
+		Thumbnail:        link.Thumbnail,
 	})
 
 	if err != nil {
-		return err
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			if pgErr.ConstraintName == "links_short_code_key" {
+				return 0, ErrDuplicatedShortCode
+			}
+			return 0, ErrDuplicatedSlug
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return 0, ErrThumbnailNotFound
+		}
+		return 0, err
 	}
-	return nil
+	return id, nil
 }
 
-func (r *DbLinksRepository) FindAllLinksFromAUser(ctx context.Context, userID int32) ([]Link, error) {
-	links, err := r.queries.FindAllLinksFromAUser(ctx, userID)
+func (r *DbLinksRepository) FindAllLinksFromAPage(ctx context.Context, pageID int32) ([]Link, error) {
+	links, err := r.queries.FindAllLinksFromAPage(ctx, pageID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrLinksNotFound
@@ -50,15 +75,150 @@ func (r *DbLinksRepository) FindAllLinksFromAUser(ctx context.Context, userID in
 
 	var result []Link
 	for _, link := range links {
-		result = append(result, Link{
-			ID:          link.ID,
-			UserID:      link.UserID,
-			Url:         link.Url,
-			Title:       link.Title,
-			Description: link.Description,
-			CreatedAt:   link.CreatedAt.Time,
-		})
+		result = append(result, toLink(link))
+	}
+
+	return result, nil
+}
+
+func (r *DbLinksRepository) FindPublishedLinksFromAPage(ctx context.Context, pageID int32) ([]Link, error) {
+	links, err := r.queries.FindPublishedLinksFromAPage(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(links) == 0 {
+		return nil, ErrLinksNotFound
+	}
+
+	var result []Link
+	for _, link := range links {
+		result = append(result, toLink(link))
+	}
+
+	return result, nil
+}
+
+func (r *DbLinksRepository) FindLinkByID(ctx context.Context, id int32) (Link, error) {
+	link, err := r.queries.FindLinkByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Link{}, ErrLinkNotFound
+		}
+		return Link{}, err
+	}
+
+	return toLink(link), nil
+}
+
+func (r *DbLinksRepository) FindLinkBySlug(ctx context.Context, pageID int32, slug string) (Link, error) {
+	link, err := r.queries.FindLinkBySlug(ctx, db.FindLinkBySlugParams{
+		PageID: pageID,
+		Slug:   pgtype.Text{String: slug, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Link{}, ErrLinkNotFound
+		}
+		return Link{}, err
+	}
+
+	return toLink(link), nil
+}
+
+func (r *DbLinksRepository) FindLinkByShortCode(ctx context.Context, shortCode string) (Link, error) {
+	link, err := r.queries.FindLinkByShortCode(ctx, shortCode)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Link{}, ErrLinkNotFound
+		}
+		return Link{}, err
+	}
+
+	return toLink(link), nil
+}
+
+func (r *DbLinksRepository) UpdateLinkUTM(ctx context.Context, pageID, linkID int32, tags utm.UTM) error {
+	encoded, err := json.Marshal(tags)
+	if err != nil {
+		return err
 	}
 
+	rows, err := r.queries.UpdateLinkUTM(ctx, db.UpdateLinkUTMParams{Utm: encoded, ID: linkID, PageID: pageID})
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrLinkNotFound
+	}
+
+	return nil
+}
+
+func (r *DbLinksRepository) ListStaleEmbeds(ctx context.Context, fetchedBefore time.Time, limit int32) ([]Link, error) {
+	rows, err := r.queries.ListStaleEmbedLinks(ctx, db.ListStaleEmbedLinksParams{
+		EmbedFetchedAt: pgtype.Timestamp{Time: fetchedBefore, Valid: true},
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Link, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Link{ID: row.ID, Url: row.Url, Embed: toEmbed(row.Embed)})
+	}
 	return result, nil
 }
+
+func (r *DbLinksRepository) UpdateLinkEmbed(ctx context.Context, linkID int32, url string, embed *links.Embed) error {
+	var encoded []byte
+	if embed != nil {
+		var err error
+		if encoded, err = json.Marshal(embed); err != nil {
+			return err
+		}
+	}
+
+	return r.queries.UpdateLinkEmbed(ctx, db.UpdateLinkEmbedParams{Embed: encoded, ID: linkID, Url: url})
+}
+
+func toEmbed(encoded []byte) *links.Embed {
+	if len(encoded) == 0 {
+		return nil
+	}
+	var embed *links.Embed
+	json.Unmarshal(encoded, &embed)
+	return embed
+}
+
+func toLink(link db.Link) Link {
+	var tags, publishedTags utm.UTM
+	json.Unmarshal(link.Utm, &tags)
+	json.Unmarshal(link.PublishedUtm, &publishedTags)
+
+	return Link{
+		ID:           link.ID,
+		PageID:       link.PageID,
+		Url:          link.Url,
+		Title:        link.Title,
+		Description:  link.Description,
+		Slug:         link.Slug.String,
+		ShortCode:    link.ShortCode,
+		Platform:     link.Platform,
+		Handle:       link.Handle,
+		Sensitive:    link.Sensitive,
+		PasswordHash: link.PasswordHash,
+		UTM:          tags,
+		Published:    link.Published,
+		CreatedAt:    link.CreatedAt.Time,
+		PublishedUTM: publishedTags,
+
+		Embed:          toEmbed(link.Embed),
+		EmbedFetchedAt: link.EmbedFetchedAt.Time,
+
+		ThumbnailMediaID: link.ThumbnailMediaID.Int32,
+		Thumbnail:        link.Thumbnail,
+	}
+}
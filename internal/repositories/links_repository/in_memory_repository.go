@@ -2,6 +2,10 @@ package links_repository
 
 import (
 	"context"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/utm"
 )
 
 type InMemoryLinksRepository struct {
@@ -14,32 +18,45 @@ func NewInMemoryLinksRepository() *InMemoryLinksRepository {
 	}
 }
 
-func (r *InMemoryLinksRepository) CreateLink(ctx context.Context, UserID int32, Url, Title, Description string) error {
-	r.Links = append(r.Links, Link{
-		ID:          int32(len(r.Links) + 1),
-		UserID:      UserID,
-		Url:         Url,
-		Title:       Title,
-		Description: Description,
-	})
+func (r *InMemoryLinksRepository) CreateLink(ctx context.Context, link Link) (int32, error) {
+	for _, l := range r.Links {
+		if l.ShortCode == link.ShortCode {
+			return 0, ErrDuplicatedShortCode
+		}
+		if link.Slug != "" && l.PageID == link.PageID && l.Slug == link.Slug {
+			return 0, ErrDuplicatedSlug
+		}
+	}
 
-	return nil
+	link.ID = int32(len(r.Links) + 1)
+	r.Links = append(r.Links, link)
+
+	return link.ID, nil
+}
+
+func (r *InMemoryLinksRepository) FindAllLinksFromAPage(ctx context.Context, pageID int32) ([]Link, error) {
+	var result []Link
+	for _, link := range r.Links {
+		if link.PageID != pageID {
+			continue
+		}
+		result = append(result, link)
+	}
+
+	if len(result) == 0 {
+		return nil, ErrLinksNotFound
+	}
+
+	return result, nil
 }
 
-func (r *InMemoryLinksRepository) FindAllLinksFromAUser(ctx context.Context, userID int32) ([]Link, error) {
+func (r *InMemoryLinksRepository) FindPublishedLinksFromAPage(ctx context.Context, pageID int32) ([]Link, error) {
 	var result []Link
 	for _, link := range r.Links {
-		if link.UserID != userID {
+		if link.PageID != pageID || !link.Published {
 			continue
 		}
-		result = append(result, Link{
-			ID:          link.ID,
-			UserID:      link.UserID,
-			Url:         link.Url,
-			Title:       link.Title,
-			Description: link.Description,
-			CreatedAt:   link.CreatedAt,
-		})
+		result = append(result, link)
 	}
 
 	if len(result) == 0 {
@@ -48,3 +65,73 @@ func (r *InMemoryLinksRepository) FindAllLinksFromAUser(ctx context.Context, use
 
 	return result, nil
 }
+
+func (r *InMemoryLinksRepository) FindLinkByID(ctx context.Context, id int32) (Link, error) {
+	for _, link := range r.Links {
+		if link.ID == id {
+			return link, nil
+		}
+	}
+	return Link{}, ErrLinkNotFound
+}
+
+func (r *InMemoryLinksRepository) FindLinkBySlug(ctx context.Context, pageID int32, slug string) (Link, error) {
+	for _, link := range r.Links {
+		if link.PageID == pageID && link.Slug == slug {
+			return link, nil
+		}
+	}
+	return Link{}, ErrLinkNotFound
+}
+
+func (r *InMemoryLinksRepository) FindLinkByShortCode(ctx context.Context, shortCode string) (Link, error) {
+	for _, link := range r.Links {
+		if link.ShortCode == shortCode {
+			return link, nil
+		}
+	}
+	return Link{}, ErrLinkNotFound
+}
+
+func (r *InMemoryLinksRepository) UpdateLinkUTM(ctx context.Context, pageID, linkID int32, tags utm.UTM) error {
+	for i, link := range r.Links {
+		if link.ID == linkID && link.PageID == pageID {
+			r.Links[i].UTM = tags
+			return nil
+		}
+	}
+	return ErrLinkNotFound
+}
+
+func (r *InMemoryLinksRepository) ListStaleEmbeds(ctx context.Context, fetchedBefore time.Time, limit int32) ([]Link, error) {
+	var result []Link
+	for _, link := range r.Links {
+		if int32(len(result)) == limit {
+			break
+		}
+		if link.Platform == "" && link.EmbedFetchedAt.Before(fetchedBefore) {
+			result = append(result, link)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryLinksRepository) UpdateLinkEmbed(ctx context.Context, linkID int32, url string, embed *links.Embed) error {
+	for i, link := range r.Links {
+		if link.ID == linkID && link.Url == url {
+			r.Links[i].Embed = embed
+			r.Links[i].EmbedFetchedAt = time.Now()
+		}
+	}
+	return nil
+}
+
+// PublishLinks marks every link of the user as published.
+func (r *InMemoryLinksRepository) PublishLinks(pageID int32) {
+	for i, link := range r.Links {
+		if link.PageID == pageID {
+			r.Links[i].Published = true
+			r.Links[i].PublishedUTM = link.UTM
+		}
+	}
+}
@@ -4,22 +4,59 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/utm"
 )
 
 var (
-	ErrLinksNotFound = errors.New("links not found")
+	ErrLinksNotFound       = errors.New("links not found")
+	ErrLinkNotFound        = errors.New("link not found")
+	ErrDuplicatedSlug      = errors.New("slug already exists")
+	ErrDuplicatedShortCode = errors.New("short code already exists")
+	ErrThumbnailNotFound   = errors.New("thumbnail media not found")
 )
 
 type Link struct {
-	ID          int32
-	UserID      int32
-	Url         string
-	Title       string
-	Description string
-	CreatedAt   time.Time
+	ID           int32
+	PageID       int32
+	Url          string
+	Title        string
+	Description  string
+	Slug         string
+	ShortCode    string
+	Platform     string
+	Handle       string
+	Sensitive    bool
+	PasswordHash string
+	UTM          utm.UTM
+	Published    bool
+	CreatedAt    time.Time
+	// PublishedUTM is the copy of UTM taken at the last publish, the one
+	// visitors are tagged with.
+	PublishedUTM utm.UTM
+	// Embed is filled in the background by the embed service. EmbedFetchedAt
+	// is zero until the first fetch.
+	Embed          *links.Embed
+	EmbedFetchedAt time.Time
+	// ThumbnailMediaID is the media library image shown next to the link,
+	// zero when there is none. Thumbnail is the object key of that image.
+	ThumbnailMediaID int32
+	Thumbnail        string
 }
 
 type LinksRepository interface {
-	CreateLink(ctx context.Context, UserID int32, Url, Title, Description string) error
-	FindAllLinksFromAUser(ctx context.Context, userID int32) ([]Link, error)
+	CreateLink(ctx context.Context, link Link) (int32, error)
+	FindAllLinksFromAPage(ctx context.Context, pageID int32) ([]Link, error)
+	FindPublishedLinksFromAPage(ctx context.Context, pageID int32) ([]Link, error)
+	FindLinkByID(ctx context.Context, id int32) (Link, error)
+	FindLinkBySlug(ctx context.Context, pageID int32, slug string) (Link, error)
+	FindLinkByShortCode(ctx context.Context, shortCode string) (Link, error)
+	UpdateLinkUTM(ctx context.Context, pageID, linkID int32, tags utm.UTM) error
+	// ListStaleEmbeds returns up to limit links, other than social profiles,
+	// whose embed was never fetched or was fetched before fetchedBefore.
+	ListStaleEmbeds(ctx context.Context, fetchedBefore time.Time, limit int32) ([]Link, error)
+	// UpdateLinkEmbed stores the embed of a link, unless its url changed
+	// since it was listed.
+	UpdateLinkEmbed(ctx context.Context, linkID int32, url string, embed *links.Embed) error
 }
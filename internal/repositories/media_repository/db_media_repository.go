@@ -0,0 +1,131 @@
+package media_repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+)
+
+type DbMediaRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbMediaRepository(pool *pgxpool.Pool) *DbMediaRepository {
+	return &DbMediaRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbMediaRepository) CreateMedia(ctx context.Context, media Media) (Media, error) {
+	created, err := r.queries.CreateMedia(ctx, db.CreateMediaParams{
+		PageID:      media.PageID,
+		ObjectKey:   media.ObjectKey,
+		ContentType: media.ContentType,
+		Width:       media.Width,
+		Height:      media.Height,
+		Size:        media.Size,
+	})
+	if err != nil {
+		return Media{}, err
+	}
+
+	return toMedia(created), nil
+}
+
+func (r *DbMediaRepository) GetMedia(ctx context.Context, pageID, id int32) (Media, error) {
+	media, err := r.queries.GetMedia(ctx, db.GetMediaParams{ID: id, PageID: pageID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Media{}, ErrMediaNotFound
+		}
+		return Media{}, err
+	}
+
+	return toMedia(media), nil
+}
+
+func (r *DbMediaRepository) ListMedia(ctx context.Context, pageID int32) ([]Media, error) {
+	rows, err := r.queries.ListMedia(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Media, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toMedia(row))
+	}
+
+	return result, nil
+}
+
+func (r *DbMediaRepository) UpdateAvatarSize(ctx context.Context, id int32, size int64) error {
+	return r.queries.UpdateMediaAvatarSize(ctx, db.UpdateMediaAvatarSizeParams{ID: id, AvatarSize: size})
+}
+
+func (r *DbMediaRepository) UpdateBannerSize(ctx context.Context, id int32, size int64) error {
+	return r.queries.UpdateMediaBannerSize(ctx, db.UpdateMediaBannerSizeParams{ID: id, BannerSize: size})
+}
+
+func (r *DbMediaRepository) ResetAvatarSize(ctx context.Context, objectKey string) error {
+	return r.queries.ResetMediaAvatarSize(ctx, objectKey)
+}
+
+func (r *DbMediaRepository) ResetBannerSize(ctx context.Context, objectKey string) error {
+	return r.queries.ResetMediaBannerSize(ctx, objectKey)
+}
+
+func (r *DbMediaRepository) Usage(ctx context.Context, accountID int32) (int64, error) {
+	return r.queries.GetMediaUsage(ctx, accountID)
+}
+
+func (r *DbMediaRepository) DeleteMedia(ctx context.Context, pageID, id int32, keys []string) error {
+	deleted, err := r.queries.DeleteUnreferencedMedia(ctx, db.DeleteUnreferencedMediaParams{ID: id, PageID: pageID, Keys: keys})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		if _, err := r.GetMedia(ctx, pageID, id); err != nil {
+			return err
+		}
+		return ErrMediaReferenced
+	}
+	return nil
+}
+
+func (r *DbMediaRepository) ListPicturesWithoutMedia(ctx context.Context) ([]PagePictures, error) {
+	rows, err := r.queries.ListPicturesWithoutMedia(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PagePictures, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, PagePictures{
+			PageID:         row.PageID,
+			ProfilePicture: row.ProfilePicture,
+			BannerPicture:  row.BannerPicture,
+		})
+	}
+
+	return result, nil
+}
+
+func toMedia(media db.Medium) Media {
+	return Media{
+		ID:          media.ID,
+		PageID:      media.PageID,
+		ObjectKey:   media.ObjectKey,
+		ContentType: media.ContentType,
+		Width:       media.Width,
+		Height:      media.Height,
+		Size:        media.Size,
+		AvatarSize:  media.AvatarSize,
+		BannerSize:  media.BannerSize,
+		CreatedAt:   media.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,188 @@
+package media_repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/revision"
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+)
+
+type InMemoryMediaRepository struct {
+	Media                      []Media
+	PageRepository             *page_repository.InMemoryPageRepository
+	ThemeRepository            *theme_repository.InMemoryThemeRepository
+	PublishedProfileRepository *published_profile_repository.InMemoryPublishedProfileRepository
+	RevisionRepository         *revision_repository.InMemoryRevisionRepository
+	nextID                     int32
+}
+
+func NewInMemoryMediaRepository(pageRepository *page_repository.InMemoryPageRepository, themeRepository *theme_repository.InMemoryThemeRepository, publishedProfileRepository *published_profile_repository.InMemoryPublishedProfileRepository, revisionRepository *revision_repository.InMemoryRevisionRepository) *InMemoryMediaRepository {
+	return &InMemoryMediaRepository{
+		Media:                      []Media{},
+		PageRepository:             pageRepository,
+		ThemeRepository:            themeRepository,
+		PublishedProfileRepository: publishedProfileRepository,
+		RevisionRepository:         revisionRepository,
+	}
+}
+
+func (r *InMemoryMediaRepository) CreateMedia(ctx context.Context, media Media) (Media, error) {
+	for _, m := range r.Media {
+		if m.PageID == media.PageID && m.ObjectKey == media.ObjectKey {
+			return m, nil
+		}
+	}
+
+	r.nextID++
+	media.ID = r.nextID
+	media.CreatedAt = time.Now()
+	r.Media = append(r.Media, media)
+	return media, nil
+}
+
+func (r *InMemoryMediaRepository) GetMedia(ctx context.Context, pageID, id int32) (Media, error) {
+	for _, m := range r.Media {
+		if m.ID == id && m.PageID == pageID {
+			return m, nil
+		}
+	}
+	return Media{}, ErrMediaNotFound
+}
+
+func (r *InMemoryMediaRepository) ListMedia(ctx context.Context, pageID int32) ([]Media, error) {
+	result := []Media{}
+	for i := len(r.Media) - 1; i >= 0; i-- {
+		if r.Media[i].PageID == pageID {
+			result = append(result, r.Media[i])
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryMediaRepository) UpdateAvatarSize(ctx context.Context, id int32, size int64) error {
+	for i := range r.Media {
+		if r.Media[i].ID == id {
+			r.Media[i].AvatarSize = size
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryMediaRepository) UpdateBannerSize(ctx context.Context, id int32, size int64) error {
+	for i := range r.Media {
+		if r.Media[i].ID == id {
+			r.Media[i].BannerSize = size
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryMediaRepository) ResetAvatarSize(ctx context.Context, objectKey string) error {
+	for i := range r.Media {
+		if r.Media[i].ObjectKey == objectKey {
+			r.Media[i].AvatarSize = 0
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryMediaRepository) ResetBannerSize(ctx context.Context, objectKey string) error {
+	for i := range r.Media {
+		if r.Media[i].ObjectKey == objectKey {
+			r.Media[i].BannerSize = 0
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryMediaRepository) Usage(ctx context.Context, accountID int32) (int64, error) {
+	var used int64
+	for _, m := range r.Media {
+		p, err := r.PageRepository.GetPageByID(ctx, m.PageID)
+		if err != nil {
+			continue
+		}
+		if p.AccountID == accountID {
+			used += m.Size + m.AvatarSize + m.BannerSize
+		}
+	}
+	return used, nil
+}
+
+func (r *InMemoryMediaRepository) DeleteMedia(ctx context.Context, pageID, id int32, keys []string) error {
+	for i, m := range r.Media {
+		if m.ID == id && m.PageID == pageID {
+			if r.referenced(pageID, id, keys) {
+				return ErrMediaReferenced
+			}
+			r.Media = append(r.Media[:i], r.Media[i+1:]...)
+			return nil
+		}
+	}
+	return ErrMediaNotFound
+}
+
+func (r *InMemoryMediaRepository) ListPicturesWithoutMedia(ctx context.Context) ([]PagePictures, error) {
+	result := []PagePictures{}
+	for _, p := range r.PageRepository.Pages {
+		if p.ProfilePicture == "" && p.BannerPicture == "" {
+			continue
+		}
+		if slices.ContainsFunc(r.Media, func(m Media) bool { return m.PageID == p.ID }) {
+			continue
+		}
+		result = append(result, PagePictures{PageID: p.ID, ProfilePicture: p.ProfilePicture, BannerPicture: p.BannerPicture})
+	}
+	return result, nil
+}
+
+func (r *InMemoryMediaRepository) referenced(pageID, id int32, keys []string) bool {
+	for _, l := range r.PublishedProfileRepository.LinksRepository.Links {
+		if l.ThumbnailMediaID == id {
+			return true
+		}
+	}
+	for _, rev := range r.RevisionRepository.Revisions {
+		if rev.PageID == pageID && slices.ContainsFunc(rev.Snapshot.Links, func(l revision.Link) bool { return l.ThumbnailMediaID == id }) {
+			return true
+		}
+	}
+
+	shown := []string{}
+	for _, p := range r.PageRepository.Pages {
+		if p.ID == pageID {
+			shown = append(shown, p.ProfilePicture, p.BannerPicture)
+		}
+	}
+	if t, ok := r.ThemeRepository.Themes[pageID]; ok {
+		shown = append(shown, t.Background.Image)
+	}
+	if p, ok := r.PublishedProfileRepository.Profiles[pageID]; ok {
+		shown = append(shown, p.ProfilePicture, p.BannerPicture, backgroundOf(p.Theme))
+	}
+	for _, rev := range r.RevisionRepository.Revisions {
+		if rev.PageID == pageID {
+			shown = append(shown, rev.Snapshot.ProfilePicture, rev.Snapshot.BannerPicture, backgroundOf(rev.Snapshot.Theme))
+		}
+	}
+
+	for _, key := range shown {
+		if key != "" && slices.Contains(keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func backgroundOf(t *theme.Theme) string {
+	if t == nil {
+		return ""
+	}
+	return t.Background.Image
+}
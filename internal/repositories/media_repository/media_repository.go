@@ -0,0 +1,61 @@
+package media_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrMediaNotFound   = errors.New("media not found")
+	ErrMediaReferenced = errors.New("media is still referenced")
+)
+
+// Media is an image kept in the library of a page, reusable as avatar,
+// banner or theme background.
+type Media struct {
+	ID          int32
+	PageID      int32
+	ObjectKey   string
+	ContentType string
+	Width       int32
+	Height      int32
+	Size        int64
+	// AvatarSize and BannerSize are the bytes of the avatar and banner
+	// variants made from the image, zero until it is first picked.
+	AvatarSize int64
+	BannerSize int64
+	CreatedAt  time.Time
+}
+
+// PagePictures are the pictures a page shows.
+type PagePictures struct {
+	PageID         int32
+	ProfilePicture string
+	BannerPicture  string
+}
+
+type MediaRepository interface {
+	// CreateMedia returns the existing media when the page already has the
+	// object.
+	CreateMedia(ctx context.Context, media Media) (Media, error)
+	GetMedia(ctx context.Context, pageID, id int32) (Media, error)
+	ListMedia(ctx context.Context, pageID int32) ([]Media, error)
+	UpdateAvatarSize(ctx context.Context, id int32, size int64) error
+	UpdateBannerSize(ctx context.Context, id int32, size int64) error
+	// ResetAvatarSize and ResetBannerSize zero the size of the pictures made
+	// from the media at objectKey, once they are removed from storage.
+	ResetAvatarSize(ctx context.Context, objectKey string) error
+	ResetBannerSize(ctx context.Context, objectKey string) error
+	// Usage returns the bytes of the libraries of the pages of the account,
+	// with the pictures made from them.
+	Usage(ctx context.Context, accountID int32) (int64, error)
+	// DeleteMedia deletes the media unless the page, its theme, its published
+	// profile or one of its revisions shows one of keys, or a link or a link
+	// of a revision uses it as thumbnail, in which case it returns
+	// ErrMediaReferenced. The check and the delete are atomic.
+	DeleteMedia(ctx context.Context, pageID, id int32, keys []string) error
+	// ListPicturesWithoutMedia returns the pictures of the pages whose media
+	// library is empty, set before the library existed.
+	ListPicturesWithoutMedia(ctx context.Context) ([]PagePictures, error)
+}
@@ -0,0 +1,160 @@
+package page_repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+	"github.com/theNixagen/linker/internal/domain/username"
+	"github.com/theNixagen/linker/internal/domain/utm"
+)
+
+type DbPageRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbPageRepository(pool *pgxpool.Pool) *DbPageRepository {
+	return &DbPageRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbPageRepository) CreatePage(ctx context.Context, page Page) (int32, error) {
+	id, err := r.queries.CreatePage(ctx, db.CreatePageParams{
+		AccountID:      page.AccountID,
+		Handle:         page.Handle,
+		Name:           page.Name,
+		HandleSkeleton: pgtype.Text{String: username.Skeleton(page.Handle), Valid: true},
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, ErrDuplicatedHandle
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *DbPageRepository) GetPageByHandle(ctx context.Context, handle string) (Page, error) {
+	page, err := r.queries.GetPageByHandle(ctx, handle)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Page{}, ErrPageNotFound
+		}
+		return Page{}, err
+	}
+
+	return toPage(page)
+}
+
+func (r *DbPageRepository) GetPageByID(ctx context.Context, id int32) (Page, error) {
+	page, err := r.queries.GetPageByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Page{}, ErrPageNotFound
+		}
+		return Page{}, err
+	}
+
+	return toPage(page)
+}
+
+func (r *DbPageRepository) ListPagesByAccount(ctx context.Context, accountID int32) ([]Page, error) {
+	pages, err := r.queries.ListPagesByAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Page, 0, len(pages))
+	for _, page := range pages {
+		p, err := toPage(page)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+func (r *DbPageRepository) UpdateBio(ctx context.Context, handle, bio string) error {
+	rows, err := r.queries.UpdatePageBio(ctx, db.UpdatePageBioParams{Bio: bio, Handle: handle})
+	return checkUpdated(rows, err)
+}
+
+func (r *DbPageRepository) UpdateProfilePhoto(ctx context.Context, handle, objectName string) error {
+	rows, err := r.queries.UpdatePageProfilePicture(ctx, db.UpdatePageProfilePictureParams{ProfilePicture: objectName, Handle: handle})
+	return checkUpdated(rows, err)
+}
+
+func (r *DbPageRepository) UpdateBannerPhoto(ctx context.Context, handle, objectName string) error {
+	rows, err := r.queries.UpdatePageBannerPicture(ctx, db.UpdatePageBannerPictureParams{BannerPicture: objectName, Handle: handle})
+	return checkUpdated(rows, err)
+}
+
+func (r *DbPageRepository) UpdateProfilePhotoState(ctx context.Context, handle, state string) error {
+	rows, err := r.queries.UpdatePageProfilePictureState(ctx, db.UpdatePageProfilePictureStateParams{ProfilePictureState: state, Handle: handle})
+	return checkUpdated(rows, err)
+}
+
+func (r *DbPageRepository) UpdateBannerPhotoState(ctx context.Context, handle, state string) error {
+	rows, err := r.queries.UpdatePageBannerPictureState(ctx, db.UpdatePageBannerPictureStateParams{BannerPictureState: state, Handle: handle})
+	return checkUpdated(rows, err)
+}
+
+func (r *DbPageRepository) UpdateUTM(ctx context.Context, handle string, tags utm.UTM) error {
+	encoded, err := json.Marshal(tags)
+	if err != nil {
+		return err
+	}
+
+	rows, err := r.queries.UpdatePageUTM(ctx, db.UpdatePageUTMParams{Utm: encoded, Handle: handle})
+	return checkUpdated(rows, err)
+}
+
+func (r *DbPageRepository) UpdateVisibility(ctx context.Context, handle, visibility string) error {
+	rows, err := r.queries.UpdatePageVisibility(ctx, db.UpdatePageVisibilityParams{Visibility: visibility, Handle: handle})
+	return checkUpdated(rows, err)
+}
+
+func checkUpdated(rows int64, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return ErrPageNotFound
+	}
+
+	return nil
+}
+
+func toPage(page db.Page) (Page, error) {
+	var tags utm.UTM
+	if err := json.Unmarshal(page.Utm, &tags); err != nil {
+		return Page{}, err
+	}
+
+	return Page{
+		ID:                  page.ID,
+		AccountID:           page.AccountID,
+		Handle:              page.Handle,
+		Name:                page.Name,
+		Bio:                 page.Bio,
+		ProfilePicture:      page.ProfilePicture,
+		BannerPicture:       page.BannerPicture,
+		ProfilePictureState: page.ProfilePictureState,
+		BannerPictureState:  page.BannerPictureState,
+		UTM:                 tags,
+		Visibility:          page.Visibility,
+		CreatedAt:           page.CreatedAt.Time,
+	}, nil
+}
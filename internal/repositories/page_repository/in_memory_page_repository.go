@@ -0,0 +1,103 @@
+package page_repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/username"
+	"github.com/theNixagen/linker/internal/domain/utm"
+)
+
+type InMemoryPageRepository struct {
+	Pages []Page
+}
+
+func NewInMemoryPageRepository() *InMemoryPageRepository {
+	return &InMemoryPageRepository{
+		Pages: []Page{},
+	}
+}
+
+func (r *InMemoryPageRepository) CreatePage(ctx context.Context, page Page) (int32, error) {
+	for _, p := range r.Pages {
+		if strings.EqualFold(p.Handle, page.Handle) || username.Skeleton(p.Handle) == username.Skeleton(page.Handle) {
+			return 0, ErrDuplicatedHandle
+		}
+	}
+
+	if page.ID == 0 {
+		page.ID = int32(len(r.Pages) + 1)
+	}
+	if page.CreatedAt.IsZero() {
+		page.CreatedAt = time.Now()
+	}
+	r.Pages = append(r.Pages, page)
+	return page.ID, nil
+}
+
+func (r *InMemoryPageRepository) GetPageByHandle(ctx context.Context, handle string) (Page, error) {
+	for _, p := range r.Pages {
+		if strings.EqualFold(p.Handle, handle) {
+			return p, nil
+		}
+	}
+	return Page{}, ErrPageNotFound
+}
+
+func (r *InMemoryPageRepository) GetPageByID(ctx context.Context, id int32) (Page, error) {
+	for _, p := range r.Pages {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return Page{}, ErrPageNotFound
+}
+
+func (r *InMemoryPageRepository) ListPagesByAccount(ctx context.Context, accountID int32) ([]Page, error) {
+	var result []Page
+	for _, p := range r.Pages {
+		if p.AccountID == accountID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryPageRepository) UpdateBio(ctx context.Context, handle, bio string) error {
+	return r.update(handle, func(p *Page) { p.Bio = bio })
+}
+
+func (r *InMemoryPageRepository) UpdateProfilePhoto(ctx context.Context, handle, objectName string) error {
+	return r.update(handle, func(p *Page) { p.ProfilePicture = objectName })
+}
+
+func (r *InMemoryPageRepository) UpdateBannerPhoto(ctx context.Context, handle, objectName string) error {
+	return r.update(handle, func(p *Page) { p.BannerPicture = objectName })
+}
+
+func (r *InMemoryPageRepository) UpdateProfilePhotoState(ctx context.Context, handle, state string) error {
+	return r.update(handle, func(p *Page) { p.ProfilePictureState = state })
+}
+
+func (r *InMemoryPageRepository) UpdateBannerPhotoState(ctx context.Context, handle, state string) error {
+	return r.update(handle, func(p *Page) { p.BannerPictureState = state })
+}
+
+func (r *InMemoryPageRepository) UpdateUTM(ctx context.Context, handle string, tags utm.UTM) error {
+	return r.update(handle, func(p *Page) { p.UTM = tags })
+}
+
+func (r *InMemoryPageRepository) UpdateVisibility(ctx context.Context, handle, visibility string) error {
+	return r.update(handle, func(p *Page) { p.Visibility = visibility })
+}
+
+func (r *InMemoryPageRepository) update(handle string, apply func(p *Page)) error {
+	for i := range r.Pages {
+		if r.Pages[i].Handle == handle {
+			apply(&r.Pages[i])
+			return nil
+		}
+	}
+	return ErrPageNotFound
+}
@@ -0,0 +1,45 @@
+package page_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/utm"
+)
+
+var (
+	ErrPageNotFound     = errors.New("page not found")
+	ErrDuplicatedHandle = errors.New("handle already exists")
+)
+
+// Page is a public profile. An account can own many pages, each with its own
+// handle, links and theme.
+type Page struct {
+	ID                  int32
+	AccountID           int32
+	Handle              string
+	Name                string
+	Bio                 string
+	ProfilePicture      string
+	BannerPicture       string
+	ProfilePictureState string
+	BannerPictureState  string
+	UTM                 utm.UTM
+	Visibility          string
+	CreatedAt           time.Time
+}
+
+type PageRepository interface {
+	CreatePage(ctx context.Context, page Page) (int32, error)
+	GetPageByHandle(ctx context.Context, handle string) (Page, error)
+	GetPageByID(ctx context.Context, id int32) (Page, error)
+	ListPagesByAccount(ctx context.Context, accountID int32) ([]Page, error)
+	UpdateBio(ctx context.Context, handle, bio string) error
+	UpdateProfilePhoto(ctx context.Context, handle, objectName string) error
+	UpdateBannerPhoto(ctx context.Context, handle, objectName string) error
+	UpdateProfilePhotoState(ctx context.Context, handle, state string) error
+	UpdateBannerPhotoState(ctx context.Context, handle, state string) error
+	UpdateUTM(ctx context.Context, handle string, tags utm.UTM) error
+	UpdateVisibility(ctx context.Context, handle, visibility string) error
+}
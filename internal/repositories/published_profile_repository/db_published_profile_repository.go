@@ -0,0 +1,57 @@
+package published_profile_repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+	"github.com/theNixagen/linker/internal/domain/theme"
+)
+
+type DbPublishedProfileRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbPublishedProfileRepository(pool *pgxpool.Pool) *DbPublishedProfileRepository {
+	return &DbPublishedProfileRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbPublishedProfileRepository) GetPublishedProfile(ctx context.Context, pageID int32) (PublishedProfile, error) {
+	row, err := r.queries.GetPublishedProfile(ctx, pageID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PublishedProfile{}, ErrProfileNotPublished
+		}
+		return PublishedProfile{}, err
+	}
+
+	profile := PublishedProfile{
+		PageID:         row.PageID,
+		Name:           row.Name,
+		Bio:            row.Bio,
+		ProfilePicture: row.ProfilePicture,
+		BannerPicture:  row.BannerPicture,
+		PublishedAt:    row.PublishedAt.Time,
+	}
+
+	if err := json.Unmarshal(row.Utm, &profile.UTM); err != nil {
+		return PublishedProfile{}, err
+	}
+
+	if row.Theme != nil {
+		t, err := theme.Parse(row.Theme)
+		if err != nil {
+			return PublishedProfile{}, err
+		}
+		profile.Theme = &t
+	}
+
+	return profile, nil
+}
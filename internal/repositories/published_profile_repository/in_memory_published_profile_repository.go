@@ -0,0 +1,35 @@
+package published_profile_repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+)
+
+type InMemoryPublishedProfileRepository struct {
+	Profiles        map[int32]PublishedProfile
+	LinksRepository *links_repository.InMemoryLinksRepository
+}
+
+func NewInMemoryPublishedProfileRepository(linksRepository *links_repository.InMemoryLinksRepository) *InMemoryPublishedProfileRepository {
+	return &InMemoryPublishedProfileRepository{
+		Profiles:        make(map[int32]PublishedProfile),
+		LinksRepository: linksRepository,
+	}
+}
+
+func (r *InMemoryPublishedProfileRepository) GetPublishedProfile(ctx context.Context, pageID int32) (PublishedProfile, error) {
+	profile, ok := r.Profiles[pageID]
+	if !ok {
+		return PublishedProfile{}, ErrProfileNotPublished
+	}
+	return profile, nil
+}
+
+func (r *InMemoryPublishedProfileRepository) Publish(ctx context.Context, profile PublishedProfile) (time.Time, error) {
+	profile.PublishedAt = time.Now()
+	r.Profiles[profile.PageID] = profile
+	r.LinksRepository.PublishLinks(profile.PageID)
+	return profile.PublishedAt, nil
+}
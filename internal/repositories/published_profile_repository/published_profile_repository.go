@@ -0,0 +1,30 @@
+package published_profile_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/domain/utm"
+)
+
+var (
+	ErrProfileNotPublished = errors.New("profile not published")
+)
+
+// PublishedProfile is the snapshot of the profile served to visitors.
+type PublishedProfile struct {
+	PageID         int32
+	Name           string
+	Bio            string
+	ProfilePicture string
+	BannerPicture  string
+	Theme          *theme.Theme
+	UTM            utm.UTM
+	PublishedAt    time.Time
+}
+
+type PublishedProfileRepository interface {
+	GetPublishedProfile(ctx context.Context, pageID int32) (PublishedProfile, error)
+}
@@ -0,0 +1,28 @@
+package reference_repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+)
+
+type DbReferenceRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbReferenceRepository(pool *pgxpool.Pool) *DbReferenceRepository {
+	return &DbReferenceRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbReferenceRepository) ListReferencedKeys(ctx context.Context) ([]string, error) {
+	return r.queries.ListReferencedObjectKeys(ctx)
+}
+
+func (r *DbReferenceRepository) ListPageHandles(ctx context.Context) ([]string, error) {
+	return r.queries.ListPageHandles(ctx)
+}
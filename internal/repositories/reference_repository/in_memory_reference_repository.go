@@ -0,0 +1,23 @@
+package reference_repository
+
+import "context"
+
+type InMemoryReferenceRepository struct {
+	Keys    []string
+	Handles []string
+}
+
+func NewInMemoryReferenceRepository() *InMemoryReferenceRepository {
+	return &InMemoryReferenceRepository{
+		Keys:    []string{},
+		Handles: []string{},
+	}
+}
+
+func (r *InMemoryReferenceRepository) ListReferencedKeys(ctx context.Context) ([]string, error) {
+	return r.Keys, nil
+}
+
+func (r *InMemoryReferenceRepository) ListPageHandles(ctx context.Context) ([]string, error) {
+	return r.Handles, nil
+}
@@ -0,0 +1,12 @@
+package reference_repository
+
+import "context"
+
+// ReferenceRepository lists what the database points to in object storage.
+type ReferenceRepository interface {
+	// ListReferencedKeys returns every object key stored on a draft or
+	// published page, a theme, a kept revision, a pending upload or the
+	// media library.
+	ListReferencedKeys(ctx context.Context) ([]string, error)
+	ListPageHandles(ctx context.Context) ([]string, error)
+}
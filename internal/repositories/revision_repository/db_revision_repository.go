@@ -0,0 +1,271 @@
+package revision_repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+	"github.com/theNixagen/linker/internal/domain/revision"
+	"github.com/theNixagen/linker/internal/domain/utm"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+)
+
+type DbRevisionRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbRevisionRepository(pool *pgxpool.Pool) *DbRevisionRepository {
+	return &DbRevisionRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbRevisionRepository) CreateRevision(ctx context.Context, pageID int32, reason string, snapshot revision.Snapshot, keep int) (Revision, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return Revision{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := createRevision(ctx, r.queries.WithTx(tx), pageID, reason, snapshot, keep)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Revision{}, err
+	}
+
+	return created, nil
+}
+
+func (r *DbRevisionRepository) ListRevisions(ctx context.Context, pageID int32) ([]Revision, error) {
+	rows, err := r.queries.ListRevisions(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Revision, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Revision{
+			ID:        row.ID,
+			PageID:    row.PageID,
+			Reason:    row.Reason,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+
+	return result, nil
+}
+
+func (r *DbRevisionRepository) GetRevision(ctx context.Context, pageID, id int32) (Revision, error) {
+	row, err := r.queries.GetRevision(ctx, db.GetRevisionParams{ID: id, PageID: pageID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Revision{}, ErrRevisionNotFound
+		}
+		return Revision{}, err
+	}
+
+	return toRevision(row)
+}
+
+func (r *DbRevisionRepository) Publish(ctx context.Context, profile published_profile_repository.PublishedProfile, snapshot revision.Snapshot, keep int) (time.Time, error) {
+	var encodedTheme []byte
+	if profile.Theme != nil {
+		var err error
+		encodedTheme, err = json.Marshal(profile.Theme)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	tags, err := json.Marshal(profile.UTM)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := r.queries.WithTx(tx)
+
+	publishedAt, err := queries.UpsertPublishedProfile(ctx, db.UpsertPublishedProfileParams{
+		PageID:         profile.PageID,
+		Name:           profile.Name,
+		Bio:            profile.Bio,
+		ProfilePicture: profile.ProfilePicture,
+		BannerPicture:  profile.BannerPicture,
+		Theme:          encodedTheme,
+		Utm:            tags,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := queries.PublishLinks(ctx, profile.PageID); err != nil {
+		return time.Time{}, err
+	}
+
+	if _, err := createRevision(ctx, queries, profile.PageID, revision.ReasonPublish, snapshot, keep); err != nil {
+		return time.Time{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return time.Time{}, err
+	}
+
+	return publishedAt.Time, nil
+}
+
+func (r *DbRevisionRepository) Restore(ctx context.Context, pageID int32, snapshot revision.Snapshot, keep int) (Revision, error) {
+	var encodedTheme []byte
+	if snapshot.Theme != nil {
+		var err error
+		encodedTheme, err = json.Marshal(snapshot.Theme)
+		if err != nil {
+			return Revision{}, err
+		}
+	}
+
+	var tags utm.UTM
+	if snapshot.UTM != nil {
+		tags = *snapshot.UTM
+	}
+	encodedTags, err := json.Marshal(tags)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return Revision{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := r.queries.WithTx(tx)
+
+	if err := queries.RestorePage(ctx, db.RestorePageParams{
+		ID:             pageID,
+		Name:           snapshot.Name,
+		Bio:            snapshot.Bio,
+		ProfilePicture: snapshot.ProfilePicture,
+		BannerPicture:  snapshot.BannerPicture,
+		Utm:            encodedTags,
+	}); err != nil {
+		return Revision{}, err
+	}
+
+	if snapshot.Theme != nil {
+		err = queries.UpsertTheme(ctx, db.UpsertThemeParams{PageID: pageID, Version: int32(snapshot.Theme.Version), Theme: encodedTheme})
+	} else {
+		err = queries.DeleteTheme(ctx, pageID)
+	}
+	if err != nil {
+		return Revision{}, err
+	}
+
+	// Links created after the revision go back to being drafts.
+	if err := queries.UnpublishLinks(ctx, pageID); err != nil {
+		return Revision{}, err
+	}
+
+	for _, link := range snapshot.Links {
+		tags, err := json.Marshal(link.UTM)
+		if err != nil {
+			return Revision{}, err
+		}
+
+		if err := queries.RestoreLink(ctx, db.RestoreLinkParams{
+			ID:           link.ID,
+			PageID:       pageID,
+			Url:          link.URL,
+			Title:        link.Title,
+			Description:  link.Description,
+			Slug:         pgtype.Text{String: link.Slug, Valid: link.Slug != ""},
+			ShortCode:    link.ShortCode,
+			Platform:     link.Platform,
+			Handle:       link.Handle,
+			Sensitive:    link.Sensitive,
+			PasswordHash: link.PasswordHash,
+			Utm:          tags,
+			CreatedAt:    pgtype.Timestamp{Time: link.CreatedAt, Valid: true},
+
+			ThumbnailMediaID: pgtype.Int4{Int32: link.ThumbnailMediaID, Valid: link.ThumbnailMediaID != 0},
+			Thumbnail:        link.Thumbnail,
+		}); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return Revision{}, ErrRestoreConflict
+			}
+			return Revision{}, err
+		}
+	}
+
+	if _, err := queries.UpsertPublishedProfile(ctx, db.UpsertPublishedProfileParams{
+		PageID:         pageID,
+		Name:           snapshot.Name,
+		Bio:            snapshot.Bio,
+		ProfilePicture: snapshot.ProfilePicture,
+		BannerPicture:  snapshot.BannerPicture,
+		Theme:          encodedTheme,
+		Utm:            encodedTags,
+	}); err != nil {
+		return Revision{}, err
+	}
+
+	created, err := createRevision(ctx, queries, pageID, revision.ReasonRestore, snapshot, keep)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Revision{}, err
+	}
+
+	return created, nil
+}
+
+func createRevision(ctx context.Context, queries *db.Queries, pageID int32, reason string, snapshot revision.Snapshot, keep int) (Revision, error) {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return Revision{}, err
+	}
+
+	row, err := queries.CreateRevision(ctx, db.CreateRevisionParams{PageID: pageID, Reason: reason, Snapshot: encoded})
+	if err != nil {
+		return Revision{}, err
+	}
+
+	if err := queries.PruneRevisions(ctx, db.PruneRevisionsParams{PageID: pageID, Limit: int32(keep)}); err != nil {
+		return Revision{}, err
+	}
+
+	return toRevision(row)
+}
+
+func toRevision(row db.ProfileRevision) (Revision, error) {
+	var snapshot revision.Snapshot
+	if err := json.Unmarshal(row.Snapshot, &snapshot); err != nil {
+		return Revision{}, err
+	}
+
+	return Revision{
+		ID:        row.ID,
+		PageID:    row.PageID,
+		Reason:    row.Reason,
+		Snapshot:  snapshot,
+		CreatedAt: row.CreatedAt.Time,
+	}, nil
+}
@@ -0,0 +1,172 @@
+package revision_repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/revision"
+	"github.com/theNixagen/linker/internal/domain/utm"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+)
+
+type InMemoryRevisionRepository struct {
+	Revisions                  []Revision
+	PageRepository             *page_repository.InMemoryPageRepository
+	ThemeRepository            *theme_repository.InMemoryThemeRepository
+	LinksRepository            *links_repository.InMemoryLinksRepository
+	PublishedProfileRepository *published_profile_repository.InMemoryPublishedProfileRepository
+	nextID                     int32
+}
+
+func NewInMemoryRevisionRepository(pageRepository *page_repository.InMemoryPageRepository, themeRepository *theme_repository.InMemoryThemeRepository, linksRepository *links_repository.InMemoryLinksRepository, publishedProfileRepository *published_profile_repository.InMemoryPublishedProfileRepository) *InMemoryRevisionRepository {
+	return &InMemoryRevisionRepository{
+		Revisions:                  []Revision{},
+		PageRepository:             pageRepository,
+		ThemeRepository:            themeRepository,
+		LinksRepository:            linksRepository,
+		PublishedProfileRepository: publishedProfileRepository,
+	}
+}
+
+func (r *InMemoryRevisionRepository) CreateRevision(ctx context.Context, pageID int32, reason string, snapshot revision.Snapshot, keep int) (Revision, error) {
+	r.nextID++
+	created := Revision{
+		ID:        r.nextID,
+		PageID:    pageID,
+		Reason:    reason,
+		Snapshot:  snapshot,
+		CreatedAt: time.Now(),
+	}
+	r.Revisions = append(r.Revisions, created)
+
+	kept := 0
+	for i := len(r.Revisions) - 1; i >= 0; i-- {
+		if r.Revisions[i].PageID != pageID {
+			continue
+		}
+		kept++
+		if kept > keep {
+			r.Revisions = slices.Delete(r.Revisions, i, i+1)
+		}
+	}
+
+	return created, nil
+}
+
+func (r *InMemoryRevisionRepository) ListRevisions(ctx context.Context, pageID int32) ([]Revision, error) {
+	var result []Revision
+	for i := len(r.Revisions) - 1; i >= 0; i-- {
+		if r.Revisions[i].PageID == pageID {
+			listed := r.Revisions[i]
+			listed.Snapshot = revision.Snapshot{}
+			result = append(result, listed)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryRevisionRepository) GetRevision(ctx context.Context, pageID, id int32) (Revision, error) {
+	for _, rev := range r.Revisions {
+		if rev.ID == id && rev.PageID == pageID {
+			return rev, nil
+		}
+	}
+	return Revision{}, ErrRevisionNotFound
+}
+
+func (r *InMemoryRevisionRepository) Publish(ctx context.Context, profile published_profile_repository.PublishedProfile, snapshot revision.Snapshot, keep int) (time.Time, error) {
+	publishedAt, err := r.PublishedProfileRepository.Publish(ctx, profile)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if _, err := r.CreateRevision(ctx, profile.PageID, revision.ReasonPublish, snapshot, keep); err != nil {
+		return time.Time{}, err
+	}
+
+	return publishedAt, nil
+}
+
+func (r *InMemoryRevisionRepository) Restore(ctx context.Context, pageID int32, snapshot revision.Snapshot, keep int) (Revision, error) {
+	for _, link := range snapshot.Links {
+		if slices.ContainsFunc(r.LinksRepository.Links, func(l links_repository.Link) bool {
+			return l.ID != link.ID && (l.ShortCode == link.ShortCode || (l.PageID == pageID && link.Slug != "" && l.Slug == link.Slug))
+		}) {
+			return Revision{}, ErrRestoreConflict
+		}
+	}
+
+	var tags utm.UTM
+	if snapshot.UTM != nil {
+		tags = *snapshot.UTM
+	}
+
+	for i, p := range r.PageRepository.Pages {
+		if p.ID == pageID {
+			r.PageRepository.Pages[i].Name = snapshot.Name
+			r.PageRepository.Pages[i].Bio = snapshot.Bio
+			r.PageRepository.Pages[i].ProfilePicture = snapshot.ProfilePicture
+			r.PageRepository.Pages[i].BannerPicture = snapshot.BannerPicture
+			r.PageRepository.Pages[i].UTM = tags
+		}
+	}
+
+	if snapshot.Theme != nil {
+		r.ThemeRepository.Themes[pageID] = *snapshot.Theme
+	} else {
+		delete(r.ThemeRepository.Themes, pageID)
+	}
+
+	for i, link := range r.LinksRepository.Links {
+		if link.PageID == pageID {
+			r.LinksRepository.Links[i].Published = false
+		}
+	}
+
+	for _, link := range snapshot.Links {
+		restored := links_repository.Link{
+			ID:           link.ID,
+			PageID:       pageID,
+			Url:          link.URL,
+			Title:        link.Title,
+			Description:  link.Description,
+			Slug:         link.Slug,
+			ShortCode:    link.ShortCode,
+			Platform:     link.Platform,
+			Handle:       link.Handle,
+			Sensitive:    link.Sensitive,
+			PasswordHash: link.PasswordHash,
+			UTM:          link.UTM,
+			PublishedUTM: link.UTM,
+			Published:    true,
+			CreatedAt:    link.CreatedAt,
+
+			ThumbnailMediaID: link.ThumbnailMediaID,
+			Thumbnail:        link.Thumbnail,
+		}
+
+		index := slices.IndexFunc(r.LinksRepository.Links, func(l links_repository.Link) bool { return l.ID == link.ID })
+		if index >= 0 {
+			r.LinksRepository.Links[index] = restored
+		} else {
+			r.LinksRepository.Links = append(r.LinksRepository.Links, restored)
+		}
+	}
+
+	r.PublishedProfileRepository.Profiles[pageID] = published_profile_repository.PublishedProfile{
+		PageID:         pageID,
+		Name:           snapshot.Name,
+		Bio:            snapshot.Bio,
+		ProfilePicture: snapshot.ProfilePicture,
+		BannerPicture:  snapshot.BannerPicture,
+		Theme:          snapshot.Theme,
+		UTM:            tags,
+		PublishedAt:    time.Now(),
+	}
+
+	return r.CreateRevision(ctx, pageID, revision.ReasonRestore, snapshot, keep)
+}
@@ -0,0 +1,40 @@
+package revision_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/revision"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+)
+
+var (
+	ErrRevisionNotFound = errors.New("revision not found")
+	ErrRestoreConflict  = errors.New("a link of the revision uses a slug or short code taken by another link")
+)
+
+type Revision struct {
+	ID        int32
+	PageID    int32
+	Reason    string
+	Snapshot  revision.Snapshot
+	CreatedAt time.Time
+}
+
+type RevisionRepository interface {
+	// CreateRevision stores the snapshot and drops the oldest revisions of the
+	// user past keep.
+	CreateRevision(ctx context.Context, pageID int32, reason string, snapshot revision.Snapshot, keep int) (Revision, error)
+	// ListRevisions returns the revisions of the user, newest first, without
+	// their snapshots.
+	ListRevisions(ctx context.Context, pageID int32) ([]Revision, error)
+	GetRevision(ctx context.Context, pageID, id int32) (Revision, error)
+	// Publish stores the published profile, publishes every link of the page
+	// and records snapshot as a revision in a single transaction.
+	Publish(ctx context.Context, profile published_profile_repository.PublishedProfile, snapshot revision.Snapshot, keep int) (time.Time, error)
+	// Restore writes the snapshot to the draft and the published profile and
+	// records it as a new revision in a single transaction. It fails with
+	// ErrRestoreConflict when a link can't get its slug or short code back.
+	Restore(ctx context.Context, pageID int32, snapshot revision.Snapshot, keep int) (Revision, error)
+}
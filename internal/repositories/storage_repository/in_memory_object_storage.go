@@ -0,0 +1,112 @@
+package storage_repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+	modified    time.Time
+}
+
+// InMemoryObjectStorage keeps objects in memory. Like LocalObjectStorage its
+// presigned URLs are served by the API.
+type InMemoryObjectStorage struct {
+	signer  URLSigner
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func NewInMemoryObjectStorage(signer URLSigner) *InMemoryObjectStorage {
+	return &InMemoryObjectStorage{
+		signer:  signer,
+		objects: make(map[string]memoryObject),
+	}
+}
+
+func (s *InMemoryObjectStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = memoryObject{data: data, contentType: contentType, modified: time.Now()}
+	return nil
+}
+
+func (s *InMemoryObjectStorage) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[key]
+	if !ok {
+		return nil, ObjectInfo{}, ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(object.data)), object.info(key), nil
+}
+
+func (s *InMemoryObjectStorage) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	object, ok := s.objects[key]
+	if !ok {
+		return ObjectInfo{}, ErrObjectNotFound
+	}
+	return object.info(key), nil
+}
+
+func (s *InMemoryObjectStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *InMemoryObjectStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := []ObjectInfo{}
+	for key, object := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, object.info(key))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+func (s *InMemoryObjectStorage) PresignPost(ctx context.Context, key, contentType string, size int64, expires time.Time) (string, map[string]string, error) {
+	return s.signer.PresignPost(key, contentType, size, expires)
+}
+
+func (o memoryObject) info(key string) ObjectInfo {
+	sum := md5.Sum(o.data)
+	return ObjectInfo{
+		Key:          key,
+		Size:         int64(len(o.data)),
+		ContentType:  o.contentType,
+		ETag:         hex.EncodeToString(sum[:]),
+		LastModified: o.modified,
+	}
+}
+
+func (s *InMemoryObjectStorage) VerifyPost(fields map[string]string) (string, string, int64, error) {
+	return s.signer.VerifyPost(fields)
+}
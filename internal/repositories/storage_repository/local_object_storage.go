@@ -0,0 +1,177 @@
+package storage_repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// tempPrefix marks files that are still being written.
+const tempPrefix = ".tmp-"
+
+// LocalObjectStorage keeps objects as files under a root directory. Its
+// presigned URLs are served by the API, see URLSigner.
+type LocalObjectStorage struct {
+	signer URLSigner
+	root   string
+}
+
+func NewLocalObjectStorage(root string, signer URLSigner) (*LocalObjectStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &LocalObjectStorage{
+		signer: signer,
+		root:   root,
+	}, nil
+}
+
+func (s *LocalObjectStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	// Write next to the target and rename, so readers never see half a file.
+	tmp, err := os.CreateTemp(filepath.Dir(target), tempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *LocalObjectStorage) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+
+	file, err := os.Open(target)
+	if err != nil {
+		return nil, ObjectInfo{}, toFileError(err)
+	}
+
+	info, err := fileInfo(key, file)
+	if err != nil {
+		file.Close()
+		return nil, ObjectInfo{}, err
+	}
+	return file, info, nil
+}
+
+func (s *LocalObjectStorage) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	file, info, err := s.Get(ctx, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	file.Close()
+	return info, nil
+}
+
+func (s *LocalObjectStorage) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalObjectStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	result := []ObjectInfo{}
+	err := filepath.WalkDir(s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), tempPrefix) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := s.Stat(ctx, key)
+		if err != nil {
+			return err
+		}
+		result = append(result, info)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *LocalObjectStorage) PresignPost(ctx context.Context, key, contentType string, size int64, expires time.Time) (string, map[string]string, error) {
+	return s.signer.PresignPost(key, contentType, size, expires)
+}
+
+func (s *LocalObjectStorage) path(key string) (string, error) {
+	if err := validateKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// fileInfo sniffs the content type, files do not keep the one they were
+// stored with.
+func fileInfo(key string, file *os.File) (ObjectInfo, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+
+	head := make([]byte, 512)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:          key,
+		Size:         stat.Size(),
+		ContentType:  http.DetectContentType(head[:n]),
+		ETag:         fmt.Sprintf("%x-%x", stat.ModTime().UnixNano(), stat.Size()),
+		LastModified: stat.ModTime(),
+	}, nil
+}
+
+func toFileError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrObjectNotFound
+	}
+	return err
+}
+
+func (s *LocalObjectStorage) VerifyPost(fields map[string]string) (string, string, int64, error) {
+	return s.signer.VerifyPost(fields)
+}
@@ -0,0 +1,84 @@
+package storage_repository
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalObjectStorage(t *testing.T) {
+	storage, err := NewLocalObjectStorage(t.TempDir(), NewURLSigner("secret", "http://localhost/storage"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := storage.Put(t.Context(), "pages/1/avatar.txt", strings.NewReader("hello"), 5, "text/plain"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	object, info, err := storage.Get(t.Context(), "pages/1/avatar.txt")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	data, _ := io.ReadAll(object)
+	object.Close()
+	if string(data) != "hello" || info.Size != 5 {
+		t.Errorf("expected stored content, got %q (%d bytes)", data, info.Size)
+	}
+
+	objects, err := storage.List(t.Context(), "pages/")
+	if err != nil || len(objects) != 1 || objects[0].Key != "pages/1/avatar.txt" {
+		t.Errorf("expected one listed object, got %v (%v)", objects, err)
+	}
+
+	if err := storage.Delete(t.Context(), "pages/1/avatar.txt"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := storage.Stat(t.Context(), "pages/1/avatar.txt"); !errors.Is(err, ErrObjectNotFound) {
+		t.Errorf("expected ErrObjectNotFound, got %v", err)
+	}
+}
+
+func TestLocalObjectStorage_RejectsTraversal(t *testing.T) {
+	storage, err := NewLocalObjectStorage(t.TempDir(), NewURLSigner("secret", "http://localhost/storage"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, key := range []string{"../escape", "/etc/passwd", "a/../../b", "a//b", ""} {
+		if err := storage.Put(t.Context(), key, strings.NewReader("x"), 1, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("expected ErrInvalidKey for %q, got %v", key, err)
+		}
+	}
+}
+
+func TestURLSigner(t *testing.T) {
+	signer := NewURLSigner("secret", "http://localhost/storage")
+
+	postURL, fields, err := signer.PresignPost("uploads/1/x", "image/png", 42, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if postURL != "http://localhost/storage" {
+		t.Errorf("unexpected url %s", postURL)
+	}
+	if _, _, _, err := NewURLSigner("other", "").VerifyPost(fields); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected signature bound to the secret, got %v", err)
+	}
+	key, contentType, size, err := signer.VerifyPost(fields)
+	if err != nil || key != "uploads/1/x" || contentType != "image/png" || size != 42 {
+		t.Errorf("unexpected policy %s %s %d (%v)", key, contentType, size, err)
+	}
+
+	fields["size"] = "4200"
+	if _, _, _, err := signer.VerifyPost(fields); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected tampered size to be rejected, got %v", err)
+	}
+
+	_, expired, _ := signer.PresignPost("uploads/1/x", "image/png", 42, time.Now().Add(-time.Minute))
+	if _, _, _, err := signer.VerifyPost(expired); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected expired policy to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package storage_repository
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinioObjectStorage keeps objects in a MinIO or S3 compatible bucket.
+type MinioObjectStorage struct {
+	bucket string
+	client *minio.Client
+}
+
+func NewMinioObjectStorage(endpoint, user, password, bucket string) (*MinioObjectStorage, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds: credentials.NewStaticV4(user, password, ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MinioObjectStorage{
+		bucket: bucket,
+		client: client,
+	}, nil
+}
+
+// CreateBucketIfNotExists reports whether the bucket had to be created.
+func (s *MinioObjectStorage) CreateBucketIfNotExists(ctx context.Context) (bool, error) {
+	ok, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return false, nil
+	}
+
+	if err := s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *MinioObjectStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+func (s *MinioObjectStorage) Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, ObjectInfo{}, toStorageError(err)
+	}
+
+	// GetObject is lazy, Stat is the first call that reaches the server.
+	info, err := object.Stat()
+	if err != nil {
+		object.Close()
+		return nil, ObjectInfo{}, toStorageError(err)
+	}
+
+	return object, toObjectInfo(info), nil
+}
+
+func (s *MinioObjectStorage) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return ObjectInfo{}, toStorageError(err)
+	}
+	return toObjectInfo(info), nil
+}
+
+func (s *MinioObjectStorage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *MinioObjectStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	result := []ObjectInfo{}
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		result = append(result, toObjectInfo(object))
+	}
+	return result, nil
+}
+
+func (s *MinioObjectStorage) PresignPost(ctx context.Context, key, contentType string, size int64, expires time.Time) (string, map[string]string, error) {
+	policy := minio.NewPostPolicy()
+	if err := policy.SetBucket(s.bucket); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetKey(key); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetExpires(expires); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetContentType(contentType); err != nil {
+		return "", nil, err
+	}
+	if err := policy.SetContentLengthRange(size, size); err != nil {
+		return "", nil, err
+	}
+
+	postURL, fields, err := s.client.PresignedPostPolicy(ctx, policy)
+	if err != nil {
+		return "", nil, err
+	}
+	return postURL.String(), fields, nil
+}
+
+func toStorageError(err error) error {
+	if code := minio.ToErrorResponse(err).Code; code == "NoSuchKey" || code == "NotFound" {
+		return ErrObjectNotFound
+	}
+	return err
+}
+
+func toObjectInfo(info minio.ObjectInfo) ObjectInfo {
+	return ObjectInfo{
+		Key:          info.Key,
+		Size:         info.Size,
+		ContentType:  info.ContentType,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}
+}
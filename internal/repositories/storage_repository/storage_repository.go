@@ -0,0 +1,43 @@
+package storage_repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrObjectNotFound   = errors.New("object not found")
+	ErrInvalidKey       = errors.New("invalid object key")
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// ObjectStorage is where uploaded and generated files are kept. Missing
+// objects are reported as ErrObjectNotFound by every implementation.
+type ObjectStorage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete removes key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// PresignPost returns a URL and form fields that accept exactly size bytes
+	// of contentType at key until expires.
+	PresignPost(ctx context.Context, key, contentType string, size int64, expires time.Time) (string, map[string]string, error)
+}
+
+// SelfSignedStorage is implemented by backends whose presigned uploads are
+// accepted by the API itself rather than by the storage server.
+type SelfSignedStorage interface {
+	ObjectStorage
+	VerifyPost(fields map[string]string) (key, contentType string, size int64, err error)
+}
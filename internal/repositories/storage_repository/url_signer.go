@@ -0,0 +1,94 @@
+package storage_repository
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// URLSigner builds HMAC-signed upload policies for backends without
+// presigning of their own. The policies point at baseURL, where the API
+// accepts POST uploads.
+type URLSigner struct {
+	secret  []byte
+	baseURL string
+}
+
+func NewURLSigner(secret, baseURL string) URLSigner {
+	return URLSigner{
+		secret:  []byte(secret),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (s URLSigner) PresignPost(key, contentType string, size int64, expires time.Time) (string, map[string]string, error) {
+	if err := validateKey(key); err != nil {
+		return "", nil, err
+	}
+
+	fields := map[string]string{
+		"key":          key,
+		"content_type": contentType,
+		"size":         strconv.FormatInt(size, 10),
+		"expires":      strconv.FormatInt(expires.Unix(), 10),
+	}
+	fields["signature"] = s.sign("POST", key, contentType, fields["size"], fields["expires"])
+	return s.baseURL, fields, nil
+}
+
+// VerifyPost checks the form fields of a policy built by PresignPost and
+// returns the constraints it carries.
+func (s URLSigner) VerifyPost(fields map[string]string) (key, contentType string, size int64, err error) {
+	if err := checkExpiry(fields["expires"]); err != nil {
+		return "", "", 0, err
+	}
+	if err := s.check(fields["signature"], "POST", fields["key"], fields["content_type"], fields["size"], fields["expires"]); err != nil {
+		return "", "", 0, err
+	}
+
+	size, err = strconv.ParseInt(fields["size"], 10, 64)
+	if err != nil {
+		return "", "", 0, ErrInvalidSignature
+	}
+	return fields["key"], fields["content_type"], size, nil
+}
+
+func (s URLSigner) sign(parts ...string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s URLSigner) check(signature string, parts ...string) error {
+	if !hmac.Equal([]byte(signature), []byte(s.sign(parts...))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func checkExpiry(expires string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// validateKey rejects keys that could escape the storage root once mapped
+// to a file path.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || strings.HasPrefix(segment, tempPrefix) {
+			return fmt.Errorf("%w: %s", ErrInvalidKey, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package theme_repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+	"github.com/theNixagen/linker/internal/domain/theme"
+)
+
+type DbThemeRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbThemeRepository(pool *pgxpool.Pool) *DbThemeRepository {
+	return &DbThemeRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbThemeRepository) GetTheme(ctx context.Context, pageID int32) (theme.Theme, error) {
+	row, err := r.queries.GetThemeByPageID(ctx, pageID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return theme.Theme{}, ErrThemeNotFound
+		}
+		return theme.Theme{}, err
+	}
+
+	return theme.Parse(row.Theme)
+}
+
+func (r *DbThemeRepository) UpsertTheme(ctx context.Context, pageID int32, t theme.Theme) error {
+	encoded, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	return r.queries.UpsertTheme(ctx, db.UpsertThemeParams{
+		PageID:  pageID,
+		Version: int32(t.Version),
+		Theme:   encoded,
+	})
+}
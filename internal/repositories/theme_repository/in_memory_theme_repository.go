@@ -0,0 +1,30 @@
+package theme_repository
+
+import (
+	"context"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+)
+
+type InMemoryThemeRepository struct {
+	Themes map[int32]theme.Theme
+}
+
+func NewInMemoryThemeRepository() *InMemoryThemeRepository {
+	return &InMemoryThemeRepository{
+		Themes: make(map[int32]theme.Theme),
+	}
+}
+
+func (r *InMemoryThemeRepository) GetTheme(ctx context.Context, pageID int32) (theme.Theme, error) {
+	t, ok := r.Themes[pageID]
+	if !ok {
+		return theme.Theme{}, ErrThemeNotFound
+	}
+	return t, nil
+}
+
+func (r *InMemoryThemeRepository) UpsertTheme(ctx context.Context, pageID int32, t theme.Theme) error {
+	r.Themes[pageID] = t
+	return nil
+}
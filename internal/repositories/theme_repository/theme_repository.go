@@ -0,0 +1,17 @@
+package theme_repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+)
+
+var (
+	ErrThemeNotFound = errors.New("theme not found")
+)
+
+type ThemeRepository interface {
+	GetTheme(ctx context.Context, pageID int32) (theme.Theme, error)
+	UpsertTheme(ctx context.Context, pageID int32, t theme.Theme) error
+}
@@ -0,0 +1,131 @@
+package upload_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/theNixagen/linker/internal/db"
+)
+
+type DbUploadRepository struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewDbUploadRepository(pool *pgxpool.Pool) *DbUploadRepository {
+	return &DbUploadRepository{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (r *DbUploadRepository) CreateUpload(ctx context.Context, upload Upload) (Upload, error) {
+	created, err := r.queries.CreateUpload(ctx, db.CreateUploadParams{
+		PageID:      upload.PageID,
+		Kind:        upload.Kind,
+		ObjectKey:   upload.ObjectKey,
+		ContentType: upload.ContentType,
+		Size:        upload.Size,
+		Checksum:    upload.Checksum,
+		ExpiresAt:   pgtype.Timestamp{Time: upload.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return Upload{}, err
+	}
+
+	return toUpload(created), nil
+}
+
+func (r *DbUploadRepository) GetUpload(ctx context.Context, pageID, id int32) (Upload, error) {
+	upload, err := r.queries.GetUpload(ctx, db.GetUploadParams{ID: id, PageID: pageID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Upload{}, ErrUploadNotFound
+		}
+		return Upload{}, err
+	}
+
+	return toUpload(upload), nil
+}
+
+func (r *DbUploadRepository) DeleteUpload(ctx context.Context, id int32) error {
+	return r.queries.DeleteUpload(ctx, id)
+}
+
+func (r *DbUploadRepository) StartProcessing(ctx context.Context, id int32, reserved int64) error {
+	return r.queries.StartUploadProcessing(ctx, db.StartUploadProcessingParams{ID: id, ReservedSize: reserved})
+}
+
+func (r *DbUploadRepository) ReleaseReservation(ctx context.Context, id int32) error {
+	return r.queries.ReleaseUploadReservation(ctx, id)
+}
+
+// LockStorage takes a transaction-scoped advisory lock, so it is released
+// when the transaction is rolled back by unlock or its connection is lost.
+func (r *DbUploadRepository) LockStorage(ctx context.Context, accountID int32) (func(), error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.queries.WithTx(tx).LockAccountStorage(ctx, accountID); err != nil {
+		tx.Rollback(ctx)
+		return nil, err
+	}
+	return func() { tx.Rollback(context.WithoutCancel(ctx)) }, nil
+}
+
+func (r *DbUploadRepository) PendingSize(ctx context.Context, accountID int32) (int64, error) {
+	return r.queries.GetPendingUploadSize(ctx, accountID)
+}
+
+func (r *DbUploadRepository) ListExpiredUploads(ctx context.Context) ([]Upload, error) {
+	rows, err := r.queries.ListExpiredUploads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Upload, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toUpload(row))
+	}
+
+	return result, nil
+}
+
+func (r *DbUploadRepository) ListStaleUploads(ctx context.Context, before time.Time) ([]Upload, error) {
+	rows, err := r.queries.ListStaleUploads(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Upload, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toUpload(row))
+	}
+
+	return result, nil
+}
+
+func (r *DbUploadRepository) FailOrphanedPictures(ctx context.Context) error {
+	return r.queries.FailOrphanedPictureStates(ctx)
+}
+
+func toUpload(upload db.Upload) Upload {
+	return Upload{
+		ID:              upload.ID,
+		PageID:          upload.PageID,
+		Kind:            upload.Kind,
+		ObjectKey:       upload.ObjectKey,
+		ContentType:     upload.ContentType,
+		Size:            upload.Size,
+		Checksum:        upload.Checksum,
+		ExpiresAt:       upload.ExpiresAt.Time,
+		CreatedAt:       upload.CreatedAt.Time,
+		ProcessingSince: upload.ProcessingSince.Time,
+		ReservedSize:    upload.ReservedSize,
+	}
+}
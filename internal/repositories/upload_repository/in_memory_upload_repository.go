@@ -0,0 +1,135 @@
+package upload_repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+type InMemoryUploadRepository struct {
+	Uploads        []Upload
+	PageRepository *page_repository.InMemoryPageRepository
+	nextID         int32
+}
+
+func NewInMemoryUploadRepository(pageRepository *page_repository.InMemoryPageRepository) *InMemoryUploadRepository {
+	return &InMemoryUploadRepository{
+		Uploads:        []Upload{},
+		PageRepository: pageRepository,
+	}
+}
+
+func (r *InMemoryUploadRepository) CreateUpload(ctx context.Context, upload Upload) (Upload, error) {
+	r.nextID++
+	upload.ID = r.nextID
+	upload.CreatedAt = time.Now()
+	r.Uploads = append(r.Uploads, upload)
+	return upload, nil
+}
+
+func (r *InMemoryUploadRepository) GetUpload(ctx context.Context, pageID, id int32) (Upload, error) {
+	for _, u := range r.Uploads {
+		if u.ID == id && u.PageID == pageID {
+			return u, nil
+		}
+	}
+	return Upload{}, ErrUploadNotFound
+}
+
+func (r *InMemoryUploadRepository) DeleteUpload(ctx context.Context, id int32) error {
+	for i, u := range r.Uploads {
+		if u.ID == id {
+			r.Uploads = append(r.Uploads[:i], r.Uploads[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryUploadRepository) StartProcessing(ctx context.Context, id int32, reserved int64) error {
+	for i := range r.Uploads {
+		if r.Uploads[i].ID == id {
+			r.Uploads[i].ProcessingSince = time.Now()
+			r.Uploads[i].ReservedSize = reserved
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryUploadRepository) ReleaseReservation(ctx context.Context, id int32) error {
+	for i := range r.Uploads {
+		if r.Uploads[i].ID == id {
+			r.Uploads[i].ReservedSize = 0
+		}
+	}
+	return nil
+}
+
+// LockStorage does nothing: the service already serializes the accounts
+// within a process.
+func (r *InMemoryUploadRepository) LockStorage(ctx context.Context, accountID int32) (func(), error) {
+	return func() {}, nil
+}
+
+func (r *InMemoryUploadRepository) PendingSize(ctx context.Context, accountID int32) (int64, error) {
+	var pending int64
+	for _, u := range r.Uploads {
+		p, err := r.PageRepository.GetPageByID(ctx, u.PageID)
+		if err != nil {
+			continue
+		}
+		switch {
+		case p.AccountID != accountID:
+		case u.ProcessingSince.IsZero():
+			pending += u.Size
+		default:
+			pending += u.ReservedSize
+		}
+	}
+	return pending, nil
+}
+
+func (r *InMemoryUploadRepository) ListExpiredUploads(ctx context.Context) ([]Upload, error) {
+	result := []Upload{}
+	now := time.Now()
+	for _, u := range r.Uploads {
+		if !u.ExpiresAt.After(now) && u.ProcessingSince.IsZero() {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryUploadRepository) ListStaleUploads(ctx context.Context, before time.Time) ([]Upload, error) {
+	result := []Upload{}
+	for _, u := range r.Uploads {
+		if !u.ProcessingSince.IsZero() && !u.ProcessingSince.After(before) {
+			result = append(result, u)
+		}
+	}
+	return result, nil
+}
+
+func (r *InMemoryUploadRepository) FailOrphanedPictures(ctx context.Context) error {
+	for i := range r.PageRepository.Pages {
+		p := &r.PageRepository.Pages[i]
+		if p.ProfilePictureState == page.PicturePending && !r.processing(p.ID, "avatar") {
+			p.ProfilePictureState = page.PictureFailed
+		}
+		if p.BannerPictureState == page.PicturePending && !r.processing(p.ID, "banner") {
+			p.BannerPictureState = page.PictureFailed
+		}
+	}
+	return nil
+}
+
+func (r *InMemoryUploadRepository) processing(pageID int32, kind string) bool {
+	for _, u := range r.Uploads {
+		if u.PageID == pageID && u.Kind == kind && !u.ProcessingSince.IsZero() {
+			return true
+		}
+	}
+	return false
+}
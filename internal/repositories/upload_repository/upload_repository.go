@@ -0,0 +1,55 @@
+package upload_repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUploadNotFound = errors.New("upload not found")
+)
+
+// Upload is a pending upload. It is deleted once the object is processed or,
+// when processing never started, once it expires. ProcessingSince is set
+// while the object is scanned and attached in the background. Until then the
+// upload reserves its declared Size; afterwards ReservedSize, what it will
+// store, until its processing starts storing it.
+type Upload struct {
+	ID              int32
+	PageID          int32
+	Kind            string
+	ObjectKey       string
+	ContentType     string
+	Size            int64
+	Checksum        string
+	ExpiresAt       time.Time
+	CreatedAt       time.Time
+	ProcessingSince time.Time
+	ReservedSize    int64
+}
+
+type UploadRepository interface {
+	CreateUpload(ctx context.Context, upload Upload) (Upload, error)
+	GetUpload(ctx context.Context, pageID, id int32) (Upload, error)
+	DeleteUpload(ctx context.Context, id int32) error
+	// StartProcessing marks the upload as being processed, which keeps it
+	// from expiring and replaces the reservation of its declared size with
+	// reserved bytes.
+	StartProcessing(ctx context.Context, id int32, reserved int64) error
+	// ReleaseReservation drops the reservation of an upload being processed.
+	ReleaseReservation(ctx context.Context, id int32) error
+	// LockStorage holds a lock on what the account stores, shared by every
+	// instance of the service, until unlock is called.
+	LockStorage(ctx context.Context, accountID int32) (unlock func(), err error)
+	// PendingSize returns the bytes reserved by the uploads of the pages of
+	// the account, summed.
+	PendingSize(ctx context.Context, accountID int32) (int64, error)
+	ListExpiredUploads(ctx context.Context) ([]Upload, error)
+	// ListStaleUploads returns the uploads whose processing started before
+	// the given time.
+	ListStaleUploads(ctx context.Context, before time.Time) ([]Upload, error)
+	// FailOrphanedPictures sets the avatar and banner states left pending
+	// with no upload being processed to failed.
+	FailOrphanedPictures(ctx context.Context) error
+}
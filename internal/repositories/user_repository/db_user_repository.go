@@ -9,6 +9,8 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/theNixagen/linker/internal/db"
+	"github.com/theNixagen/linker/internal/domain/username"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
 )
 
 type DbUserRepository struct {
@@ -23,12 +25,20 @@ func NewDbUserRepository(pool *pgxpool.Pool) *DbUserRepository {
 	}
 }
 
-func (r *DbUserRepository) Create(ctx context.Context, user User) (int32, error) {
+func (r *DbUserRepository) Create(ctx context.Context, user User, page page_repository.Page) (int32, error) {
 	if _, err := r.GetUserByUsername(ctx, user.Username); err == nil {
 		return 0, ErrDuplicatedUsername
 	}
 
-	result, err := r.queries.CreateUser(ctx, db.CreateUserParams{
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := r.queries.WithTx(tx)
+
+	result, err := queries.CreateUser(ctx, db.CreateUserParams{
 		Email:    user.Email,
 		Password: user.Password,
 		Name:     pgtype.Text{String: user.Name, Valid: true},
@@ -39,6 +49,9 @@ func (r *DbUserRepository) Create(ctx context.Context, user User) (int32, error)
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == "23505" {
+				if pgErr.ConstraintName == "users_username_lower_key" {
+					return 0, ErrDuplicatedUsername
+				}
 				return 0, ErrDuplicatedEmail
 			}
 			return 0, errors.New("could not insert user")
@@ -46,14 +59,28 @@ func (r *DbUserRepository) Create(ctx context.Context, user User) (int32, error)
 		return 0, err
 	}
 
+	if _, err := queries.CreatePage(ctx, db.CreatePageParams{
+		AccountID:      result,
+		Handle:         page.Handle,
+		Name:           page.Name,
+		HandleSkeleton: pgtype.Text{String: username.Skeleton(page.Handle), Valid: true},
+	}); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, ErrDuplicatedUsername
+		}
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
 	return result, nil
 }
 
 func (r *DbUserRepository) GetUserByUsername(ctx context.Context, username string) (User, error) {
-	user, err := r.queries.GetUserByUsername(ctx, pgtype.Text{
-		String: username,
-		Valid:  true,
-	})
+	user, err := r.queries.GetUserByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return User{}, ErrUserNotFound
@@ -61,43 +88,28 @@ func (r *DbUserRepository) GetUserByUsername(ctx context.Context, username strin
 		return User{}, err
 	}
 
-	return User{
-		ID:             user.ID,
-		Email:          user.Email,
-		Password:       user.Password,
-		CreatedAt:      user.CreatedAt.Time,
-		Name:           user.Name.String,
-		Username:       user.Username.String,
-		Bio:            user.Bio,
-		BannerPicture:  user.BannerPicture,
-		ProfilePicture: user.ProfilePicture,
-	}, nil
+	return toUser(user), nil
 }
 
-func (r *DbUserRepository) UpdateBio(ctx context.Context, username, bio string) error {
-	err := r.queries.UpdateBio(ctx, db.UpdateBioParams{Username: pgtype.Text{String: username, Valid: true}, Bio: bio})
-
+func (r *DbUserRepository) GetUserByID(ctx context.Context, id int32) (User, error) {
+	user, err := r.queries.GetUserByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrUserNotFound
+			return User{}, ErrUserNotFound
 		}
-		return err
+		return User{}, err
 	}
 
-	return nil
+	return toUser(user), nil
 }
 
-func (r *DbUserRepository) UpdateProfilePhoto(ctx context.Context, username, objectName string) error {
-	err := r.queries.UpdateProfilePhoto(ctx, db.UpdateProfilePhotoParams{Username: pgtype.Text{String: username, Valid: true}, ProfilePicture: objectName})
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrUserNotFound
-		}
-		return err
+func toUser(user db.User) User {
+	return User{
+		ID:        user.ID,
+		Email:     user.Email,
+		Password:  user.Password,
+		CreatedAt: user.CreatedAt.Time,
+		Name:      user.Name.String,
+		Username:  user.Username.String,
 	}
-	return nil
-}
-
-func (r *DbUserRepository) UpdateBannerPhoto(ctx context.Context, username, objectName string) error {
-	return nil
 }
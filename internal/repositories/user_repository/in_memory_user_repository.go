@@ -1,20 +1,28 @@
 package user_repository
 
-import "context"
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
 
 type InMemoryUserRepository struct {
-	Users []User
+	Users          []User
+	PageRepository *page_repository.InMemoryPageRepository
 }
 
-func NewInMemoryUserRepository() *InMemoryUserRepository {
+func NewInMemoryUserRepository(pageRepository *page_repository.InMemoryPageRepository) *InMemoryUserRepository {
 	return &InMemoryUserRepository{
-		Users: []User{},
+		Users:          []User{},
+		PageRepository: pageRepository,
 	}
 }
 
-func (r *InMemoryUserRepository) Create(ctx context.Context, user User) (int32, error) {
+func (r *InMemoryUserRepository) Create(ctx context.Context, user User, page page_repository.Page) (int32, error) {
 	for _, u := range r.Users {
-		if u.Username == user.Username {
+		if strings.EqualFold(u.Username, user.Username) {
 			return 0, ErrDuplicatedUsername
 		}
 		if u.Email == user.Email {
@@ -22,45 +30,36 @@ func (r *InMemoryUserRepository) Create(ctx context.Context, user User) (int32,
 		}
 	}
 
+	if user.ID == 0 {
+		user.ID = int32(len(r.Users) + 1)
+	}
+
+	page.AccountID = user.ID
+	if _, err := r.PageRepository.CreatePage(ctx, page); err != nil {
+		if errors.Is(err, page_repository.ErrDuplicatedHandle) {
+			return 0, ErrDuplicatedUsername
+		}
+		return 0, err
+	}
+
 	r.Users = append(r.Users, user)
 	return user.ID, nil
 }
 
 func (r *InMemoryUserRepository) GetUserByUsername(ctx context.Context, username string) (User, error) {
 	for _, u := range r.Users {
-		if u.Username == username {
+		if strings.EqualFold(u.Username, username) {
 			return u, nil
 		}
 	}
 	return User{}, ErrUserNotFound
 }
 
-func (r *InMemoryUserRepository) UpdateBio(ctx context.Context, username, bio string) error {
-	for i, u := range r.Users {
-		if u.Username == username {
-			r.Users[i].Bio = bio
-			return nil
-		}
-	}
-	return ErrUserNotFound
-}
-
-func (r *InMemoryUserRepository) UpdateProfilePhoto(ctx context.Context, username, objectName string) error {
-	for i, u := range r.Users {
-		if u.Username == username {
-			r.Users[i].ProfilePicture = objectName
-			return nil
-		}
-	}
-	return ErrUserNotFound
-}
-
-func (r *InMemoryUserRepository) UpdateBannerPhoto(ctx context.Context, username, objectName string) error {
-	for i, u := range r.Users {
-		if u.Username == username {
-			r.Users[i].BannerPicture = objectName
-			return nil
+func (r *InMemoryUserRepository) GetUserByID(ctx context.Context, id int32) (User, error) {
+	for _, u := range r.Users {
+		if u.ID == id {
+			return u, nil
 		}
 	}
-	return ErrUserNotFound
+	return User{}, ErrUserNotFound
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
 )
 
 var (
@@ -13,21 +15,18 @@ var (
 )
 
 type User struct {
-	ID             int32
-	Email          string
-	Password       string
-	CreatedAt      time.Time
-	ProfilePicture string
-	Bio            string
-	BannerPicture  string
-	Name           string
-	Username       string
+	ID        int32
+	Email     string
+	Password  string
+	CreatedAt time.Time
+	Name      string
+	Username  string
 }
 
 type UserRepository interface {
-	Create(ctx context.Context, user User) (int32, error)
+	// Create stores the user and its first page, owned by the new account, in
+	// a single transaction.
+	Create(ctx context.Context, user User, page page_repository.Page) (int32, error)
 	GetUserByUsername(ctx context.Context, username string) (User, error)
-	UpdateBio(ctx context.Context, username, bio string) error
-	UpdateProfilePhoto(ctx context.Context, username, objectName string) error
-	UpdateBannerPhoto(ctx context.Context, username, objectName string) error
+	GetUserByID(ctx context.Context, id int32) (User, error)
 }
@@ -12,8 +12,11 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/theNixagen/linker/internal/db"
 	"github.com/theNixagen/linker/internal/domain/auth"
+	"github.com/theNixagen/linker/internal/domain/page"
 	"github.com/theNixagen/linker/internal/domain/user"
+	"github.com/theNixagen/linker/internal/domain/username"
 	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
 	"github.com/theNixagen/linker/internal/repositories/user_repository"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -25,20 +28,34 @@ var (
 type AuthService struct {
 	cacheRepository cache_repository.CacheRepository
 	userRepository  user_repository.UserRepository
+	pageRepository  page_repository.PageRepository
 	jwtSecret       string
 	refreshSecret   string
 }
 
-func NewAuthService(jwtSecret, refreshSecret string, userRepository user_repository.UserRepository, cacheRepository cache_repository.CacheRepository) *AuthService {
+func NewAuthService(jwtSecret, refreshSecret string, userRepository user_repository.UserRepository, pageRepository page_repository.PageRepository, cacheRepository cache_repository.CacheRepository) *AuthService {
 	return &AuthService{
 		userRepository:  userRepository,
+		pageRepository:  pageRepository,
 		cacheRepository: cacheRepository,
 		jwtSecret:       jwtSecret,
 		refreshSecret:   refreshSecret,
 	}
 }
 
+// CreateUser creates the account and its first page, whose handle is the
+// account username.
 func (as *AuthService) CreateUser(ctx context.Context, user user.CreateUser) (int, error) {
+	name, err := username.Normalize(user.Username)
+	if err != nil {
+		return 0, err
+	}
+	user.Username = name
+
+	if _, err := as.pageRepository.GetPageByHandle(ctx, user.Username); err == nil {
+		return 0, user_repository.ErrDuplicatedUsername
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), 6)
 
 	if err != nil {
@@ -50,6 +67,10 @@ func (as *AuthService) CreateUser(ctx context.Context, user user.CreateUser) (in
 		Name:     user.Name,
 		Username: user.Username,
 		Password: string(hashedPassword),
+	}, page_repository.Page{
+		Handle:     user.Username,
+		Name:       user.Name,
+		Visibility: page.VisibilityPublic,
 	})
 
 	if err != nil {
@@ -1,19 +1,23 @@
 package services
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/theNixagen/linker/internal/domain/user"
+	"github.com/theNixagen/linker/internal/domain/username"
 	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
 	"github.com/theNixagen/linker/internal/repositories/user_repository"
 )
 
 func TestAuthService_CreateUser(t *testing.T) {
 	jwtSecret := "test_jwt"
 	refreshSecret := "test_refresh"
-	userRepository := user_repository.NewInMemoryUserRepository()
+	pageRepository := page_repository.NewInMemoryPageRepository()
+	userRepository := user_repository.NewInMemoryUserRepository(pageRepository)
 	cacheRepository := cache_repository.NewInMemoryCacheRepository()
-	as := NewAuthService(jwtSecret, refreshSecret, userRepository, cacheRepository)
+	as := NewAuthService(jwtSecret, refreshSecret, userRepository, pageRepository, cacheRepository)
 	as.CreateUser(t.Context(), user.CreateUser{
 		Name:     "john doe",
 		Username: "johndoe",
@@ -24,14 +28,19 @@ func TestAuthService_CreateUser(t *testing.T) {
 	if len(userRepository.Users) != 1 {
 		t.Errorf("expected 1 user, got %d", len(userRepository.Users))
 	}
+
+	if len(pageRepository.Pages) != 1 || pageRepository.Pages[0].Handle != "johndoe" {
+		t.Errorf("expected first page 'johndoe', got %+v", pageRepository.Pages)
+	}
 }
 
 func TestAuthService_AuthUser(t *testing.T) {
 	jwtSecret := "test_jwt"
 	refreshSecret := "test_refresh"
-	userRepository := user_repository.NewInMemoryUserRepository()
+	pageRepository := page_repository.NewInMemoryPageRepository()
+	userRepository := user_repository.NewInMemoryUserRepository(pageRepository)
 	cacheRepository := cache_repository.NewInMemoryCacheRepository()
-	as := NewAuthService(jwtSecret, refreshSecret, userRepository, cacheRepository)
+	as := NewAuthService(jwtSecret, refreshSecret, userRepository, pageRepository, cacheRepository)
 	as.CreateUser(t.Context(), user.CreateUser{
 		Name:     "john doe",
 		Username: "johndoe",
@@ -53,9 +62,10 @@ func TestAuthService_AuthUser(t *testing.T) {
 func TestAuthService_AuthUser_WrongUsername(t *testing.T) {
 	jwtSecret := "test_jwt"
 	refreshSecret := "test_refresh"
-	userRepository := user_repository.NewInMemoryUserRepository()
+	pageRepository := page_repository.NewInMemoryPageRepository()
+	userRepository := user_repository.NewInMemoryUserRepository(pageRepository)
 	cacheRepository := cache_repository.NewInMemoryCacheRepository()
-	as := NewAuthService(jwtSecret, refreshSecret, userRepository, cacheRepository)
+	as := NewAuthService(jwtSecret, refreshSecret, userRepository, pageRepository, cacheRepository)
 	as.CreateUser(t.Context(), user.CreateUser{
 		Name:     "john doe",
 		Username: "johndoe",
@@ -73,9 +83,10 @@ func TestAuthService_AuthUser_WrongUsername(t *testing.T) {
 func TestAuthService_AuthUser_WrongPassword(t *testing.T) {
 	jwtSecret := "test_jwt"
 	refreshSecret := "test_refresh"
-	userRepository := user_repository.NewInMemoryUserRepository()
+	pageRepository := page_repository.NewInMemoryPageRepository()
+	userRepository := user_repository.NewInMemoryUserRepository(pageRepository)
 	cacheRepository := cache_repository.NewInMemoryCacheRepository()
-	as := NewAuthService(jwtSecret, refreshSecret, userRepository, cacheRepository)
+	as := NewAuthService(jwtSecret, refreshSecret, userRepository, pageRepository, cacheRepository)
 	as.CreateUser(t.Context(), user.CreateUser{
 		Name:     "john doe",
 		Username: "johndoe",
@@ -89,3 +100,55 @@ func TestAuthService_AuthUser_WrongPassword(t *testing.T) {
 		t.Errorf("expected error, got nil")
 	}
 }
+
+func TestAuthService_CreateUser_UsernamePolicy(t *testing.T) {
+	pageRepository := page_repository.NewInMemoryPageRepository()
+	userRepository := user_repository.NewInMemoryUserRepository(pageRepository)
+	as := NewAuthService("test_jwt", "test_refresh", userRepository, pageRepository, cache_repository.NewInMemoryCacheRepository())
+
+	if _, err := as.CreateUser(t.Context(), user.CreateUser{Name: "admin", Username: "Admin", Email: "admin@example.com", Password: "supersecretpassword"}); !errors.Is(err, username.ErrReserved) {
+		t.Fatalf("expected ErrReserved, got %v", err)
+	}
+
+	if _, err := as.CreateUser(t.Context(), user.CreateUser{Name: "john doe", Username: " JohnDoe ", Email: "johndoe@example.com", Password: "supersecretpassword"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if userRepository.Users[0].Username != "johndoe" || pageRepository.Pages[0].Handle != "johndoe" {
+		t.Fatalf("expected normalized username, got %v", userRepository.Users[0].Username)
+	}
+
+	if _, err := as.CreateUser(t.Context(), user.CreateUser{Name: "john doe", Username: "JOHNDOE", Email: "other@example.com", Password: "supersecretpassword"}); !errors.Is(err, user_repository.ErrDuplicatedUsername) {
+		t.Fatalf("expected ErrDuplicatedUsername, got %v", err)
+	}
+
+	if _, err := as.CreateUser(t.Context(), user.CreateUser{Name: "john doe", Username: "j0hn_doe", Email: "lookalike@example.com", Password: "supersecretpassword"}); !errors.Is(err, user_repository.ErrDuplicatedUsername) {
+		t.Fatalf("expected lookalikes of existing handles to be taken, got %v", err)
+	}
+
+	if _, _, err := as.AuthUser(t.Context(), "JohnDoe", "supersecretpassword"); err != nil {
+		t.Fatalf("expected case-insensitive login, got %v", err)
+	}
+}
+
+func TestAuthService_CreateUser_NoOrphanedAccount(t *testing.T) {
+	pageRepository := page_repository.NewInMemoryPageRepository()
+	userRepository := user_repository.NewInMemoryUserRepository(pageRepository)
+	as := NewAuthService("test_jwt", "test_refresh", userRepository, pageRepository, cache_repository.NewInMemoryCacheRepository())
+
+	if _, err := userRepository.Create(t.Context(), user_repository.User{Email: "john@example.com", Username: "john"}, page_repository.Page{Handle: "johndoe"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, err := as.CreateUser(t.Context(), user.CreateUser{Name: "john doe", Username: "johndoe", Email: "johndoe@example.com", Password: "supersecretpassword"}); !errors.Is(err, user_repository.ErrDuplicatedUsername) {
+		t.Fatalf("expected ErrDuplicatedUsername, got %v", err)
+	}
+
+	if _, err := userRepository.Create(t.Context(), user_repository.User{Email: "jane@example.com", Username: "jane"}, page_repository.Page{Handle: "JohnDoe"}); !errors.Is(err, user_repository.ErrDuplicatedUsername) {
+		t.Fatalf("expected a failed page to fail the account, got %v", err)
+	}
+
+	if len(userRepository.Users) != 1 || len(pageRepository.Pages) != 1 {
+		t.Fatalf("expected no orphaned account, got %d users and %d pages", len(userRepository.Users), len(pageRepository.Pages))
+	}
+}
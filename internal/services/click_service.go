@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/theNixagen/linker/internal/repositories/click_repository"
+)
+
+var clickSourcePattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+type ClickService struct {
+	ClickRepository click_repository.ClickRepository
+}
+
+func NewClickService(clickRepository click_repository.ClickRepository) *ClickService {
+	return &ClickService{
+		ClickRepository: clickRepository,
+	}
+}
+
+func (cs *ClickService) RecordLinkClick(ctx context.Context, linkID int32, source string) error {
+	return cs.ClickRepository.RecordLinkClick(ctx, linkID, NormalizeClickSource(source))
+}
+
+func (cs *ClickService) RecordProfileView(ctx context.Context, pageID int32, source string) error {
+	return cs.ClickRepository.RecordProfileView(ctx, pageID, NormalizeClickSource(source))
+}
+
+// NormalizeClickSource lowercases the source marker and drops values that
+// would only add noise to the analytics, such as free text.
+func NormalizeClickSource(source string) string {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if !clickSourcePattern.MatchString(source) {
+		return ""
+	}
+	return source
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/theNixagen/linker/internal/repositories/click_repository"
+)
+
+func TestClickService_RecordLinkClick(t *testing.T) {
+	cr := click_repository.NewInMemoryClickRepository()
+	cs := NewClickService(cr)
+
+	sources := map[string]string{
+		"qr":                                   "qr",
+		" QR ":                                 "qr",
+		"":                                     "",
+		"<script>":                             "",
+		"a-very-long-source-name-that-goes-on": "",
+	}
+
+	for source := range sources {
+		if err := cs.RecordLinkClick(t.Context(), 1, source); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	if len(cr.Clicks) != len(sources) {
+		t.Fatalf("expected %d clicks, got %d", len(sources), len(cr.Clicks))
+	}
+
+	for _, click := range cr.Clicks {
+		if click.LinkID != 1 {
+			t.Fatalf("expected click on link 1, got %d", click.LinkID)
+		}
+		if click.Source != "" && click.Source != "qr" {
+			t.Fatalf("expected source to be normalized, got %q", click.Source)
+		}
+	}
+}
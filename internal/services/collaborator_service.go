@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/audit"
+	"github.com/theNixagen/linker/internal/domain/collaborator"
+	"github.com/theNixagen/linker/internal/repositories/audit_repository"
+	"github.com/theNixagen/linker/internal/repositories/collaborator_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/user_repository"
+)
+
+const (
+	invitationTTL   = 7 * 24 * time.Hour
+	auditEntryLimit = 100
+)
+
+var (
+	ErrAlreadyCollaborator = errors.New("account already has access to this page")
+	ErrInvitationExpired   = errors.New("invitation expired")
+	ErrInvitationMismatch  = errors.New("invitation was sent to another email")
+)
+
+type CollaboratorService struct {
+	PageRepository         page_repository.PageRepository
+	UserRepository         user_repository.UserRepository
+	CollaboratorRepository collaborator_repository.CollaboratorRepository
+	AuditRepository        audit_repository.AuditRepository
+	Mailer                 Mailer
+	publicURL              string
+}
+
+func NewCollaboratorService(publicURL string, pageRepository page_repository.PageRepository, userRepository user_repository.UserRepository, collaboratorRepository collaborator_repository.CollaboratorRepository, auditRepository audit_repository.AuditRepository, mailer Mailer) *CollaboratorService {
+	return &CollaboratorService{
+		PageRepository:         pageRepository,
+		UserRepository:         userRepository,
+		CollaboratorRepository: collaboratorRepository,
+		AuditRepository:        auditRepository,
+		Mailer:                 mailer,
+		publicURL:              publicURL,
+	}
+}
+
+// Invite emails a single-use invitation to join the page with the given role.
+// Inviting the same email again replaces the previous invitation. The token
+// only travels in the email, so nothing is created without a mailer.
+func (cs *CollaboratorService) Invite(ctx context.Context, handle string, inviterID int32, req collaborator.InviteCollaborator) (collaborator.GetInvitation, error) {
+	if cs.Mailer == nil {
+		return collaborator.GetInvitation{}, ErrMailerUnavailable
+	}
+
+	owner, err := cs.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return collaborator.GetInvitation{}, err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	collaborators, err := cs.ListCollaborators(ctx, handle)
+	if err != nil {
+		return collaborator.GetInvitation{}, err
+	}
+	for _, c := range collaborators {
+		if strings.EqualFold(c.Email, email) {
+			return collaborator.GetInvitation{}, ErrAlreadyCollaborator
+		}
+	}
+
+	token, tokenHash, err := generateInvitationToken()
+	if err != nil {
+		return collaborator.GetInvitation{}, err
+	}
+
+	invitation, err := cs.CollaboratorRepository.CreateInvitation(ctx, collaborator_repository.Invitation{
+		PageID:    owner.ID,
+		Email:     email,
+		Role:      req.Role,
+		TokenHash: tokenHash,
+		InvitedBy: inviterID,
+		ExpiresAt: time.Now().Add(invitationTTL),
+	})
+	if err != nil {
+		return collaborator.GetInvitation{}, err
+	}
+
+	body := fmt.Sprintf("You were invited to manage the page @%s as %s.\n\nSign in as %s and accept the invitation with a POST to %s/invitations/%s/accept\n", owner.Handle, req.Role, email, cs.publicURL, token)
+	if err := cs.Mailer.Send(ctx, email, fmt.Sprintf("Invitation to manage @%s", owner.Handle), body); err != nil {
+		return collaborator.GetInvitation{}, err
+	}
+
+	return toGetInvitation(invitation), nil
+}
+
+// AcceptInvitation adds the account as a collaborator. The invitation can only
+// be accepted by the account whose email it was sent to.
+func (cs *CollaboratorService) AcceptInvitation(ctx context.Context, accountID int32, token string) (collaborator.Membership, error) {
+	invitation, err := cs.CollaboratorRepository.FindInvitationByTokenHash(ctx, hashInvitationToken(token))
+	if err != nil {
+		return collaborator.Membership{}, err
+	}
+
+	if time.Now().After(invitation.ExpiresAt) {
+		return collaborator.Membership{}, ErrInvitationExpired
+	}
+
+	account, err := cs.UserRepository.GetUserByID(ctx, accountID)
+	if err != nil {
+		return collaborator.Membership{}, err
+	}
+
+	if !strings.EqualFold(account.Email, invitation.Email) {
+		return collaborator.Membership{}, ErrInvitationMismatch
+	}
+
+	invited, err := cs.PageRepository.GetPageByID(ctx, invitation.PageID)
+	if err != nil {
+		return collaborator.Membership{}, err
+	}
+
+	if invited.AccountID == accountID {
+		return collaborator.Membership{}, ErrAlreadyCollaborator
+	}
+
+	if err := cs.CollaboratorRepository.AcceptInvitation(ctx, invitation, accountID); err != nil {
+		return collaborator.Membership{}, err
+	}
+
+	return collaborator.Membership{Handle: invited.Handle, Role: invitation.Role}, nil
+}
+
+// ListCollaborators returns the owner followed by the page collaborators.
+func (cs *CollaboratorService) ListCollaborators(ctx context.Context, handle string) ([]collaborator.GetCollaborator, error) {
+	owner, err := cs.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := cs.UserRepository.GetUserByID(ctx, owner.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborators, err := cs.CollaboratorRepository.ListCollaborators(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]collaborator.GetCollaborator, 0, len(collaborators)+1)
+	result = append(result, collaborator.GetCollaborator{
+		AccountID: account.ID,
+		Email:     account.Email,
+		Name:      account.Name,
+		Role:      collaborator.RoleOwner,
+		CreatedAt: owner.CreatedAt,
+	})
+	for _, c := range collaborators {
+		result = append(result, collaborator.GetCollaborator{
+			AccountID: c.AccountID,
+			Email:     c.Email,
+			Name:      c.Name,
+			Role:      c.Role,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func (cs *CollaboratorService) ListInvitations(ctx context.Context, handle string) ([]collaborator.GetInvitation, error) {
+	owner, err := cs.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := cs.CollaboratorRepository.ListInvitations(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]collaborator.GetInvitation, 0, len(invitations))
+	for _, invitation := range invitations {
+		result = append(result, toGetInvitation(invitation))
+	}
+
+	return result, nil
+}
+
+func (cs *CollaboratorService) RemoveCollaborator(ctx context.Context, handle string, accountID int32) error {
+	owner, err := cs.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+
+	return cs.CollaboratorRepository.RemoveCollaborator(ctx, owner.ID, accountID)
+}
+
+// Record adds an entry to the audit log of a page.
+func (cs *CollaboratorService) Record(ctx context.Context, pageID, accountID int32, action string, details map[string]string) error {
+	return cs.AuditRepository.CreateEntry(ctx, audit_repository.Entry{
+		PageID:    pageID,
+		AccountID: accountID,
+		Action:    action,
+		Details:   details,
+	})
+}
+
+// ListAudit returns the most recent changes made to a page.
+func (cs *CollaboratorService) ListAudit(ctx context.Context, handle string) ([]audit.Entry, error) {
+	owner, err := cs.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := cs.AuditRepository.ListEntries(ctx, owner.ID, auditEntryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]audit.Entry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, audit.Entry{
+			ID:        entry.ID,
+			AccountID: entry.AccountID,
+			Email:     entry.Email,
+			Action:    entry.Action,
+			Details:   entry.Details,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+func generateInvitationToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(buf)
+	return token, hashInvitationToken(token), nil
+}
+
+// hashInvitationToken keeps only a digest of invitation tokens in the
+// database, so a leaked table can't be used to join pages.
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toGetInvitation(invitation collaborator_repository.Invitation) collaborator.GetInvitation {
+	return collaborator.GetInvitation{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/collaborator"
+	"github.com/theNixagen/linker/internal/repositories/audit_repository"
+	"github.com/theNixagen/linker/internal/repositories/collaborator_repository"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/user_repository"
+)
+
+type fakeMailer struct {
+	sent []string
+}
+
+func (m *fakeMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.sent = append(m.sent, body)
+	return nil
+}
+
+var invitationTokenPattern = regexp.MustCompile(`/invitations/([0-9a-f]+)/accept`)
+
+func (m *fakeMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatalf("expected an email to be sent")
+	}
+	match := invitationTokenPattern.FindStringSubmatch(m.sent[len(m.sent)-1])
+	if match == nil {
+		t.Fatalf("expected an invitation link, got %q", m.sent[len(m.sent)-1])
+	}
+	return match[1]
+}
+
+func newTestCollaboratorService(t *testing.T) (*CollaboratorService, *PageService, *collaborator_repository.InMemoryCollaboratorRepository, *fakeMailer) {
+	pr := page_repository.NewInMemoryPageRepository()
+	ur := user_repository.NewInMemoryUserRepository(pr)
+	cr := collaborator_repository.NewInMemoryCollaboratorRepository()
+	mailer := &fakeMailer{}
+
+	ur.Create(t.Context(), user_repository.User{ID: 1, Email: "johndoe@example.com", Name: "John Doe", Username: "johndoe"}, page_repository.Page{ID: 1, Handle: "johndoe", Name: "John Doe"})
+	ur.Create(t.Context(), user_repository.User{ID: 2, Email: "manager@example.com", Name: "Manager", Username: "manager"}, page_repository.Page{ID: 2, Handle: "manager", Name: "Manager"})
+
+	cs := NewCollaboratorService("https://linker.app", pr, ur, cr, audit_repository.NewInMemoryAuditRepository(), mailer)
+	pub := published_profile_repository.NewInMemoryPublishedProfileRepository(links_repository.NewInMemoryLinksRepository())
+	return cs, NewPageService(pr, pub, cr), cr, mailer
+}
+
+func TestCollaboratorService_InviteAndAccept(t *testing.T) {
+	cs, ps, _, mailer := newTestCollaboratorService(t)
+
+	if _, _, err := ps.ManagedPage(t.Context(), 2, "johndoe"); !errors.Is(err, page_repository.ErrPageNotFound) {
+		t.Fatalf("expected page to be hidden before accepting, got %v", err)
+	}
+
+	invitation, err := cs.Invite(t.Context(), "johndoe", 1, collaborator.InviteCollaborator{Email: "Manager@Example.com", Role: collaborator.RoleEditor})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if invitation.Email != "manager@example.com" {
+		t.Fatalf("expected normalized email, got %v", invitation.Email)
+	}
+
+	membership, err := cs.AcceptInvitation(t.Context(), 2, mailer.lastToken(t))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if membership.Handle != "johndoe" || membership.Role != collaborator.RoleEditor {
+		t.Fatalf("expected editor of johndoe, got %+v", membership)
+	}
+
+	managed, role, err := ps.ManagedPage(t.Context(), 2, "johndoe")
+	if err != nil || managed.Handle != "johndoe" || role != collaborator.RoleEditor {
+		t.Fatalf("expected editor access to johndoe, got %v as %v (%v)", managed.Handle, role, err)
+	}
+
+	pages, _ := ps.ListPages(t.Context(), 2)
+	if len(pages) != 2 || pages[0].Role != collaborator.RoleOwner || pages[1].Role != collaborator.RoleEditor {
+		t.Fatalf("expected owned and shared pages, got %+v", pages)
+	}
+
+	collaborators, _ := cs.ListCollaborators(t.Context(), "johndoe")
+	if len(collaborators) != 2 || collaborators[0].Role != collaborator.RoleOwner {
+		t.Fatalf("expected owner and editor, got %+v", collaborators)
+	}
+
+	if _, err := cs.Invite(t.Context(), "johndoe", 1, collaborator.InviteCollaborator{Email: "manager@example.com", Role: collaborator.RoleAnalyst}); !errors.Is(err, ErrAlreadyCollaborator) {
+		t.Fatalf("expected ErrAlreadyCollaborator, got %v", err)
+	}
+
+	if err := cs.RemoveCollaborator(t.Context(), "johndoe", 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if _, _, err := ps.ManagedPage(t.Context(), 2, "johndoe"); !errors.Is(err, page_repository.ErrPageNotFound) {
+		t.Fatalf("expected access to be revoked, got %v", err)
+	}
+}
+
+func TestCollaboratorService_AcceptInvitation_Rejected(t *testing.T) {
+	cs, _, cr, mailer := newTestCollaboratorService(t)
+
+	cs.Invite(t.Context(), "johndoe", 1, collaborator.InviteCollaborator{Email: "someone@example.com", Role: collaborator.RoleAnalyst})
+	token := mailer.lastToken(t)
+
+	if _, err := cs.AcceptInvitation(t.Context(), 2, token); !errors.Is(err, ErrInvitationMismatch) {
+		t.Fatalf("expected ErrInvitationMismatch, got %v", err)
+	}
+
+	if _, err := cs.AcceptInvitation(t.Context(), 2, "unknown"); !errors.Is(err, collaborator_repository.ErrInvitationNotFound) {
+		t.Fatalf("expected ErrInvitationNotFound, got %v", err)
+	}
+
+	cs.Invite(t.Context(), "johndoe", 1, collaborator.InviteCollaborator{Email: "manager@example.com", Role: collaborator.RoleAnalyst})
+	cr.Invitations[len(cr.Invitations)-1].ExpiresAt = cr.Invitations[len(cr.Invitations)-1].CreatedAt.Add(-1)
+
+	if _, err := cs.AcceptInvitation(t.Context(), 2, mailer.lastToken(t)); !errors.Is(err, ErrInvitationExpired) {
+		t.Fatalf("expected ErrInvitationExpired, got %v", err)
+	}
+}
+
+func TestCollaboratorService_Invite_NoMailer(t *testing.T) {
+	cs, _, cr, _ := newTestCollaboratorService(t)
+	cs.Mailer = nil
+
+	if _, err := cs.Invite(t.Context(), "johndoe", 1, collaborator.InviteCollaborator{Email: "manager@example.com", Role: collaborator.RoleEditor}); !errors.Is(err, ErrMailerUnavailable) {
+		t.Fatalf("expected ErrMailerUnavailable, got %v", err)
+	}
+	if len(cr.Invitations) != 0 {
+		t.Fatalf("expected no invitation to be created, got %+v", cr.Invitations)
+	}
+}
+
+func TestCollaboratorService_Audit(t *testing.T) {
+	cs, _, _, _ := newTestCollaboratorService(t)
+
+	cs.Record(t.Context(), 1, 1, "PUT /profile/bio", nil)
+	cs.Record(t.Context(), 2, 2, "PUT /profile/bio", nil)
+	cs.Record(t.Context(), 1, 2, "POST /profile/link", map[string]string{"id": "3"})
+
+	entries, err := cs.ListAudit(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].Action != "POST /profile/link" || entries[0].AccountID != 2 {
+		t.Fatalf("expected latest entries of johndoe first, got %+v", entries)
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	clamdChunkSize      = 64 << 10
+	clamdDefaultTimeout = 30 * time.Second
+)
+
+var (
+	ErrContentRejected    = errors.New("file was rejected by the content scanner")
+	ErrScannerUnavailable = errors.New("content scanner is unavailable")
+)
+
+// ScanResult is the verdict of a ContentScanner. Signature names what was
+// found in files that are not clean.
+type ScanResult struct {
+	Clean     bool
+	Signature string
+}
+
+// ContentScanner inspects uploaded files for malware or policy violations
+// before they become public.
+type ContentScanner interface {
+	Scan(ctx context.Context, data []byte) (ScanResult, error)
+}
+
+// NoopScanner accepts every file. It is the default until a scanner is
+// configured.
+type NoopScanner struct{}
+
+func (NoopScanner) Scan(ctx context.Context, data []byte) (ScanResult, error) {
+	return ScanResult{Clean: true}, nil
+}
+
+// ClamdScanner streams files to a ClamAV daemon with the INSTREAM command.
+// Address is a host:port pair, or the path of a unix socket.
+type ClamdScanner struct {
+	Address string
+	Timeout time.Duration
+}
+
+func NewClamdScanner(address string) *ClamdScanner {
+	return &ClamdScanner{
+		Address: address,
+		Timeout: clamdDefaultTimeout,
+	}
+}
+
+func (cs *ClamdScanner) Scan(ctx context.Context, data []byte) (ScanResult, error) {
+	network := "tcp"
+	if strings.HasPrefix(cs.Address, "/") {
+		network = "unix"
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, cs.Address)
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("%w: %v", ErrScannerUnavailable, err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(cs.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	reply, err := clamdInstream(conn, data)
+	if err != nil {
+		return ScanResult{}, fmt.Errorf("%w: %v", ErrScannerUnavailable, err)
+	}
+	return parseClamdReply(reply)
+}
+
+// clamdInstream sends data as length-prefixed chunks followed by an empty
+// chunk and reads the null-terminated reply.
+func clamdInstream(conn net.Conn, data []byte) (string, error) {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return "", err
+	}
+
+	size := make([]byte, 4)
+	for len(data) > 0 {
+		chunk := data[:min(len(data), clamdChunkSize)]
+		data = data[len(chunk):]
+
+		binary.BigEndian.PutUint32(size, uint32(len(chunk)))
+		if _, err := conn.Write(size); err != nil {
+			return "", err
+		}
+		if _, err := conn.Write(chunk); err != nil {
+			return "", err
+		}
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimRight(reply, "\x00")), nil
+}
+
+// parseClamdReply reads replies such as "stream: OK" and
+// "stream: Eicar-Signature FOUND".
+func parseClamdReply(reply string) (ScanResult, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case result == "OK":
+		return ScanResult{Clean: true}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return ScanResult{Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return ScanResult{}, fmt.Errorf("%w: %s", ErrScannerUnavailable, reply)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+// fakeClamd answers INSTREAM commands, flagging streams that contain the
+// EICAR marker.
+func fakeClamd(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveClamd(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func serveClamd(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	command, err := reader.ReadString(0)
+	if err != nil || command != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+
+	var stream bytes.Buffer
+	size := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(reader, size); err != nil {
+			return
+		}
+		n := binary.BigEndian.Uint32(size)
+		if n == 0 {
+			break
+		}
+		if _, err := io.CopyN(&stream, reader, int64(n)); err != nil {
+			return
+		}
+	}
+
+	if bytes.Contains(stream.Bytes(), []byte("EICAR")) {
+		conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		return
+	}
+	conn.Write([]byte("stream: OK\x00"))
+}
+
+func TestClamdScanner_Scan(t *testing.T) {
+	scanner := NewClamdScanner(fakeClamd(t))
+
+	result, err := scanner.Scan(t.Context(), bytes.Repeat([]byte("a"), 3*clamdChunkSize+1))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Clean {
+		t.Errorf("expected a clean result, got %+v", result)
+	}
+
+	result, err = scanner.Scan(t.Context(), []byte("X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Clean || result.Signature != "Eicar-Test-Signature" {
+		t.Errorf("expected the EICAR signature, got %+v", result)
+	}
+}
+
+func TestClamdScanner_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	if _, err := NewClamdScanner(address).Scan(t.Context(), []byte("data")); !errors.Is(err, ErrScannerUnavailable) {
+		t.Errorf("expected ErrScannerUnavailable, got %v", err)
+	}
+}
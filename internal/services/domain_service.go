@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/customdomain"
+	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/custom_domain_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+const (
+	maxDomainsPerUser      = 5
+	domainResolutionTTL    = 5 * time.Minute
+	domainNotFound         = "none"
+	domainReverifyInterval = 24 * time.Hour
+	domainReverifyBatch    = 100
+	domainPendingTTL       = 7 * 24 * time.Hour
+)
+
+var (
+	ErrTooManyDomains = errors.New("domain limit reached")
+	ErrRecordNotFound = errors.New("verification TXT record not found")
+	ErrRecordMismatch = errors.New("verification TXT record does not match")
+	ErrDomainTaken    = errors.New("domain is verified by another page")
+)
+
+// Resolver is the subset of net.Resolver used to verify domain ownership.
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+type DomainService struct {
+	PageRepository         page_repository.PageRepository
+	CustomDomainRepository custom_domain_repository.CustomDomainRepository
+	CacheRepository        cache_repository.CacheRepository
+	resolver               Resolver
+	platformHost           string
+}
+
+func NewDomainService(platformHost string, resolver Resolver, pageRepository page_repository.PageRepository, customDomainRepository custom_domain_repository.CustomDomainRepository, cacheRepository cache_repository.CacheRepository) *DomainService {
+	return &DomainService{
+		PageRepository:         pageRepository,
+		CustomDomainRepository: customDomainRepository,
+		CacheRepository:        cacheRepository,
+		resolver:               resolver,
+		platformHost:           strings.ToLower(platformHost),
+	}
+}
+
+func (ds *DomainService) AddDomain(ctx context.Context, handle string, req customdomain.CreateCustomDomain) (customdomain.GetCustomDomain, error) {
+	owner, err := ds.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return customdomain.GetCustomDomain{}, err
+	}
+
+	name, err := customdomain.Normalize(req.Domain)
+	if err != nil {
+		return customdomain.GetCustomDomain{}, err
+	}
+
+	if ds.platformHost != "" && (customdomain.IsSubdomainOf(name, ds.platformHost) || customdomain.IsSubdomainOf(ds.platformHost, name)) {
+		return customdomain.GetCustomDomain{}, customdomain.ErrReservedDomain
+	}
+
+	existing, err := ds.CustomDomainRepository.ListDomainsByPage(ctx, owner.ID)
+	if err != nil {
+		return customdomain.GetCustomDomain{}, err
+	}
+	if len(existing) >= maxDomainsPerUser {
+		return customdomain.GetCustomDomain{}, ErrTooManyDomains
+	}
+
+	token, err := generateVerificationToken()
+	if err != nil {
+		return customdomain.GetCustomDomain{}, err
+	}
+
+	created, err := ds.CustomDomainRepository.CreateDomain(ctx, custom_domain_repository.CustomDomain{
+		PageID:            owner.ID,
+		Domain:            name,
+		VerificationToken: token,
+	})
+	if err != nil {
+		return customdomain.GetCustomDomain{}, err
+	}
+
+	return toGetCustomDomain(created), nil
+}
+
+func (ds *DomainService) ListDomains(ctx context.Context, handle string) ([]customdomain.GetCustomDomain, error) {
+	owner, err := ds.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	domains, err := ds.CustomDomainRepository.ListDomainsByPage(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]customdomain.GetCustomDomain, 0, len(domains))
+	for _, domain := range domains {
+		result = append(result, toGetCustomDomain(domain))
+	}
+	return result, nil
+}
+
+// VerifyDomain checks the TXT record right away, used when the owner asks for
+// a new check after publishing the record.
+func (ds *DomainService) VerifyDomain(ctx context.Context, handle string, id int32) (customdomain.GetCustomDomain, error) {
+	domain, err := ds.findOwnedDomain(ctx, handle, id)
+	if err != nil {
+		return customdomain.GetCustomDomain{}, err
+	}
+
+	domain, err = ds.verify(ctx, domain)
+	if err != nil {
+		return customdomain.GetCustomDomain{}, err
+	}
+
+	return toGetCustomDomain(domain), nil
+}
+
+func (ds *DomainService) DeleteDomain(ctx context.Context, handle string, id int32) error {
+	domain, err := ds.findOwnedDomain(ctx, handle, id)
+	if err != nil {
+		return err
+	}
+
+	if err := ds.CustomDomainRepository.DeleteDomain(ctx, domain.PageID, domain.ID); err != nil {
+		return err
+	}
+
+	ds.invalidateDomain(ctx, domain.Domain)
+	return nil
+}
+
+// ResolveHost returns the handle served on host, if host is a verified
+// custom domain.
+func (ds *DomainService) ResolveHost(ctx context.Context, host string) (string, error) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+	key := fmt.Sprintf("domain:host:%s", host)
+	if cached, err := ds.CacheRepository.Get(ctx, key); err == nil && cached != "" {
+		if cached == domainNotFound {
+			return "", custom_domain_repository.ErrDomainNotFound
+		}
+		return cached, nil
+	}
+
+	domain, err := ds.CustomDomainRepository.FindVerifiedDomain(ctx, host)
+	if err != nil {
+		if errors.Is(err, custom_domain_repository.ErrDomainNotFound) {
+			ds.CacheRepository.Set(ctx, key, domainNotFound, domainResolutionTTL)
+		}
+		return "", err
+	}
+
+	owner, err := ds.PageRepository.GetPageByID(ctx, domain.PageID)
+	if err != nil {
+		return "", err
+	}
+
+	ds.CacheRepository.Set(ctx, key, owner.Handle, domainResolutionTTL)
+	return owner.Handle, nil
+}
+
+// ReverifyDomains checks every domain not checked within the interval. A
+// verified domain whose record disappeared stops being served, while DNS
+// errors keep the current status until the next run. Claims still pending
+// after domainPendingTTL are dropped first, freeing the owner's domain slot.
+func (ds *DomainService) ReverifyDomains(ctx context.Context) error {
+	if _, err := ds.CustomDomainRepository.DeleteExpiredDomains(ctx, time.Now().Add(-domainPendingTTL)); err != nil {
+		return err
+	}
+
+	domains, err := ds.CustomDomainRepository.ListDomainsToVerify(ctx, time.Now().Add(-domainReverifyInterval), domainReverifyBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, domain := range domains {
+		if _, err := ds.verify(ctx, domain); err != nil {
+			log.Printf("could not verify domain %s: %v", domain.Domain, err)
+		}
+	}
+	return nil
+}
+
+// RunReverification calls ReverifyDomains once right away and then on every
+// tick until ctx is done.
+func (ds *DomainService) RunReverification(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := ds.ReverifyDomains(ctx); err != nil {
+			log.Printf("could not reverify domains: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ds *DomainService) verify(ctx context.Context, domain custom_domain_repository.CustomDomain) (custom_domain_repository.CustomDomain, error) {
+	status, reason := domain.Status, ""
+
+	checkErr := ds.checkRecord(ctx, domain)
+	switch {
+	case checkErr == nil:
+		status = customdomain.StatusVerified
+	case errors.Is(checkErr, ErrRecordNotFound) || errors.Is(checkErr, ErrRecordMismatch):
+		if domain.Status == customdomain.StatusVerified {
+			status = customdomain.StatusFailed
+		}
+		reason = checkErr.Error()
+	default:
+		reason = "dns lookup failed, will retry"
+	}
+
+	err := ds.CustomDomainRepository.UpdateDomainStatus(ctx, domain.ID, status, reason)
+	if errors.Is(err, custom_domain_repository.ErrDuplicatedDomain) {
+		// Another page verified the domain first, so the record proves
+		// nothing until that page removes it.
+		status, reason = domain.Status, ErrDomainTaken.Error()
+		err = ds.CustomDomainRepository.UpdateDomainStatus(ctx, domain.ID, status, reason)
+	}
+	if err != nil {
+		return custom_domain_repository.CustomDomain{}, err
+	}
+
+	if status != domain.Status {
+		ds.invalidateDomain(ctx, domain.Domain)
+	}
+
+	return ds.CustomDomainRepository.FindDomainByID(ctx, domain.ID)
+}
+
+func (ds *DomainService) checkRecord(ctx context.Context, domain custom_domain_repository.CustomDomain) error {
+	expected := customdomain.NewVerificationRecord(domain.Domain, domain.VerificationToken)
+
+	records, err := ds.resolver.LookupTXT(ctx, expected.Name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return ErrRecordNotFound
+		}
+		return err
+	}
+
+	if len(records) == 0 {
+		return ErrRecordNotFound
+	}
+	if !slices.Contains(records, expected.Value) {
+		return ErrRecordMismatch
+	}
+	return nil
+}
+
+func (ds *DomainService) findOwnedDomain(ctx context.Context, handle string, id int32) (custom_domain_repository.CustomDomain, error) {
+	owner, err := ds.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return custom_domain_repository.CustomDomain{}, err
+	}
+
+	domain, err := ds.CustomDomainRepository.FindDomainByID(ctx, id)
+	if err != nil {
+		return custom_domain_repository.CustomDomain{}, err
+	}
+
+	if domain.PageID != owner.ID {
+		return custom_domain_repository.CustomDomain{}, custom_domain_repository.ErrDomainNotFound
+	}
+	return domain, nil
+}
+
+func (ds *DomainService) invalidateDomain(ctx context.Context, name string) {
+	if err := ds.CacheRepository.Del(ctx, fmt.Sprintf("domain:host:%s", name)); err != nil {
+		log.Printf("could not invalidate domain %s: %v", name, err)
+	}
+}
+
+func generateVerificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func toGetCustomDomain(domain custom_domain_repository.CustomDomain) customdomain.GetCustomDomain {
+	result := customdomain.GetCustomDomain{
+		ID:            domain.ID,
+		Domain:        domain.Domain,
+		Status:        domain.Status,
+		FailureReason: domain.FailureReason,
+		Record:        customdomain.NewVerificationRecord(domain.Domain, domain.VerificationToken),
+		CreatedAt:     domain.CreatedAt,
+	}
+	if !domain.LastCheckedAt.IsZero() {
+		result.LastCheckedAt = &domain.LastCheckedAt
+	}
+	if !domain.VerifiedAt.IsZero() {
+		result.VerifiedAt = &domain.VerifiedAt
+	}
+	return result
+}
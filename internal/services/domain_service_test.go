@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/customdomain"
+	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/custom_domain_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+type fakeResolver struct {
+	records map[string][]string
+	err     error
+}
+
+func (f *fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	records, ok := f.records[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func newTestDomainService(t *testing.T) (*DomainService, *fakeResolver, *custom_domain_repository.InMemoryCustomDomainRepository) {
+	pr := page_repository.NewInMemoryPageRepository()
+	pr.CreatePage(t.Context(), page_repository.Page{
+		ID:        1,
+		AccountID: 1,
+		Handle:    "johndoe",
+		Name:      "John Doe",
+	})
+	pr.CreatePage(t.Context(), page_repository.Page{
+		ID:        2,
+		AccountID: 2,
+		Handle:    "janedoe",
+		Name:      "Jane Doe",
+	})
+
+	resolver := &fakeResolver{records: map[string][]string{}}
+	dr := custom_domain_repository.NewInMemoryCustomDomainRepository()
+	ds := NewDomainService("linker.app", resolver, pr, dr, cache_repository.NewInMemoryCacheRepository())
+	return ds, resolver, dr
+}
+
+func TestDomainService_AddDomain(t *testing.T) {
+	ds, _, _ := newTestDomainService(t)
+
+	domain, err := ds.AddDomain(t.Context(), "johndoe", customdomain.CreateCustomDomain{Domain: " Links.Example.com. "})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if domain.Domain != "links.example.com" || domain.Status != customdomain.StatusPending {
+		t.Fatalf("expected pending links.example.com, got %+v", domain)
+	}
+
+	if domain.Record.Name != "_linker-verification.links.example.com" || domain.Record.Type != "TXT" {
+		t.Fatalf("unexpected verification record %+v", domain.Record)
+	}
+
+	tests := []struct {
+		domain string
+		want   error
+	}{
+		{"johndoe.linker.app", customdomain.ErrReservedDomain},
+		{"linker.app", customdomain.ErrReservedDomain},
+		{"localhost", customdomain.ErrInvalidDomain},
+		{"127.0.0.1", customdomain.ErrInvalidDomain},
+		{"https://example.com/path", customdomain.ErrInvalidDomain},
+		{"-bad.example.com", customdomain.ErrInvalidDomain},
+	}
+
+	for _, tt := range tests {
+		if _, err := ds.AddDomain(t.Context(), "janedoe", customdomain.CreateCustomDomain{Domain: tt.domain}); !errors.Is(err, tt.want) {
+			t.Errorf("expected %v for %s, got %v", tt.want, tt.domain, err)
+		}
+	}
+
+	if _, err := ds.AddDomain(t.Context(), "johndoe", customdomain.CreateCustomDomain{Domain: "links.example.com"}); !errors.Is(err, custom_domain_repository.ErrDuplicatedDomain) {
+		t.Fatalf("expected ErrDuplicatedDomain for the same page, got %v", err)
+	}
+	if _, err := ds.AddDomain(t.Context(), "janedoe", customdomain.CreateCustomDomain{Domain: "links.example.com"}); err != nil {
+		t.Fatalf("expected a pending claim not to block other pages, got %v", err)
+	}
+}
+
+func TestDomainService_VerifyDomain_Taken(t *testing.T) {
+	ds, resolver, _ := newTestDomainService(t)
+
+	first, _ := ds.AddDomain(t.Context(), "johndoe", customdomain.CreateCustomDomain{Domain: "links.example.com"})
+	second, _ := ds.AddDomain(t.Context(), "janedoe", customdomain.CreateCustomDomain{Domain: "links.example.com"})
+	resolver.records[first.Record.Name] = []string{first.Record.Value, second.Record.Value}
+
+	if verified, _ := ds.VerifyDomain(t.Context(), "janedoe", second.ID); verified.Status != customdomain.StatusVerified {
+		t.Fatalf("expected the first page to verify to own the domain, got %+v", verified)
+	}
+
+	taken, err := ds.VerifyDomain(t.Context(), "johndoe", first.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if taken.Status != customdomain.StatusPending || taken.FailureReason != ErrDomainTaken.Error() {
+		t.Fatalf("expected claim to stay pending as taken, got %+v", taken)
+	}
+
+	if handle, _ := ds.ResolveHost(t.Context(), "links.example.com"); handle != "janedoe" {
+		t.Fatalf("expected janedoe, got %v", handle)
+	}
+}
+
+func TestDomainService_ReverifyDomains_ExpiresPending(t *testing.T) {
+	ds, _, dr := newTestDomainService(t)
+
+	ds.AddDomain(t.Context(), "johndoe", customdomain.CreateCustomDomain{Domain: "old.example.com"})
+	ds.AddDomain(t.Context(), "johndoe", customdomain.CreateCustomDomain{Domain: "new.example.com"})
+	dr.Domains[0].CreatedAt = time.Now().Add(-domainPendingTTL - time.Hour)
+
+	if err := ds.ReverifyDomains(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(dr.Domains) != 1 || dr.Domains[0].Domain != "new.example.com" {
+		t.Fatalf("expected only the expired claim to be dropped, got %+v", dr.Domains)
+	}
+}
+
+func TestDomainService_VerifyDomain(t *testing.T) {
+	ds, resolver, _ := newTestDomainService(t)
+
+	domain, _ := ds.AddDomain(t.Context(), "johndoe", customdomain.CreateCustomDomain{Domain: "links.example.com"})
+
+	pending, err := ds.VerifyDomain(t.Context(), "johndoe", domain.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pending.Status != customdomain.StatusPending || pending.FailureReason == "" {
+		t.Fatalf("expected pending domain with a reason, got %+v", pending)
+	}
+
+	if _, err := ds.ResolveHost(t.Context(), "links.example.com"); !errors.Is(err, custom_domain_repository.ErrDomainNotFound) {
+		t.Fatalf("expected unverified domain not to resolve, got %v", err)
+	}
+
+	resolver.records[domain.Record.Name] = []string{"other", domain.Record.Value}
+
+	verified, err := ds.VerifyDomain(t.Context(), "johndoe", domain.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if verified.Status != customdomain.StatusVerified || verified.VerifiedAt == nil {
+		t.Fatalf("expected verified domain, got %+v", verified)
+	}
+
+	username, err := ds.ResolveHost(t.Context(), "Links.Example.com:443")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if username != "johndoe" {
+		t.Fatalf("expected johndoe, got %v", username)
+	}
+
+	if _, err := ds.VerifyDomain(t.Context(), "janedoe", domain.ID); !errors.Is(err, custom_domain_repository.ErrDomainNotFound) {
+		t.Fatalf("expected other users not to verify the domain, got %v", err)
+	}
+}
+
+func TestDomainService_ReverifyDomains(t *testing.T) {
+	ds, resolver, dr := newTestDomainService(t)
+
+	domain, _ := ds.AddDomain(t.Context(), "johndoe", customdomain.CreateCustomDomain{Domain: "links.example.com"})
+	resolver.records[domain.Record.Name] = []string{domain.Record.Value}
+	ds.VerifyDomain(t.Context(), "johndoe", domain.ID)
+	ds.ResolveHost(t.Context(), "links.example.com")
+
+	dr.Domains[0].LastCheckedAt = time.Now().Add(-48 * time.Hour)
+	resolver.err = errors.New("i/o timeout")
+	if err := ds.ReverifyDomains(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dr.Domains[0].Status != customdomain.StatusVerified {
+		t.Fatalf("expected dns errors to keep the domain verified, got %v", dr.Domains[0].Status)
+	}
+
+	dr.Domains[0].LastCheckedAt = time.Now().Add(-48 * time.Hour)
+	resolver.err = nil
+	delete(resolver.records, domain.Record.Name)
+	if err := ds.ReverifyDomains(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if dr.Domains[0].Status != customdomain.StatusFailed {
+		t.Fatalf("expected domain to fail re-verification, got %v", dr.Domains[0].Status)
+	}
+
+	if _, err := ds.ResolveHost(t.Context(), "links.example.com"); !errors.Is(err, custom_domain_repository.ErrDomainNotFound) {
+		t.Fatalf("expected failed domain to stop resolving, got %v", err)
+	}
+}
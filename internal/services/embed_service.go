@@ -0,0 +1,410 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"golang.org/x/net/html"
+)
+
+const (
+	embedCacheTTL         = 24 * time.Hour
+	embedNegativeCacheTTL = time.Hour
+	embedMaxCacheTTL      = 7 * 24 * time.Hour
+	embedMaxBodySize      = 512 << 10
+	embedNotAvailable     = "none"
+	embedFetchTimeout     = 5 * time.Second
+
+	// Stored embeds are fetched again after embedRefreshAge, at most
+	// embedRefreshBatch links per pass.
+	embedRefreshAge   = 24 * time.Hour
+	embedRefreshBatch = 50
+)
+
+var (
+	ErrEmbedNotAvailable = errors.New("embed not available")
+	errPrivateAddress    = errors.New("address is not public")
+)
+
+// OEmbedProvider describes a trusted oEmbed endpoint. Only links hosted on
+// Hosts are embedded, and discovered endpoints must live on the same host as
+// Endpoint, since the returned HTML is rendered inline on profiles.
+type OEmbedProvider struct {
+	Name     string
+	Endpoint string
+	Hosts    []string
+	Schemes  []*regexp.Regexp
+}
+
+var (
+	YouTubeOEmbed = OEmbedProvider{
+		Name:     "YouTube",
+		Endpoint: "https://www.youtube.com/oembed",
+		Hosts:    []string{"youtube.com", "www.youtube.com", "m.youtube.com", "youtu.be", "music.youtube.com"},
+		Schemes: []*regexp.Regexp{
+			regexp.MustCompile(`^https?://(?:www\.|m\.|music\.)?youtube\.com/(?:watch|shorts/|live/|playlist)`),
+			regexp.MustCompile(`^https?://youtu\.be/[A-Za-z0-9_-]+`),
+		},
+	}
+	SpotifyOEmbed = OEmbedProvider{
+		Name:     "Spotify",
+		Endpoint: "https://open.spotify.com/oembed",
+		Hosts:    []string{"open.spotify.com"},
+		Schemes: []*regexp.Regexp{
+			regexp.MustCompile(`^https?://open\.spotify\.com/(?:intl-[a-z]+/)?(?:track|album|playlist|episode|show|artist)/[A-Za-z0-9]+`),
+		},
+	}
+	SoundCloudOEmbed = OEmbedProvider{
+		Name:     "SoundCloud",
+		Endpoint: "https://soundcloud.com/oembed",
+		Hosts:    []string{"soundcloud.com", "www.soundcloud.com", "m.soundcloud.com"},
+		Schemes: []*regexp.Regexp{
+			regexp.MustCompile(`^https?://(?:www\.|m\.)?soundcloud\.com/[^/]+/[^/]+`),
+		},
+	}
+	VimeoOEmbed = OEmbedProvider{
+		Name:     "Vimeo",
+		Endpoint: "https://vimeo.com/api/oembed.json",
+		Hosts:    []string{"vimeo.com", "www.vimeo.com", "player.vimeo.com"},
+		Schemes: []*regexp.Regexp{
+			regexp.MustCompile(`^https?://(?:www\.)?vimeo\.com/(?:channels/[^/]+/)?\d+`),
+			regexp.MustCompile(`^https?://player\.vimeo\.com/video/\d+`),
+		},
+	}
+
+	DefaultOEmbedProviders = []OEmbedProvider{YouTubeOEmbed, SpotifyOEmbed, SoundCloudOEmbed, VimeoOEmbed}
+)
+
+type oEmbedResponse struct {
+	Type         string  `json:"type"`
+	Version      string  `json:"version"`
+	Title        string  `json:"title"`
+	ProviderName string  `json:"provider_name"`
+	HTML         string  `json:"html"`
+	Width        flexInt `json:"width"`
+	Height       flexInt `json:"height"`
+	ThumbnailURL string  `json:"thumbnail_url"`
+	CacheAge     flexInt `json:"cache_age"`
+}
+
+// flexInt accepts numbers encoded either as JSON numbers or strings, as
+// providers are not consistent about it.
+type flexInt int
+
+func (f *flexInt) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "" || raw == "null" {
+		return nil
+	}
+	n, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil
+	}
+	*f = flexInt(n)
+	return nil
+}
+
+// EmbedService fetches the oEmbed metadata of links in the background and
+// stores it on them. Profiles only read the stored embeds, so serving a page
+// never waits on a provider.
+type EmbedService struct {
+	providers       []OEmbedProvider
+	client          *http.Client
+	linksRepository links_repository.LinksRepository
+	cacheRepository cache_repository.CacheRepository
+}
+
+func NewEmbedService(linksRepository links_repository.LinksRepository, cacheRepository cache_repository.CacheRepository, providers ...OEmbedProvider) *EmbedService {
+	return &EmbedService{
+		providers:       providers,
+		client:          newEmbedClient(),
+		linksRepository: linksRepository,
+		cacheRepository: cacheRepository,
+	}
+}
+
+// newEmbedClient does not follow redirects and only connects to public
+// addresses, since the urls it fetches come from users.
+func newEmbedClient() *http.Client {
+	dialer := &net.Dialer{Timeout: embedFetchTimeout, Control: denyPrivateAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   embedFetchTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func denyPrivateAddress(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("%w: %s", errPrivateAddress, host)
+	}
+	return nil
+}
+
+// RefreshEmbeds fetches the embeds of links that were created, or whose url
+// changed, since the last pass and of links whose embed is older than
+// embedRefreshAge. A failed fetch keeps the stored embed until the next
+// refresh.
+func (es *EmbedService) RefreshEmbeds(ctx context.Context) error {
+	stale, err := es.linksRepository.ListStaleEmbeds(ctx, time.Now().Add(-embedRefreshAge), embedRefreshBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, link := range stale {
+		embed, err := es.GetEmbed(ctx, link.Url)
+		if err != nil && !errors.Is(err, ErrEmbedNotAvailable) {
+			log.Printf("could not fetch embed for link %d: %v", link.ID, err)
+			embed = link.Embed
+		}
+
+		if err := es.linksRepository.UpdateLinkEmbed(ctx, link.ID, link.Url, embed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunEmbedRefresh calls RefreshEmbeds right away and then on every tick until
+// ctx is done.
+func (es *EmbedService) RunEmbedRefresh(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := es.RefreshEmbeds(ctx); err != nil {
+			log.Printf("could not refresh embeds: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (es *EmbedService) GetEmbed(ctx context.Context, rawURL string) (*links.Embed, error) {
+	key := fmt.Sprintf("embed:%s", rawURL)
+	if cached, err := es.cacheRepository.Get(ctx, key); err == nil && cached != "" {
+		if cached == embedNotAvailable {
+			return nil, ErrEmbedNotAvailable
+		}
+		var embed links.Embed
+		if err := json.Unmarshal([]byte(cached), &embed); err == nil {
+			return &embed, nil
+		}
+	}
+
+	embed, ttl, err := es.fetchEmbed(ctx, rawURL)
+	if err != nil {
+		if errors.Is(err, ErrEmbedNotAvailable) {
+			es.cacheRepository.Set(ctx, key, embedNotAvailable, embedNegativeCacheTTL)
+		}
+		return nil, err
+	}
+
+	if encoded, err := json.Marshal(embed); err == nil {
+		es.cacheRepository.Set(ctx, key, string(encoded), ttl)
+	}
+
+	return embed, nil
+}
+
+func (es *EmbedService) fetchEmbed(ctx context.Context, rawURL string) (*links.Embed, time.Duration, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, 0, ErrEmbedNotAvailable
+	}
+
+	provider, ok := es.providerForHost(u.Host)
+	if !ok {
+		return nil, 0, ErrEmbedNotAvailable
+	}
+
+	endpoint := ""
+	if provider.matches(rawURL) {
+		endpoint = provider.endpointFor(rawURL)
+	} else {
+		endpoint, err = es.discover(ctx, rawURL)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !provider.trusts(endpoint) {
+			return nil, 0, ErrEmbedNotAvailable
+		}
+	}
+
+	resp, err := es.get(ctx, endpoint)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden || (resp.StatusCode >= 300 && resp.StatusCode < 400) {
+		return nil, 0, ErrEmbedNotAvailable
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("oembed provider %s returned status %d", provider.Name, resp.StatusCode)
+	}
+
+	var data oEmbedResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, embedMaxBodySize)).Decode(&data); err != nil {
+		return nil, 0, err
+	}
+
+	if (data.Type != "video" && data.Type != "rich") || data.HTML == "" {
+		return nil, 0, ErrEmbedNotAvailable
+	}
+
+	ttl := embedCacheTTL
+	if age := time.Duration(data.CacheAge) * time.Second; age > time.Hour {
+		ttl = min(age, embedMaxCacheTTL)
+	}
+
+	name := data.ProviderName
+	if name == "" {
+		name = provider.Name
+	}
+
+	return &links.Embed{
+		Type:         data.Type,
+		Provider:     name,
+		Title:        data.Title,
+		HTML:         data.HTML,
+		Width:        int(data.Width),
+		Height:       int(data.Height),
+		ThumbnailURL: data.ThumbnailURL,
+	}, ttl, nil
+}
+
+// discover looks for <link rel="alternate" type="application/json+oembed">
+// in the page head.
+func (es *EmbedService) discover(ctx context.Context, pageURL string) (string, error) {
+	resp, err := es.get(ctx, pageURL)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", ErrEmbedNotAvailable
+	}
+
+	base := resp.Request.URL
+	tokenizer := html.NewTokenizer(io.LimitReader(resp.Body, embedMaxBodySize))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return "", ErrEmbedNotAvailable
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			if token.Data == "body" {
+				return "", ErrEmbedNotAvailable
+			}
+			if token.Data != "link" {
+				continue
+			}
+
+			var rel, typ, href string
+			for _, attr := range token.Attr {
+				switch strings.ToLower(attr.Key) {
+				case "rel":
+					rel = strings.ToLower(attr.Val)
+				case "type":
+					typ = strings.ToLower(attr.Val)
+				case "href":
+					href = attr.Val
+				}
+			}
+
+			if rel == "alternate" && typ == "application/json+oembed" && href != "" {
+				endpoint, err := base.Parse(href)
+				if err != nil {
+					return "", ErrEmbedNotAvailable
+				}
+				return endpoint.String(), nil
+			}
+		}
+	}
+}
+
+func (es *EmbedService) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "LinkerBot/1.0 (+oembed)")
+	return es.client.Do(req)
+}
+
+func (es *EmbedService) providerForHost(host string) (OEmbedProvider, bool) {
+	host = strings.ToLower(host)
+	for _, p := range es.providers {
+		for _, h := range p.Hosts {
+			if h == host {
+				return p, true
+			}
+		}
+	}
+	return OEmbedProvider{}, false
+}
+
+func (p OEmbedProvider) matches(rawURL string) bool {
+	for _, scheme := range p.Schemes {
+		if scheme.MatchString(rawURL) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p OEmbedProvider) endpointFor(rawURL string) string {
+	query := url.Values{}
+	query.Set("url", rawURL)
+	query.Set("format", "json")
+
+	separator := "?"
+	if strings.Contains(p.Endpoint, "?") {
+		separator = "&"
+	}
+	return p.Endpoint + separator + query.Encode()
+}
+
+func (p OEmbedProvider) trusts(endpoint string) bool {
+	discovered, err := url.Parse(endpoint)
+	if err != nil {
+		return false
+	}
+	expected, err := url.Parse(p.Endpoint)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(discovered.Host, expected.Host)
+}
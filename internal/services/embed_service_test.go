@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+)
+
+func newTestOEmbedServer(t *testing.T, calls *int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if r.URL.Query().Get("format") != "json" {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		if r.URL.Query().Get("url") == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"type":"video","version":"1.0","title":"My video","provider_name":"TestTube","html":"<iframe src=\"https://player.test/1\"></iframe>","width":"480","height":270,"thumbnail_url":"https://img.test/1.jpg"}`)
+	})
+	mux.HandleFunc("/watch/discoverable", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!doctype html><html><head><title>x</title>
+<link rel="alternate" type="application/json+oembed" href="/oembed?url=discoverable&amp;format=json">
+</head><body></body></html>`)
+	})
+	mux.HandleFunc("/watch/plain", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>no embed</title></head><body></body></html>`)
+	})
+	mux.HandleFunc("/watch/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/watch/discoverable", http.StatusFound)
+	})
+	mux.HandleFunc("/watch/untrusted", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><link rel="alternate" type="application/json+oembed" href="https://evil.test/oembed"></head></html>`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestEmbedService(t *testing.T, server *httptest.Server) (*EmbedService, *cache_repository.InMemoryCacheRepository) {
+	es, cr := newTestEmbedServiceWithLinks(server, links_repository.NewInMemoryLinksRepository())
+	return es, cr
+}
+
+// newTestEmbedServiceWithLinks lets the client reach the test server, which
+// listens on a loopback address.
+func newTestEmbedServiceWithLinks(server *httptest.Server, lr links_repository.LinksRepository) (*EmbedService, *cache_repository.InMemoryCacheRepository) {
+	u, _ := url.Parse(server.URL)
+	cr := cache_repository.NewInMemoryCacheRepository()
+	es := NewEmbedService(lr, cr, OEmbedProvider{
+		Name:     "TestTube",
+		Endpoint: server.URL + "/oembed",
+		Hosts:    []string{u.Host},
+		Schemes:  []*regexp.Regexp{regexp.MustCompile(`^` + regexp.QuoteMeta(server.URL) + `/video/\d+$`)},
+	})
+	es.client.Transport = server.Client().Transport
+	return es, cr
+}
+
+func TestEmbedService_GetEmbed(t *testing.T) {
+	calls := 0
+	server := newTestOEmbedServer(t, &calls)
+	es, _ := newTestEmbedService(t, server)
+
+	embed, err := es.GetEmbed(t.Context(), server.URL+"/video/1")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if embed.Provider != "TestTube" || embed.Type != "video" {
+		t.Fatalf("expected TestTube video, got %+v", embed)
+	}
+
+	if embed.Width != 480 || embed.Height != 270 {
+		t.Fatalf("expected 480x270, got %dx%d", embed.Width, embed.Height)
+	}
+
+	if _, err := es.GetEmbed(t.Context(), server.URL+"/video/1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected provider to be called once, got %d", calls)
+	}
+}
+
+func TestEmbedService_GetEmbed_Discovery(t *testing.T) {
+	calls := 0
+	server := newTestOEmbedServer(t, &calls)
+	es, _ := newTestEmbedService(t, server)
+
+	embed, err := es.GetEmbed(t.Context(), server.URL+"/watch/discoverable")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if embed.Title != "My video" {
+		t.Fatalf("expected title 'My video', got %v", embed.Title)
+	}
+}
+
+func TestEmbedService_GetEmbed_NotAvailable(t *testing.T) {
+	calls := 0
+	server := newTestOEmbedServer(t, &calls)
+	es, cr := newTestEmbedService(t, server)
+
+	tests := []string{
+		server.URL + "/watch/plain",
+		server.URL + "/watch/untrusted",
+		server.URL + "/watch/redirect",
+		"https://example.com/video/1",
+	}
+
+	for _, rawURL := range tests {
+		if _, err := es.GetEmbed(t.Context(), rawURL); !errors.Is(err, ErrEmbedNotAvailable) {
+			t.Errorf("expected ErrEmbedNotAvailable for %s, got %v", rawURL, err)
+		}
+	}
+
+	cached, _ := cr.Get(t.Context(), "embed:"+server.URL+"/watch/plain")
+	if cached != embedNotAvailable {
+		t.Fatalf("expected negative result to be cached, got %q", cached)
+	}
+
+	if calls != 0 {
+		t.Fatalf("expected provider not to be called, got %d", calls)
+	}
+}
+
+func TestEmbedService_RejectsPrivateAddresses(t *testing.T) {
+	calls := 0
+	server := newTestOEmbedServer(t, &calls)
+	es, _ := newTestEmbedService(t, server)
+	es.client = newEmbedClient()
+
+	if _, err := es.GetEmbed(t.Context(), server.URL+"/video/1"); !errors.Is(err, errPrivateAddress) {
+		t.Fatalf("expected errPrivateAddress, got %v", err)
+	}
+	if calls != 0 {
+		t.Fatalf("expected provider not to be called, got %d", calls)
+	}
+}
+
+func TestEmbedService_RefreshEmbeds(t *testing.T) {
+	calls := 0
+	server := newTestOEmbedServer(t, &calls)
+	lr := links_repository.NewInMemoryLinksRepository()
+	lr.Links = []links_repository.Link{
+		{ID: 1, Url: server.URL + "/video/1"},
+		{ID: 2, Url: "https://example.com"},
+		{ID: 3, Url: "https://github.com/johndoe", Platform: "github"},
+	}
+	es, _ := newTestEmbedServiceWithLinks(server, lr)
+
+	if err := es.RefreshEmbeds(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if lr.Links[0].Embed == nil {
+		t.Fatalf("expected embed on first link")
+	}
+	if lr.Links[1].Embed != nil || lr.Links[1].EmbedFetchedAt.IsZero() {
+		t.Fatalf("expected second link to be fetched without an embed, got %+v", lr.Links[1])
+	}
+	if !lr.Links[2].EmbedFetchedAt.IsZero() {
+		t.Fatalf("expected social links to be skipped")
+	}
+
+	if err := es.RefreshEmbeds(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected fresh embeds not to be fetched again, got %d calls", calls)
+	}
+}
@@ -1,66 +1,254 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
-	"log"
-	"net/url"
+	"path"
+	"strings"
 	"time"
 
-	"github.com/minio/minio-go/v7"
-	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/theNixagen/linker/internal/domain/imaging"
+	"github.com/theNixagen/linker/internal/repositories/storage_repository"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// resizedPrefix holds the copies of public images scaled down to the
+	// widths asked for by clients.
+	resizedPrefix = "resized/"
+
+	DefaultMaxUploadSize = 10 << 20
+	DefaultStorageQuota  = 100 << 20
+)
+
+var (
+	ErrUploadTooLarge = errors.New("upload is too large")
+	ErrQuotaExceeded  = errors.New("storage quota exceeded")
+	ErrNotResizable   = errors.New("resized images cannot be resized again")
 )
 
 type FileService struct {
-	BucketName  string
-	MinioClient *minio.Client
+	Storage storage_repository.ObjectStorage
+	// MaxUploadSize bounds each upload, which is read into memory.
+	MaxUploadSize int64
+	// Quota bounds what each account stores over all of its pages: their
+	// media libraries with the pictures made from them, their pending
+	// uploads and their quarantined files.
+	Quota int64
 }
 
-func NewFileService(bucketName, minioUrl, minioUser, minioPasswd string) *FileService {
-	minioClient, err := minio.New(minioUrl, &minio.Options{
-		Creds: credentials.NewStaticV4(minioUser, minioPasswd, ""),
-	})
-	if err != nil {
-		fmt.Println(err)
-		log.Fatalf("Failed to initialize minio client: %v", err)
+func NewFileService(storage storage_repository.ObjectStorage, maxUploadSize, quota int64) *FileService {
+	if maxUploadSize <= 0 {
+		maxUploadSize = DefaultMaxUploadSize
+	}
+	if quota <= 0 {
+		quota = DefaultStorageQuota
 	}
+
 	return &FileService{
-		BucketName:  bucketName,
-		MinioClient: minioClient,
+		Storage:       storage,
+		MaxUploadSize: maxUploadSize,
+		Quota:         quota,
+	}
+}
+
+func (fs *FileService) PutObject(ctx context.Context, key string, file io.Reader, size int64, contentType string) error {
+	return fs.Storage.Put(ctx, key, file, size, contentType)
+}
+
+// readUpload reads an upload into memory, refusing anything larger than
+// MaxUploadSize.
+func (fs *FileService) readUpload(file io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(file, fs.MaxUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > fs.MaxUploadSize {
+		return nil, ErrUploadTooLarge
+	}
+	return data, nil
+}
+
+// StoredSize returns the bytes stored under prefix.
+func (fs *FileService) StoredSize(ctx context.Context, prefix string) (int64, error) {
+	objects, err := fs.Storage.List(ctx, prefix)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	for _, object := range objects {
+		size += object.Size
+	}
+	return size, nil
+}
+
+// encodeVariants encodes one JPEG per variant of img.
+func encodeVariants(img image.Image, variants []imaging.Variant) ([][]byte, error) {
+	encoded := make([][]byte, 0, len(variants))
+	for _, v := range variants {
+		data, err := imaging.EncodeJPEG(imaging.Fill(img, v))
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, data)
+	}
+	return encoded, nil
+}
+
+// PutImage stores the variants made by encodeVariants under base. It returns
+// the key of the largest variant, which is the one pages reference.
+func (fs *FileService) PutImage(ctx context.Context, base string, encoded [][]byte, variants []imaging.Variant) (string, error) {
+	var key string
+	for i, v := range variants {
+		key = imaging.VariantKey(base, v)
+		if err := fs.PutObject(ctx, key, bytes.NewReader(encoded[i]), int64(len(encoded[i])), imaging.ContentType); err != nil {
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+// RemoveImage deletes every variant of the image stored at key, or key
+// alone when it predates variants.
+func (fs *FileService) RemoveImage(ctx context.Context, key string, variants []imaging.Variant) error {
+	keys := imaging.VariantKeys(key, variants)
+	if keys == nil {
+		return fs.RemoveObject(ctx, key)
+	}
+
+	for _, variantKey := range keys {
+		if err := fs.RemoveObject(ctx, variantKey); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (fs *FileService) CreateBucketIfNotExists(ctx context.Context) bool {
-	ok, err := fs.MinioClient.BucketExists(ctx, fs.BucketName)
+func (fs *FileService) RemoveObject(ctx context.Context, key string) error {
+	return fs.Storage.Delete(ctx, key)
+}
+
+func (fs *FileService) GetObject(ctx context.Context, key string) (io.ReadCloser, storage_repository.ObjectInfo, error) {
+	return fs.Storage.Get(ctx, key)
+}
+
+// GetImage downloads and decodes an image stored in the bucket, with the
+// size and pixel limits of uploads.
+func (fs *FileService) GetImage(ctx context.Context, key string) (image.Image, error) {
+	data, err := fs.ReadObject(ctx, key)
 	if err != nil {
-		fmt.Println(err)
-		log.Fatalf("Failed to acess minio: %v", err)
+		return nil, err
 	}
-	if !ok {
-		if err := fs.MinioClient.MakeBucket(ctx, fs.BucketName, minio.MakeBucketOptions{}); err != nil {
-			fmt.Println(err)
-			log.Fatalf("Failed to create bucket: %v", err)
+	return imaging.Decode(data)
+}
+
+func (fs *FileService) ObjectExists(ctx context.Context, key string) (bool, error) {
+	_, err := fs.Storage.Stat(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage_repository.ErrObjectNotFound) {
+			return false, nil
 		}
-		return true
+		return false, err
 	}
-	return false
+	return true, nil
 }
 
-func (fs *FileService) PutObject(ctx context.Context, fileName string, file io.Reader, size int64) (minio.UploadInfo, error) {
-	info, err := fs.MinioClient.PutObject(ctx, fs.BucketName, fileName, file, size, minio.PutObjectOptions{})
+// RemoveObjectsWithPrefix deletes every object under prefix except keep.
+func (fs *FileService) RemoveObjectsWithPrefix(ctx context.Context, prefix, keep string) error {
+	objects, err := fs.Storage.List(ctx, prefix)
 	if err != nil {
-		return minio.UploadInfo{}, err
+		return err
+	}
+
+	for _, object := range objects {
+		if object.Key == keep {
+			continue
+		}
+		if err := fs.RemoveObject(ctx, object.Key); err != nil {
+			return err
+		}
 	}
-	return info, nil
+	return nil
 }
 
-func (fs *FileService) GetSignedURL(ctx context.Context, key, bucket string) (*url.URL, error) {
-	url, err := fs.MinioClient.PresignedGetObject(ctx, bucket, key, time.Minute*15, url.Values{})
+// PresignedPost returns a POST policy that lets a client upload exactly size
+// bytes of contentType to key until expires.
+func (fs *FileService) PresignedPost(ctx context.Context, key, contentType string, size int64, expires time.Time) (string, map[string]string, error) {
+	return fs.Storage.PresignPost(ctx, key, contentType, size, expires)
+}
+
+// ReadObject reads the object at key into memory, refusing objects larger
+// than MaxUploadSize.
+func (fs *FileService) ReadObject(ctx context.Context, key string) ([]byte, error) {
+	object, _, err := fs.GetObject(ctx, key)
 	if err != nil {
-		return nil, errors.New("failed to generate signed url")
+		return nil, err
 	}
+	defer object.Close()
 
-	return url, nil
+	return fs.readUpload(object)
+}
+
+// IsPublicKey reports whether key may be served to anyone, which excludes
+// uploads that were not verified yet and quarantined files. Keys from before
+// content addressing were plain, guessable file names and are not served
+// either.
+func IsPublicKey(key string) bool {
+	return strings.Contains(key, "/") && !strings.HasPrefix(key, uploadPrefix) && !strings.HasPrefix(key, quarantinePrefix)
+}
+
+// ResizeImage returns the image at key scaled down to width and encoded as
+// JPEG. Each width is stored the first time it is asked for and served from
+// storage afterwards, so the original is decoded only once. Resized copies
+// are not resized again, which would nest keys without limit.
+func (fs *FileService) ResizeImage(ctx context.Context, key string, width int) (io.ReadCloser, storage_repository.ObjectInfo, error) {
+	if strings.HasPrefix(key, resizedPrefix) {
+		return nil, storage_repository.ObjectInfo{}, ErrNotResizable
+	}
+
+	resized := resizedKey(key, width)
+	object, info, err := fs.GetObject(ctx, resized)
+	if !errors.Is(err, storage_repository.ErrObjectNotFound) {
+		return object, info, err
+	}
+
+	data, err := fs.ReadObject(ctx, key)
+	if err != nil {
+		return nil, storage_repository.ObjectInfo{}, err
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, storage_repository.ObjectInfo{}, err
+	}
+
+	encoded, err := imaging.EncodeJPEG(imaging.FitWidth(img, width))
+	if err != nil {
+		return nil, storage_repository.ObjectInfo{}, err
+	}
+	if err := fs.PutObject(ctx, resized, bytes.NewReader(encoded), int64(len(encoded)), imaging.ContentType); err != nil {
+		return nil, storage_repository.ObjectInfo{}, err
+	}
+	return fs.GetObject(ctx, resized)
+}
+
+// resizedKey is where the copy of key scaled down to width is stored.
+func resizedKey(key string, width int) string {
+	return fmt.Sprintf("%s%s/w%d%s", resizedPrefix, key, width, imaging.Extension)
+}
+
+// resizedSource returns the key a resized copy was made from.
+func resizedSource(key string) (string, bool) {
+	if !strings.HasPrefix(key, resizedPrefix) {
+		return "", false
+	}
+	return path.Dir(strings.TrimPrefix(key, resizedPrefix)), true
 }
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"io"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/imaging"
+	"github.com/theNixagen/linker/internal/repositories/storage_repository"
+)
+
+func newTestStorage(t *testing.T) *storage_repository.InMemoryObjectStorage {
+	t.Helper()
+	return storage_repository.NewInMemoryObjectStorage(storage_repository.NewURLSigner("test_storage", "http://localhost/storage"))
+}
+
+func TestFileService_ResizeImage(t *testing.T) {
+	storage := newTestStorage(t)
+	fs := NewFileService(storage, 0, 0)
+
+	data := testPNG(t, color.White)
+	if err := fs.PutObject(t.Context(), "pages/1/media/abc.jpg", bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	object, info, err := fs.ResizeImage(t.Context(), "pages/1/media/abc.jpg", 64)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resized, _ := io.ReadAll(object)
+	object.Close()
+
+	img, err := imaging.Decode(resized)
+	if err != nil || img.Bounds().Dx() != 64 || info.ContentType != imaging.ContentType {
+		t.Fatalf("expected a 64 pixel wide JPEG, got %v (%v)", info, err)
+	}
+	if keys := objectKeys(t, storage, resizedPrefix); len(keys) != 1 || keys[0] != resizedKey("pages/1/media/abc.jpg", 64) {
+		t.Fatalf("expected the resized copy to be stored, got %v", keys)
+	}
+
+	// The stored copy is served even once the original cannot be read.
+	storage.Delete(t.Context(), "pages/1/media/abc.jpg")
+	object, _, err = fs.ResizeImage(t.Context(), "pages/1/media/abc.jpg", 64)
+	if err != nil {
+		t.Fatalf("expected the stored copy, got %v", err)
+	}
+	object.Close()
+
+	if _, _, err := fs.ResizeImage(t.Context(), resizedKey("pages/1/media/abc.jpg", 64), 64); !errors.Is(err, ErrNotResizable) {
+		t.Fatalf("expected ErrNotResizable, got %v", err)
+	}
+	if keys := objectKeys(t, storage, resizedPrefix); len(keys) != 1 {
+		t.Fatalf("expected no nested copy, got %v", keys)
+	}
+}
+
+func TestIsPublicKey(t *testing.T) {
+	tests := map[string]bool{
+		"pages/1/avatar/abc/lg.jpg": true,
+		"share/johndoe/card.png":    true,
+		"legacy.png":                false,
+		"uploads/1/pending":         false,
+		"quarantine/pages/1/x.jpg":  false,
+		"":                          false,
+	}
+
+	for key, expected := range tests {
+		if got := IsPublicKey(key); got != expected {
+			t.Errorf("IsPublicKey(%q) = %v, expected %v", key, got, expected)
+		}
+	}
+}
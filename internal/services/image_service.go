@@ -0,0 +1,964 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/imaging"
+	"github.com/theNixagen/linker/internal/domain/media"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/domain/upload"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+	"github.com/theNixagen/linker/internal/repositories/upload_repository"
+)
+
+const (
+	ImageKindAvatar     = "avatar"
+	ImageKindBanner     = "banner"
+	ImageKindBackground = "background"
+	ImageKindMedia      = "media"
+
+	// quarantinePrefix holds uploads flagged by the content scanner, kept
+	// for review and never served.
+	quarantinePrefix = "quarantine/"
+)
+
+var (
+	ErrMediaInUse = errors.New("media is in use by the page, a link, a published profile or a revision")
+)
+
+// ImageService stores the pictures of a page under content-addressed keys
+// and removes the ones that were replaced. Every upload is kept in the media
+// library of the page, from which pictures can be picked again.
+type ImageService struct {
+	PageRepository             page_repository.PageRepository
+	PublishedProfileRepository published_profile_repository.PublishedProfileRepository
+	RevisionRepository         revision_repository.RevisionRepository
+	ThemeRepository            theme_repository.ThemeRepository
+	LinksRepository            links_repository.LinksRepository
+	MediaRepository            media_repository.MediaRepository
+	UploadRepository           upload_repository.UploadRepository
+	FileService                *FileService
+	Scanner                    ContentScanner
+
+	locks      accountLocks
+	processing sync.WaitGroup
+}
+
+func NewImageService(pageRepository page_repository.PageRepository, publishedProfileRepository published_profile_repository.PublishedProfileRepository, revisionRepository revision_repository.RevisionRepository, themeRepository theme_repository.ThemeRepository, linksRepository links_repository.LinksRepository, mediaRepository media_repository.MediaRepository, uploadRepository upload_repository.UploadRepository, fileService *FileService, scanner ContentScanner) *ImageService {
+	return &ImageService{
+		PageRepository:             pageRepository,
+		PublishedProfileRepository: publishedProfileRepository,
+		RevisionRepository:         revisionRepository,
+		ThemeRepository:            themeRepository,
+		LinksRepository:            linksRepository,
+		MediaRepository:            mediaRepository,
+		UploadRepository:           uploadRepository,
+		FileService:                fileService,
+		Scanner:                    scanner,
+	}
+}
+
+func (is *ImageService) UploadProfilePhoto(ctx context.Context, handle string, file io.Reader) error {
+	return is.upload(ctx, handle, ImageKindAvatar, file)
+}
+
+func (is *ImageService) UploadBanner(ctx context.Context, handle string, file io.Reader) error {
+	return is.upload(ctx, handle, ImageKindBanner, file)
+}
+
+func (is *ImageService) upload(ctx context.Context, handle, kind string, file io.Reader) error {
+	data, err := is.FileService.readUpload(file)
+	if err != nil {
+		return err
+	}
+	contentType, err := imaging.Sniff(data)
+	if err != nil {
+		return err
+	}
+
+	p, err := is.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+	// Only an early check against the raw size: Attach checks what the
+	// upload will store.
+	if err := is.checkQuota(ctx, p.ID, int64(len(data))); err != nil {
+		return err
+	}
+
+	// The upload is stored like a direct one, so it is not lost when the
+	// service stops before it is processed.
+	key := uploadKey(p.ID)
+	if err := is.FileService.PutObject(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return err
+	}
+	pending, err := is.UploadRepository.CreateUpload(ctx, upload_repository.Upload{
+		PageID:      p.ID,
+		Kind:        kind,
+		ObjectKey:   key,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		ExpiresAt:   time.Now().Add(upload.Expiry),
+	})
+	if err != nil {
+		is.FileService.RemoveObject(ctx, key)
+		return err
+	}
+
+	if err := is.Attach(ctx, p.Handle, pending, data); err != nil {
+		is.discardUpload(ctx, pending)
+		return err
+	}
+	return nil
+}
+
+// Attach validates data, the content of the stored upload pending, reserves
+// what it will store and sets the avatar or banner state of the page to
+// pending. It returns ErrQuotaExceeded when the account has no room for it.
+// The upload is then scanned, added to the media library and set as the
+// picture in the background; the state ends up ready, rejected when the
+// scanner flags the file or failed when it could not be processed. The
+// upload is kept until then, so Recover can process it again if the service
+// stops first.
+func (is *ImageService) Attach(ctx context.Context, handle string, pending upload_repository.Upload, data []byte) error {
+	setState, err := is.stateSetter(pending.Kind)
+	if err != nil {
+		return err
+	}
+
+	p, err := is.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return err
+	}
+	encoded, err := encodeUpload(img, pending.Kind)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := is.lock(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	// The upload already reserves its declared size, or what it would store
+	// when it is processed again.
+	reserved := pending.ReservedSize
+	if pending.ProcessingSince.IsZero() {
+		reserved = pending.Size
+	}
+	if err := is.checkQuota(ctx, p.ID, encoded.size()-reserved); err != nil {
+		return err
+	}
+	if err := is.UploadRepository.StartProcessing(ctx, pending.ID, encoded.size()); err != nil {
+		return err
+	}
+	if err := setState(ctx, p.Handle, page.PicturePending); err != nil {
+		return err
+	}
+
+	is.processing.Add(1)
+	go func() {
+		defer is.processing.Done()
+		is.process(context.WithoutCancel(ctx), p, pending, data, img, encoded, setState)
+	}()
+	return nil
+}
+
+// process runs the scan and the swap of an upload accepted by Attach. The
+// scan runs first, so a slow scanner does not hold up the other uploads of the
+// account. The files are then stored one upload at a time: the room reserved
+// by Attach is released only once the lock is held, so the quota checked
+// before each file is stored cannot be taken by another upload in between.
+func (is *ImageService) process(ctx context.Context, p page_repository.Page, pending upload_repository.Upload, data []byte, img image.Image, encoded encodedUpload, setState func(ctx context.Context, handle, state string) error) {
+	kind := pending.Kind
+	err := is.scan(ctx, p.ID, kind, data)
+	if err == nil {
+		var unlock func()
+		unlock, err = is.lock(ctx, p.ID)
+		if err == nil {
+			defer unlock()
+			err = is.UploadRepository.ReleaseReservation(ctx, pending.ID)
+		}
+	}
+	if err == nil {
+		var m media_repository.Media
+		m, err = is.storeEncodedMedia(ctx, p.ID, img, encoded.media)
+		if err == nil {
+			err = is.attach(ctx, p.Handle, kind, m, encoded.picture)
+		}
+	}
+
+	state := page.PictureReady
+	switch {
+	case errors.Is(err, ErrContentRejected):
+		state = page.PictureRejected
+	case err != nil:
+		state = page.PictureFailed
+		log.Printf("could not attach the %s of %s: %v", kind, p.Handle, err)
+	}
+	if stateErr := setState(ctx, p.Handle, state); stateErr != nil {
+		log.Printf("could not update the %s state of %s: %v", kind, p.Handle, stateErr)
+		return
+	}
+	is.discardUpload(ctx, pending)
+}
+
+// Recover processes again the uploads whose processing started more than
+// staleAfter ago, which were left behind by a service that stopped, and sets
+// the picture states still pending without an upload to failed.
+func (is *ImageService) Recover(ctx context.Context, staleAfter time.Duration) error {
+	stale, err := is.UploadRepository.ListStaleUploads(ctx, time.Now().Add(-staleAfter))
+	if err != nil {
+		return err
+	}
+
+	for _, pending := range stale {
+		if err := is.requeue(ctx, pending); err != nil {
+			log.Printf("could not process upload %d again: %v", pending.ID, err)
+			is.discardUpload(ctx, pending)
+		}
+	}
+	return is.UploadRepository.FailOrphanedPictures(ctx)
+}
+
+func (is *ImageService) requeue(ctx context.Context, pending upload_repository.Upload) error {
+	p, err := is.PageRepository.GetPageByID(ctx, pending.PageID)
+	if err != nil {
+		return err
+	}
+	data, err := is.FileService.ReadObject(ctx, pending.ObjectKey)
+	if err != nil {
+		return err
+	}
+	return is.Attach(ctx, p.Handle, pending, data)
+}
+
+// RunRecovery calls Recover right away and then on every tick until ctx is
+// done. Uploads count as stale after a tick, which is far longer than one
+// takes to process.
+func (is *ImageService) RunRecovery(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := is.Recover(ctx, every); err != nil {
+			log.Printf("could not recover pending uploads: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// discardUpload removes the object of an upload and then its row, which is
+// kept when the object could not be removed so it is tried again later.
+func (is *ImageService) discardUpload(ctx context.Context, pending upload_repository.Upload) {
+	if err := is.FileService.RemoveObject(ctx, pending.ObjectKey); err != nil {
+		log.Printf("could not remove upload %s: %v", pending.ObjectKey, err)
+		return
+	}
+	if err := is.UploadRepository.DeleteUpload(ctx, pending.ID); err != nil {
+		log.Printf("could not delete upload %d: %v", pending.ID, err)
+	}
+}
+
+// Wait blocks until every upload accepted by Attach is processed.
+func (is *ImageService) Wait() {
+	is.processing.Wait()
+}
+
+// AttachMedia sets an image of the media library as the avatar or banner of
+// the page, without uploading it again. Library images were already scanned,
+// so the picture state goes back to ready.
+func (is *ImageService) AttachMedia(ctx context.Context, handle, kind string, mediaID int32) error {
+	setState, err := is.stateSetter(kind)
+	if err != nil {
+		return err
+	}
+
+	p, err := is.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := is.lock(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m, err := is.MediaRepository.GetMedia(ctx, p.ID, mediaID)
+	if err != nil {
+		return err
+	}
+
+	data, err := is.FileService.ReadObject(ctx, m.ObjectKey)
+	if err != nil {
+		return err
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return err
+	}
+	encoded, err := encodeVariants(img, pictureVariants(kind))
+	if err != nil {
+		return err
+	}
+
+	if err := is.attach(ctx, p.Handle, kind, m, encoded); err != nil {
+		return err
+	}
+	return setState(ctx, p.Handle, page.PictureReady)
+}
+
+// stateSetter returns the function that updates the state of the picture of
+// kind.
+func (is *ImageService) stateSetter(kind string) (func(ctx context.Context, handle, state string) error, error) {
+	switch kind {
+	case ImageKindAvatar:
+		return is.PageRepository.UpdateProfilePhotoState, nil
+	case ImageKindBanner:
+		return is.PageRepository.UpdateBannerPhotoState, nil
+	default:
+		return nil, fmt.Errorf("unknown image kind %q", kind)
+	}
+}
+
+// attach sets the variants of m, encoded by encodeVariants, as the picture of
+// kind.
+func (is *ImageService) attach(ctx context.Context, handle, kind string, m media_repository.Media, encoded [][]byte) error {
+	switch kind {
+	case ImageKindAvatar:
+		return is.replace(ctx, handle, kind, m, encoded, imaging.AvatarVariants,
+			func(p page_repository.Page) string { return p.ProfilePicture },
+			is.PageRepository.UpdateProfilePhoto)
+	case ImageKindBanner:
+		return is.replace(ctx, handle, kind, m, encoded, imaging.BannerVariants,
+			func(p page_repository.Page) string { return p.BannerPicture },
+			is.PageRepository.UpdateBannerPhoto)
+	default:
+		return fmt.Errorf("unknown image kind %q", kind)
+	}
+}
+
+// scan returns ErrContentRejected when the scanner flags data, after copying
+// it under the quarantine prefix for review. The copy counts towards the
+// quota and is skipped when the account has no room left for it.
+func (is *ImageService) scan(ctx context.Context, pageID int32, kind string, data []byte) error {
+	result, err := is.Scanner.Scan(ctx, data)
+	if err != nil {
+		return err
+	}
+	if result.Clean {
+		return nil
+	}
+
+	key := quarantinePrefix + contentKey(pageID, kind, data)
+	if err := is.checkQuota(ctx, pageID, int64(len(data))); err != nil {
+		log.Printf("could not quarantine %s: %v", key, err)
+	} else if err := is.FileService.PutObject(ctx, key, bytes.NewReader(data), int64(len(data)), http.DetectContentType(data)); err != nil {
+		log.Printf("could not quarantine %s: %v", key, err)
+	}
+	log.Printf("content scanner flagged %s: %s", key, result.Signature)
+	return ErrContentRejected
+}
+
+// PutBackground stores a theme background in the media library at its
+// original size, with the metadata stripped, and returns its key. The account
+// stays locked while the file is scanned and stored.
+func (is *ImageService) PutBackground(ctx context.Context, pageID int32, file io.Reader) (string, error) {
+	data, err := is.FileService.readUpload(file)
+	if err != nil {
+		return "", err
+	}
+
+	unlock, err := is.lock(ctx, pageID)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if err := is.scan(ctx, pageID, ImageKindBackground, data); err != nil {
+		return "", err
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return "", err
+	}
+	m, err := is.storeMedia(ctx, pageID, img)
+	if err != nil {
+		return "", err
+	}
+	return m.ObjectKey, nil
+}
+
+// SetBackground points the theme of the page at key through set and then
+// releases the previous background, the way replace swaps avatars and
+// banners. Backgrounds picked from or uploaded to the media library stay in
+// it, where they can be picked again or deleted by the user.
+func (is *ImageService) SetBackground(ctx context.Context, handle, key string, set func(ctx context.Context, handle, key string) error) error {
+	p, err := is.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+
+	var previous string
+	current, err := is.ThemeRepository.GetTheme(ctx, p.ID)
+	if err != nil && !errors.Is(err, theme_repository.ErrThemeNotFound) {
+		return err
+	}
+	if err == nil {
+		previous = current.Background.Image
+	}
+
+	if err := set(ctx, p.Handle, key); err != nil {
+		return err
+	}
+
+	if previous != "" && previous != key {
+		is.releaseBackground(ctx, p.ID, previous)
+	}
+	return nil
+}
+
+// MediaKey returns the key of an image of the media library, which theme
+// backgrounds reference directly.
+func (is *ImageService) MediaKey(ctx context.Context, pageID, mediaID int32) (string, error) {
+	m, err := is.MediaRepository.GetMedia(ctx, pageID, mediaID)
+	if err != nil {
+		return "", err
+	}
+	return m.ObjectKey, nil
+}
+
+// storeMedia keeps img, encoded without metadata, in the media library of
+// the page. Storing the same image again returns the existing media. Callers
+// hold the lock of the account.
+func (is *ImageService) storeMedia(ctx context.Context, pageID int32, img image.Image) (media_repository.Media, error) {
+	encoded, err := imaging.EncodeJPEG(img)
+	if err != nil {
+		return media_repository.Media{}, err
+	}
+	return is.storeEncodedMedia(ctx, pageID, img, encoded)
+}
+
+// storeEncodedMedia is storeMedia for an image already encoded.
+func (is *ImageService) storeEncodedMedia(ctx context.Context, pageID int32, img image.Image, encoded []byte) (media_repository.Media, error) {
+	if err := is.checkQuota(ctx, pageID, int64(len(encoded))); err != nil {
+		return media_repository.Media{}, err
+	}
+
+	key := contentKey(pageID, ImageKindMedia, encoded) + imaging.Extension
+	if err := is.FileService.PutObject(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), imaging.ContentType); err != nil {
+		return media_repository.Media{}, err
+	}
+
+	bounds := img.Bounds()
+	return is.MediaRepository.CreateMedia(ctx, media_repository.Media{
+		PageID:      pageID,
+		ObjectKey:   key,
+		ContentType: imaging.ContentType,
+		Width:       int32(bounds.Dx()),
+		Height:      int32(bounds.Dy()),
+		Size:        int64(len(encoded)),
+	})
+}
+
+// ListMedia returns the media library of the page, newest first. URL holds
+// the object key.
+func (is *ImageService) ListMedia(ctx context.Context, pageID int32) ([]media.Media, error) {
+	library, err := is.MediaRepository.ListMedia(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	referenced, err := is.referencedKeys(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]media.Media, 0, len(library))
+	for _, m := range library {
+		result = append(result, media.Media{
+			ID:          m.ID,
+			URL:         m.ObjectKey,
+			ContentType: m.ContentType,
+			Width:       m.Width,
+			Height:      m.Height,
+			Size:        m.Size,
+			InUse:       slices.ContainsFunc(mediaKeys(m), func(key string) bool { return referenced[key] }),
+			CreatedAt:   m.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// DeleteMedia removes an image from the media library along with the
+// pictures made from it. Images still shown by the page, its links, its
+// published profile or a kept revision cannot be deleted. The row goes first,
+// in one statement with the reference check, so the objects are only removed
+// once nothing can point at them anymore.
+func (is *ImageService) DeleteMedia(ctx context.Context, pageID, mediaID int32) error {
+	unlock, err := is.lock(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	m, err := is.MediaRepository.GetMedia(ctx, pageID, mediaID)
+	if err != nil {
+		return err
+	}
+
+	if err := is.MediaRepository.DeleteMedia(ctx, pageID, mediaID, mediaKeys(m)); err != nil {
+		if errors.Is(err, media_repository.ErrMediaReferenced) {
+			return ErrMediaInUse
+		}
+		return err
+	}
+
+	// Objects left behind by a failure here are unreferenced and collected
+	// by the storage GC.
+	if err := is.FileService.RemoveImage(ctx, mediaPictureKey(m, ImageKindAvatar, imaging.AvatarVariants), imaging.AvatarVariants); err != nil {
+		log.Printf("could not remove the avatar made from %s: %v", m.ObjectKey, err)
+	}
+	if err := is.FileService.RemoveImage(ctx, mediaPictureKey(m, ImageKindBanner, imaging.BannerVariants), imaging.BannerVariants); err != nil {
+		log.Printf("could not remove the banner made from %s: %v", m.ObjectKey, err)
+	}
+	if err := is.FileService.RemoveObject(ctx, m.ObjectKey); err != nil {
+		log.Printf("could not remove %s: %v", m.ObjectKey, err)
+	}
+	return nil
+}
+
+// BackfillMedia adds the pictures of pages that predate the media library to
+// it, so they can be picked again. Pictures that cannot be read are logged
+// and skipped.
+func (is *ImageService) BackfillMedia(ctx context.Context) error {
+	pictures, err := is.MediaRepository.ListPicturesWithoutMedia(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pictures {
+		for _, key := range []string{p.ProfilePicture, p.BannerPicture} {
+			if key == "" {
+				continue
+			}
+			if err := is.backfill(ctx, p.PageID, key); err != nil {
+				log.Printf("could not add %s to the media library: %v", key, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (is *ImageService) backfill(ctx context.Context, pageID int32, key string) error {
+	unlock, err := is.lock(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := is.FileService.ReadObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return err
+	}
+	_, err = is.storeMedia(ctx, pageID, img)
+	return err
+}
+
+// mediaKeys are the keys that show m: the image itself and the pictures made
+// from it.
+func mediaKeys(m media_repository.Media) []string {
+	return []string{
+		m.ObjectKey,
+		mediaPictureKey(m, ImageKindAvatar, imaging.AvatarVariants),
+		mediaPictureKey(m, ImageKindBanner, imaging.BannerVariants),
+	}
+}
+
+// replace uploads the variants of a new picture, points the page at it and
+// only then releases the previous one. Objects left by a failed upload or
+// update are released as well, so the page never references a missing key.
+func (is *ImageService) replace(ctx context.Context, handle, kind string, m media_repository.Media, encoded [][]byte, variants []imaging.Variant, current func(page_repository.Page) string, update func(ctx context.Context, handle, key string) error) error {
+	p, err := is.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+
+	previous := current(p)
+	var size int64
+	for _, data := range encoded {
+		size += int64(len(data))
+	}
+	// Variants made from m before are overwritten, so only the difference
+	// is new.
+	if err := is.checkQuota(ctx, p.ID, size-pictureSize(m, kind)); err != nil {
+		return err
+	}
+
+	key, err := is.FileService.PutImage(ctx, mediaPictureBase(m, kind), encoded, variants)
+	if err != nil {
+		is.release(ctx, p.ID, mediaPictureKey(m, kind, variants), variants)
+		return err
+	}
+	if err := is.updatePictureSize(ctx, m.ID, kind, size); err != nil {
+		is.release(ctx, p.ID, key, variants)
+		return err
+	}
+
+	if key == previous {
+		return nil
+	}
+
+	if err := update(ctx, p.Handle, key); err != nil {
+		is.release(ctx, p.ID, key, variants)
+		return err
+	}
+
+	if previous != "" {
+		is.release(ctx, p.ID, previous, variants)
+	}
+	return nil
+}
+
+// Usage returns how many bytes the account owning a page stores against its
+// quota, over all of its pages: their media libraries with the pictures made
+// from them, the declared sizes of their pending uploads and their
+// quarantined files. The first two are summed in the database; quarantined
+// files are listed, and the quota keeps them few. Pictures made from a
+// library image stop counting once release or the storage GC removes them.
+func (is *ImageService) Usage(ctx context.Context, pageID int32) (int64, error) {
+	p, err := is.PageRepository.GetPageByID(ctx, pageID)
+	if err != nil {
+		return 0, err
+	}
+	library, err := is.MediaRepository.Usage(ctx, p.AccountID)
+	if err != nil {
+		return 0, err
+	}
+	pending, err := is.UploadRepository.PendingSize(ctx, p.AccountID)
+	if err != nil {
+		return 0, err
+	}
+
+	pages, err := is.PageRepository.ListPagesByAccount(ctx, p.AccountID)
+	if err != nil {
+		return 0, err
+	}
+	var quarantined int64
+	for _, page := range pages {
+		size, err := is.FileService.StoredSize(ctx, fmt.Sprintf("%spages/%d/", quarantinePrefix, page.ID))
+		if err != nil {
+			return 0, err
+		}
+		quarantined += size
+	}
+	return library + pending + quarantined, nil
+}
+
+// checkQuota returns ErrQuotaExceeded when size more bytes would take the
+// account owning the page over its quota. The check is only exact while the
+// lock of the account is held.
+func (is *ImageService) checkQuota(ctx context.Context, pageID int32, size int64) error {
+	used, err := is.Usage(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	if used+size > is.FileService.Quota {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// encodedUpload is an upload encoded the way it is stored: the image kept in
+// the media library and the variants of the picture made from it.
+type encodedUpload struct {
+	media   []byte
+	picture [][]byte
+}
+
+func encodeUpload(img image.Image, kind string) (encodedUpload, error) {
+	media, err := imaging.EncodeJPEG(img)
+	if err != nil {
+		return encodedUpload{}, err
+	}
+	picture, err := encodeVariants(img, pictureVariants(kind))
+	if err != nil {
+		return encodedUpload{}, err
+	}
+	return encodedUpload{media: media, picture: picture}, nil
+}
+
+// size returns the bytes the upload takes once stored.
+func (u encodedUpload) size() int64 {
+	size := int64(len(u.media))
+	for _, data := range u.picture {
+		size += int64(len(data))
+	}
+	return size
+}
+
+// pictureVariants returns the variants of the picture of kind.
+func pictureVariants(kind string) []imaging.Variant {
+	if kind == ImageKindBanner {
+		return imaging.BannerVariants
+	}
+	return imaging.AvatarVariants
+}
+
+// pictureSize returns the bytes of the variants of kind made from m.
+func pictureSize(m media_repository.Media, kind string) int64 {
+	if kind == ImageKindBanner {
+		return m.BannerSize
+	}
+	return m.AvatarSize
+}
+
+func (is *ImageService) updatePictureSize(ctx context.Context, id int32, kind string, size int64) error {
+	if kind == ImageKindBanner {
+		return is.MediaRepository.UpdateBannerSize(ctx, id, size)
+	}
+	return is.MediaRepository.UpdateAvatarSize(ctx, id, size)
+}
+
+// release deletes the objects of key unless the draft, the published profile
+// or a kept revision of the page still shows it. Failures are logged only,
+// since the page itself is already consistent.
+func (is *ImageService) release(ctx context.Context, pageID int32, key string, variants []imaging.Variant) {
+	used, err := is.inUse(ctx, pageID, key)
+	if err != nil {
+		log.Printf("could not check references to %s: %v", key, err)
+		return
+	}
+	if used {
+		return
+	}
+
+	if err := is.FileService.RemoveImage(ctx, key, variants); err != nil {
+		log.Printf("could not remove %s: %v", key, err)
+		return
+	}
+	if err := resetPictureSize(ctx, is.MediaRepository, key); err != nil {
+		log.Printf("could not reset the size of %s: %v", key, err)
+	}
+}
+
+// releaseBackground deletes a background that is no longer shown when it
+// predates the media library. Library images are never removed implicitly.
+func (is *ImageService) releaseBackground(ctx context.Context, pageID int32, key string) {
+	library, err := is.MediaRepository.ListMedia(ctx, pageID)
+	if err != nil {
+		log.Printf("could not list the media of page %d: %v", pageID, err)
+		return
+	}
+	if slices.ContainsFunc(library, func(m media_repository.Media) bool { return m.ObjectKey == key }) {
+		return
+	}
+
+	is.release(ctx, pageID, key, nil)
+}
+
+func (is *ImageService) inUse(ctx context.Context, pageID int32, key string) (bool, error) {
+	referenced, err := is.referencedKeys(ctx, pageID)
+	if err != nil {
+		return false, err
+	}
+	return referenced[key], nil
+}
+
+// referencedKeys returns every key shown by the draft, its links, the
+// published profile or a kept revision of the page.
+func (is *ImageService) referencedKeys(ctx context.Context, pageID int32) (map[string]bool, error) {
+	p, err := is.PageRepository.GetPageByID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	referenced := map[string]bool{p.ProfilePicture: true, p.BannerPicture: true}
+
+	draft, err := is.ThemeRepository.GetTheme(ctx, pageID)
+	if err != nil && !errors.Is(err, theme_repository.ErrThemeNotFound) {
+		return nil, err
+	}
+	if err == nil {
+		referenced[draft.Background.Image] = true
+	}
+
+	pageLinks, err := is.LinksRepository.FindAllLinksFromAPage(ctx, pageID)
+	if err != nil && !errors.Is(err, links_repository.ErrLinksNotFound) {
+		return nil, err
+	}
+	for _, l := range pageLinks {
+		referenced[l.Thumbnail] = true
+	}
+
+	published, err := is.PublishedProfileRepository.GetPublishedProfile(ctx, pageID)
+	if err != nil && !errors.Is(err, published_profile_repository.ErrProfileNotPublished) {
+		return nil, err
+	}
+	if err == nil {
+		referenced[published.ProfilePicture] = true
+		referenced[published.BannerPicture] = true
+		referenced[backgroundOf(published.Theme)] = true
+	}
+
+	revisions, err := is.RevisionRepository.ListRevisions(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range revisions {
+		rev, err := is.RevisionRepository.GetRevision(ctx, pageID, r.ID)
+		if err != nil {
+			return nil, err
+		}
+		referenced[rev.Snapshot.ProfilePicture] = true
+		referenced[rev.Snapshot.BannerPicture] = true
+		referenced[backgroundOf(rev.Snapshot.Theme)] = true
+		for _, l := range rev.Snapshot.Links {
+			referenced[l.Thumbnail] = true
+		}
+	}
+
+	delete(referenced, "")
+	return referenced, nil
+}
+
+func backgroundOf(t *theme.Theme) string {
+	if t == nil {
+		return ""
+	}
+	return t.Background.Image
+}
+
+// contentKey names an upload after its content, so two pages never share a
+// key and uploading the same file again keeps its key.
+func contentKey(pageID int32, kind string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("pages/%d/%s/%s", pageID, kind, hex.EncodeToString(sum[:]))
+}
+
+// mediaPictureBase is where the variants of a picture made from m are
+// stored. It shares the hash of the media key, so the pictures made from an
+// image can be found from it.
+func mediaPictureBase(m media_repository.Media, kind string) string {
+	hash := strings.TrimSuffix(path.Base(m.ObjectKey), imaging.Extension)
+	return fmt.Sprintf("pages/%d/%s/%s", m.PageID, kind, hash)
+}
+
+// pictureSource returns the key of the media the avatar or banner at key was
+// made from, reversing mediaPictureBase.
+func pictureSource(key string) (mediaKey, kind string, ok bool) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 5 || parts[0] != "pages" || (parts[2] != ImageKindAvatar && parts[2] != ImageKindBanner) {
+		return "", "", false
+	}
+	return fmt.Sprintf("pages/%s/%s/%s%s", parts[1], ImageKindMedia, parts[3], imaging.Extension), parts[2], true
+}
+
+// resetPictureSize stops counting the picture at key, which was removed from
+// storage, in the size of the media it was made from.
+func resetPictureSize(ctx context.Context, mediaRepository media_repository.MediaRepository, key string) error {
+	mediaKey, kind, ok := pictureSource(key)
+	if !ok {
+		return nil
+	}
+	if kind == ImageKindBanner {
+		return mediaRepository.ResetBannerSize(ctx, mediaKey)
+	}
+	return mediaRepository.ResetAvatarSize(ctx, mediaKey)
+}
+
+func mediaPictureKey(m media_repository.Media, kind string, variants []imaging.Variant) string {
+	return imaging.VariantKey(mediaPictureBase(m, kind), variants[len(variants)-1])
+}
+
+// lock serializes the work done on the stored files of the account owning
+// the page, across instances. The lock within the process comes first, so
+// waiting for the account takes at most one database connection per process.
+func (is *ImageService) lock(ctx context.Context, pageID int32) (unlock func(), err error) {
+	p, err := is.PageRepository.GetPageByID(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	unlockLocal := is.locks.lock(p.AccountID)
+	unlockStorage, err := is.UploadRepository.LockStorage(ctx, p.AccountID)
+	if err != nil {
+		unlockLocal()
+		return nil, err
+	}
+	return func() {
+		unlockStorage()
+		unlockLocal()
+	}, nil
+}
+
+// accountLocks serializes the accounts within the process. A lock is dropped
+// once nobody holds or waits for it.
+type accountLocks struct {
+	mu       sync.Mutex
+	accounts map[int32]*accountLock
+}
+
+type accountLock struct {
+	sync.Mutex
+	users int
+}
+
+func (l *accountLocks) lock(accountID int32) (unlock func()) {
+	l.mu.Lock()
+	if l.accounts == nil {
+		l.accounts = map[int32]*accountLock{}
+	}
+	a, ok := l.accounts[accountID]
+	if !ok {
+		a = &accountLock{}
+		l.accounts[accountID] = a
+	}
+	a.users++
+	l.mu.Unlock()
+
+	a.Lock()
+	return func() {
+		a.Unlock()
+		l.mu.Lock()
+		a.users--
+		if a.users == 0 {
+			delete(l.accounts, accountID)
+		}
+		l.mu.Unlock()
+	}
+}
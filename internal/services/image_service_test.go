@@ -0,0 +1,621 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/imaging"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/revision"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+	"github.com/theNixagen/linker/internal/repositories/storage_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+	"github.com/theNixagen/linker/internal/repositories/upload_repository"
+)
+
+func newTestImageService(t *testing.T) (*ImageService, *page_repository.InMemoryPageRepository, *revision_repository.InMemoryRevisionRepository, *storage_repository.InMemoryObjectStorage) {
+	pr := page_repository.NewInMemoryPageRepository()
+	lr := links_repository.NewInMemoryLinksRepository()
+	pub := published_profile_repository.NewInMemoryPublishedProfileRepository(lr)
+	tr := theme_repository.NewInMemoryThemeRepository()
+	rr := revision_repository.NewInMemoryRevisionRepository(pr, tr, lr, pub)
+	storage := newTestStorage(t)
+	pr.CreatePage(t.Context(), page_repository.Page{ID: 1, AccountID: 1, Handle: "johndoe", Name: "John Doe"})
+
+	return NewImageService(pr, pub, rr, tr, lr, media_repository.NewInMemoryMediaRepository(pr, tr, pub, rr), upload_repository.NewInMemoryUploadRepository(pr), NewFileService(storage, 0, 0), NoopScanner{}), pr, rr, storage
+}
+
+func testPNG(t *testing.T, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 300, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 300; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return buf.Bytes()
+}
+
+func objectKeys(t *testing.T, storage *storage_repository.InMemoryObjectStorage, prefix string) []string {
+	t.Helper()
+	objects, err := storage.List(t.Context(), prefix)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	keys := make([]string, 0, len(objects))
+	for _, o := range objects {
+		keys = append(keys, o.Key)
+	}
+	return keys
+}
+
+func TestImageService_UploadProfilePhoto(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.White))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	key := pr.Pages[0].ProfilePicture
+	if !strings.HasPrefix(key, "pages/1/avatar/") || !strings.HasSuffix(key, "/lg.jpg") {
+		t.Fatalf("expected content-addressed key, got %q", key)
+	}
+	if keys := objectKeys(t, storage, "pages/1/avatar/"); len(keys) != len(imaging.AvatarVariants) {
+		t.Fatalf("expected one object per variant, got %v", keys)
+	}
+	if keys := objectKeys(t, storage, "pages/1/media/"); len(keys) != 1 {
+		t.Fatalf("expected the upload in the media library, got %v", keys)
+	}
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.Black))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	if pr.Pages[0].ProfilePicture == key {
+		t.Fatalf("expected a new key for a different image")
+	}
+	if keys := objectKeys(t, storage, "pages/1/avatar/"); len(keys) != len(imaging.AvatarVariants) {
+		t.Fatalf("expected the replaced picture to be removed, got %v", keys)
+	}
+}
+
+func TestImageService_KeepsPicturesOfRevisions(t *testing.T) {
+	is, pr, rr, storage := newTestImageService(t)
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.White))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+	rr.CreateRevision(t.Context(), 1, revision.ReasonPublish, revision.Snapshot{ProfilePicture: pr.Pages[0].ProfilePicture}, 10)
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.Black))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	if keys := objectKeys(t, storage, "pages/1/avatar/"); len(keys) != 2*len(imaging.AvatarVariants) {
+		t.Fatalf("expected the picture of the revision to be kept, got %v", keys)
+	}
+}
+
+func TestImageService_PutBackground(t *testing.T) {
+	is, _, _, storage := newTestImageService(t)
+
+	white, err := is.PutBackground(t.Context(), 1, bytes.NewReader(testPNG(t, color.White)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	black, err := is.PutBackground(t.Context(), 1, bytes.NewReader(testPNG(t, color.Black)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if white == black || !strings.HasPrefix(white, "pages/1/") || !strings.HasPrefix(black, "pages/1/") {
+		t.Fatalf("expected distinct content-addressed keys, got %q and %q", white, black)
+	}
+	if keys := objectKeys(t, storage, "pages/1/"); len(keys) != 2 {
+		t.Fatalf("expected both backgrounds to be stored, got %v", keys)
+	}
+
+	is.FileService.MaxUploadSize = 16
+	if _, err := is.PutBackground(t.Context(), 1, bytes.NewReader(testPNG(t, color.White))); !errors.Is(err, ErrUploadTooLarge) {
+		t.Fatalf("expected ErrUploadTooLarge, got %v", err)
+	}
+}
+
+func TestImageService_SetBackground(t *testing.T) {
+	is, _, _, storage := newTestImageService(t)
+	ts := NewThemeService(is.PageRepository, is.ThemeRepository)
+
+	white, err := is.PutBackground(t.Context(), 1, bytes.NewReader(testPNG(t, color.White)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := is.SetBackground(t.Context(), "johndoe", white, ts.SetBackgroundImage); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	black, err := is.PutBackground(t.Context(), 1, bytes.NewReader(testPNG(t, color.Black)))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	failing := func(ctx context.Context, handle, key string) error { return errStorageDown }
+	if err := is.SetBackground(t.Context(), "johndoe", black, failing); !errors.Is(err, errStorageDown) {
+		t.Fatalf("expected errStorageDown, got %v", err)
+	}
+	if keys := objectKeys(t, storage, "pages/1/media/"); len(keys) != 2 {
+		t.Fatalf("expected the current background to be kept when the update fails, got %v", keys)
+	}
+
+	if err := is.SetBackground(t.Context(), "johndoe", black, ts.SetBackgroundImage); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys := objectKeys(t, storage, "pages/1/media/"); len(keys) != 2 {
+		t.Fatalf("expected the replaced background to stay in the media library, got %v", keys)
+	}
+	if library, _ := is.MediaRepository.ListMedia(t.Context(), 1); len(library) != 2 {
+		t.Fatalf("expected the replaced background to stay in the media library, got %+v", library)
+	}
+
+	// Backgrounds from before the media library are released.
+	legacy := "pages/1/background/legacy.jpg"
+	if err := storage.Put(t.Context(), legacy, bytes.NewReader([]byte("jpeg")), 4, imaging.ContentType); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := ts.SetBackgroundImage(t.Context(), "johndoe", legacy); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := is.SetBackground(t.Context(), "johndoe", white, ts.SetBackgroundImage); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys := objectKeys(t, storage, "pages/1/background/"); len(keys) != 0 {
+		t.Fatalf("expected the legacy background to be released, got %v", keys)
+	}
+}
+
+var errStorageDown = errors.New("storage down")
+
+// failingStorage accepts limit writes and fails every one after them.
+type failingStorage struct {
+	*storage_repository.InMemoryObjectStorage
+	limit int
+}
+
+func (s *failingStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if s.limit == 0 {
+		return errStorageDown
+	}
+	s.limit--
+	return s.InMemoryObjectStorage.Put(ctx, key, r, size, contentType)
+}
+
+func TestImageService_FailedUploadKeepsPicture(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.White))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+	key := pr.Pages[0].ProfilePicture
+	previous := objectKeys(t, storage, "pages/1/avatar/")
+
+	// The media copy and the first variant are written, the second fails.
+	is.FileService.Storage = &failingStorage{InMemoryObjectStorage: storage, limit: 2}
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.Black))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	if pr.Pages[0].ProfilePictureState != page.PictureFailed {
+		t.Errorf("expected failed state, got %q", pr.Pages[0].ProfilePictureState)
+	}
+	if pr.Pages[0].ProfilePicture != key {
+		t.Fatalf("expected the page to keep its picture, got %q", pr.Pages[0].ProfilePicture)
+	}
+	if keys := objectKeys(t, storage, "pages/1/avatar/"); !slices.Equal(keys, previous) {
+		t.Fatalf("expected the partial upload to be released, got %v", keys)
+	}
+}
+
+func TestImageService_RejectsNonImages(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+
+	err := is.UploadBanner(t.Context(), "johndoe", strings.NewReader("<html>not an image</html>"))
+	if !errors.Is(err, imaging.ErrUnsupportedType) {
+		t.Fatalf("expected ErrUnsupportedType, got %v", err)
+	}
+	if pr.Pages[0].BannerPicture != "" || len(objectKeys(t, storage, "")) != 0 {
+		t.Fatalf("expected nothing to be stored")
+	}
+}
+
+type flaggingScanner struct{}
+
+func (flaggingScanner) Scan(ctx context.Context, data []byte) (ScanResult, error) {
+	return ScanResult{Signature: "Test-Signature"}, nil
+}
+
+// blockingScanner holds every scan until release is closed.
+type blockingScanner struct {
+	release chan struct{}
+}
+
+func (s blockingScanner) Scan(ctx context.Context, data []byte) (ScanResult, error) {
+	<-s.release
+	return ScanResult{Clean: true}, nil
+}
+
+func TestImageService_ScansInBackground(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+	scanner := blockingScanner{release: make(chan struct{})}
+	is.Scanner = scanner
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.White))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pr.Pages[0].ProfilePictureState != page.PicturePending || pr.Pages[0].ProfilePicture != "" {
+		t.Fatalf("expected a pending picture while the scan runs, got %+v", pr.Pages[0])
+	}
+	if keys := objectKeys(t, storage, uploadPrefix); len(keys) != 1 {
+		t.Fatalf("expected the upload to be kept while the scan runs, got %v", keys)
+	}
+
+	close(scanner.release)
+	is.Wait()
+
+	if pr.Pages[0].ProfilePictureState != page.PictureReady || pr.Pages[0].ProfilePicture == "" {
+		t.Fatalf("expected the picture to be set once scanned, got %+v", pr.Pages[0])
+	}
+	if keys := objectKeys(t, storage, uploadPrefix); len(keys) != 0 {
+		t.Fatalf("expected the upload to be removed once processed, got %v", keys)
+	}
+}
+
+func TestImageService_ReservesStoredSize(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+	scanner := blockingScanner{release: make(chan struct{})}
+	is.Scanner = scanner
+
+	data := testPNG(t, color.White)
+	img, err := imaging.Decode(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	encoded, err := encodeUpload(img, ImageKindAvatar)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if encoded.size() <= int64(len(data)) {
+		t.Fatalf("expected the stored avatar to be larger than the %d bytes uploaded, got %d", len(data), encoded.size())
+	}
+	is.FileService.Quota = encoded.size() + int64(len(data))
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(data)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if used, _ := is.Usage(t.Context(), 1); used != encoded.size() {
+		t.Fatalf("expected the accepted upload to reserve %d bytes, got %d", encoded.size(), used)
+	}
+
+	// The second upload fits the quota as uploaded, but not once stored, so
+	// it is refused before it is accepted.
+	if err := is.UploadBanner(t.Context(), "johndoe", bytes.NewReader(data)); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if pr.Pages[0].BannerPictureState == page.PicturePending {
+		t.Fatal("expected the refused banner not to be pending")
+	}
+
+	close(scanner.release)
+	is.Wait()
+	if pr.Pages[0].ProfilePictureState != page.PictureReady {
+		t.Fatalf("expected the accepted avatar to be set, got %+v", pr.Pages[0])
+	}
+	if keys := objectKeys(t, storage, uploadPrefix); len(keys) != 0 {
+		t.Fatalf("expected both uploads to be removed, got %v", keys)
+	}
+}
+
+func TestImageService_Recover(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+	pr.CreatePage(t.Context(), page_repository.Page{ID: 2, AccountID: 1, Handle: "janedoe", Name: "Jane Doe"})
+	uploads := is.UploadRepository.(*upload_repository.InMemoryUploadRepository)
+
+	// The service stopped while processing the avatar of johndoe, and the
+	// banner of janedoe was left pending with nothing to process it.
+	data := testPNG(t, color.White)
+	if err := is.FileService.PutObject(t.Context(), "uploads/1/x", bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	uploads.Uploads = append(uploads.Uploads, upload_repository.Upload{ID: 1, PageID: 1, Kind: ImageKindAvatar, ObjectKey: "uploads/1/x", Size: int64(len(data)), ProcessingSince: time.Now().Add(-time.Hour)})
+	pr.Pages[0].ProfilePictureState = page.PicturePending
+	pr.Pages[1].BannerPictureState = page.PicturePending
+
+	if err := is.Recover(t.Context(), time.Minute); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	if pr.Pages[0].ProfilePictureState != page.PictureReady || pr.Pages[0].ProfilePicture == "" {
+		t.Fatalf("expected the upload to be processed again, got %+v", pr.Pages[0])
+	}
+	if pr.Pages[1].BannerPictureState != page.PictureFailed {
+		t.Fatalf("expected the orphaned state to fail, got %+v", pr.Pages[1])
+	}
+	if len(uploads.Uploads) != 0 || len(objectKeys(t, storage, uploadPrefix)) != 0 {
+		t.Fatalf("expected the upload to be removed once processed, got %+v", uploads.Uploads)
+	}
+}
+
+func TestImageService_QuarantinesFlaggedUploads(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.White))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+	key := pr.Pages[0].ProfilePicture
+
+	is.Scanner = flaggingScanner{}
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.Black))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	if pr.Pages[0].ProfilePicture != key {
+		t.Errorf("expected the previous picture to be kept")
+	}
+	if pr.Pages[0].ProfilePictureState != page.PictureRejected {
+		t.Errorf("expected rejected state, got %q", pr.Pages[0].ProfilePictureState)
+	}
+
+	quarantined, err := storage.List(t.Context(), quarantinePrefix)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(quarantined) != 1 || IsPublicKey(quarantined[0].Key) {
+		t.Fatalf("expected one private quarantined object, got %v", quarantined)
+	}
+
+	library, err := is.MediaRepository.Usage(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	used, err := is.Usage(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if used != library+quarantined[0].Size {
+		t.Errorf("expected the quarantined object to count towards the usage, got %d", used)
+	}
+
+	is.Scanner = NoopScanner{}
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.Black))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+	if pr.Pages[0].ProfilePictureState != page.PictureReady {
+		t.Errorf("expected ready state, got %q", pr.Pages[0].ProfilePictureState)
+	}
+}
+
+func TestImageService_Media(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.White))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	library, err := is.ListMedia(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(library) != 1 || !library[0].InUse || library[0].Width != 300 {
+		t.Fatalf("expected the avatar in the media library, got %+v", library)
+	}
+	id := library[0].ID
+
+	pr.Pages[0].BannerPictureState = page.PictureRejected
+	if err := is.AttachMedia(t.Context(), "johndoe", ImageKindBanner, id); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pr.Pages[0].BannerPictureState != page.PictureReady {
+		t.Fatalf("expected the banner state to be reset, got %q", pr.Pages[0].BannerPictureState)
+	}
+	if !strings.HasPrefix(pr.Pages[0].BannerPicture, "pages/1/banner/") {
+		t.Fatalf("expected a banner made from the media, got %q", pr.Pages[0].BannerPicture)
+	}
+
+	if err := is.DeleteMedia(t.Context(), 1, id); !errors.Is(err, ErrMediaInUse) {
+		t.Fatalf("expected ErrMediaInUse, got %v", err)
+	}
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.Black))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+	if err := is.UploadBanner(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.Black))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	if err := is.DeleteMedia(t.Context(), 1, id); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys := objectKeys(t, storage, "pages/1/media/"); len(keys) != 1 {
+		t.Errorf("expected only the media in use to be left, got %v", keys)
+	}
+	if _, err := is.MediaKey(t.Context(), 1, id); !errors.Is(err, media_repository.ErrMediaNotFound) {
+		t.Errorf("expected ErrMediaNotFound, got %v", err)
+	}
+}
+
+func TestImageService_DeleteMedia_Thumbnail(t *testing.T) {
+	is, _, rr, _ := newTestImageService(t)
+	lr := is.LinksRepository.(*links_repository.InMemoryLinksRepository)
+	m, _ := is.MediaRepository.CreateMedia(t.Context(), media_repository.Media{PageID: 1, ObjectKey: "pages/1/media/abc.jpg"})
+	lr.Links = append(lr.Links, links_repository.Link{ID: 1, PageID: 1, ThumbnailMediaID: m.ID, Thumbnail: m.ObjectKey})
+
+	library, err := is.ListMedia(t.Context(), 1)
+	if err != nil || len(library) != 1 || !library[0].InUse {
+		t.Fatalf("expected the thumbnail to be in use, got %+v (%v)", library, err)
+	}
+	if err := is.DeleteMedia(t.Context(), 1, m.ID); !errors.Is(err, ErrMediaInUse) {
+		t.Fatalf("expected ErrMediaInUse, got %v", err)
+	}
+
+	// Kept revisions can restore the link with its thumbnail.
+	lr.Links = nil
+	rr.CreateRevision(t.Context(), 1, revision.ReasonPublish, revision.Snapshot{Links: []revision.Link{{ID: 1, ThumbnailMediaID: m.ID, Thumbnail: m.ObjectKey}}}, 10)
+	if err := is.DeleteMedia(t.Context(), 1, m.ID); !errors.Is(err, ErrMediaInUse) {
+		t.Fatalf("expected ErrMediaInUse, got %v", err)
+	}
+
+	rr.Revisions = nil
+	if err := is.DeleteMedia(t.Context(), 1, m.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestImageService_BackfillMedia(t *testing.T) {
+	is, pr, _, storage := newTestImageService(t)
+
+	data := testPNG(t, color.White)
+	if err := storage.Put(t.Context(), "avatar.png", bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	pr.Pages[0].ProfilePicture = "avatar.png"
+	pr.Pages[0].BannerPicture = "missing.png"
+
+	for range 2 {
+		if err := is.BackfillMedia(t.Context()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	library, err := is.ListMedia(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(library) != 1 || library[0].Width != 300 {
+		t.Fatalf("expected the existing avatar in the media library once, got %+v", library)
+	}
+}
+
+func TestImageService_Quota(t *testing.T) {
+	is, _, _, storage := newTestImageService(t)
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.White))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+	first, err := is.Usage(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first == 0 {
+		t.Fatal("expected the avatar to count towards the usage")
+	}
+
+	if err := is.UploadProfilePhoto(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.Black))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	is.Wait()
+
+	// The avatar made from the first upload was removed, so it stops
+	// counting even though its image stays in the library.
+	var stored int64
+	objects, _ := storage.List(t.Context(), "pages/1/")
+	for _, o := range objects {
+		stored += o.Size
+	}
+	if used, _ := is.Usage(t.Context(), 1); used != stored {
+		t.Fatalf("expected the usage to match the %d bytes stored after the swap, got %d", stored, used)
+	}
+
+	library, err := is.ListMedia(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, m := range library {
+		if !m.InUse {
+			if err := is.DeleteMedia(t.Context(), 1, m.ID); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+		}
+	}
+
+	stored = 0
+	objects, _ = storage.List(t.Context(), "pages/1/")
+	for _, o := range objects {
+		stored += o.Size
+	}
+	used, err := is.Usage(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if used != stored {
+		t.Fatalf("expected the usage to match the %d bytes left after the replacement, got %d", stored, used)
+	}
+
+	is.FileService.Quota = used + 1
+	err = is.UploadBanner(t.Context(), "johndoe", bytes.NewReader(testPNG(t, color.White)))
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	// Making a banner from the library stores new variants as well.
+	err = is.AttachMedia(t.Context(), "johndoe", ImageKindBanner, library[0].ID)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if err := is.AttachMedia(t.Context(), "johndoe", ImageKindAvatar, library[0].ID); err != nil {
+		t.Fatalf("expected picking the current avatar again to fit, got %v", err)
+	}
+}
+
+func TestAccountLocks(t *testing.T) {
+	var locks accountLocks
+	unlock := locks.lock(1)
+
+	locked := make(chan struct{})
+	go func() {
+		defer close(locked)
+		locks.lock(1)()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("expected the second lock of the account to wait")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Other accounts are not held back.
+	locks.lock(2)()
+
+	unlock()
+	<-locked
+	if len(locks.accounts) != 0 {
+		t.Fatalf("expected the unused locks to be dropped, got %d", len(locks.accounts))
+	}
+}
@@ -2,46 +2,543 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/theNixagen/linker/internal/domain/auth"
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/platform"
+	"github.com/theNixagen/linker/internal/domain/utm"
+	"github.com/theNixagen/linker/internal/repositories/cache_repository"
 	"github.com/theNixagen/linker/internal/repositories/links_repository"
-	"github.com/theNixagen/linker/internal/repositories/user_repository"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidLinkPassword   = errors.New("invalid link password")
+	ErrSensitiveNotConfirmed = errors.New("sensitive content must be confirmed")
+	ErrLinkLocked            = errors.New("link is locked")
+	ErrTooManyUnlockAttempts = errors.New("too many unlock attempts, try again later")
+)
+
+const (
+	shortCodeAlphabet    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	shortCodeLength      = 7
+	shortCodeMaxAttempts = 5
+	linkResolutionTTL    = 10 * time.Minute
+	unlockTokenTTL       = 10 * time.Minute
+
+	// Password attempts are limited per client on each link and per client
+	// over UnlockAttemptWindow, so passwords can't be guessed by brute force.
+	// There is no budget shared by every client of a link, which anyone
+	// could use up to lock the link for everybody else.
+	UnlockAttemptWindow           = 15 * time.Minute
+	unlockMaxAttemptsPerLinkAndIP = 5
+	unlockMaxAttemptsPerIP        = 10
 )
 
 type LinksService struct {
-	UserRepository  user_repository.UserRepository
-	LinksRepository links_repository.LinksRepository
+	PageRepository             page_repository.PageRepository
+	LinksRepository            links_repository.LinksRepository
+	PublishedProfileRepository published_profile_repository.PublishedProfileRepository
+	MediaRepository            media_repository.MediaRepository
+	CacheRepository            cache_repository.CacheRepository
+	unlockSecret               string
 }
 
-func NewLinksService(userRepository user_repository.UserRepository, linksRepository links_repository.LinksRepository) *LinksService {
+func NewLinksService(unlockSecret string, pageRepository page_repository.PageRepository, linksRepository links_repository.LinksRepository, publishedProfileRepository published_profile_repository.PublishedProfileRepository, mediaRepository media_repository.MediaRepository, cacheRepository cache_repository.CacheRepository) *LinksService {
 	return &LinksService{
-		UserRepository:  userRepository,
-		LinksRepository: linksRepository,
+		PageRepository:             pageRepository,
+		LinksRepository:            linksRepository,
+		PublishedProfileRepository: publishedProfileRepository,
+		MediaRepository:            mediaRepository,
+		CacheRepository:            cacheRepository,
+		unlockSecret:               unlockSecret,
 	}
 }
 
-func (ls *LinksService) CreateLink(ctx context.Context, username, url, title, description string) error {
-	user, err := ls.UserRepository.GetUserByUsername(ctx, username)
+func (ls *LinksService) CreateLink(ctx context.Context, handle string, link links.CreateLink) (links.GetLink, error) {
+	owner, err := ls.PageRepository.GetPageByHandle(ctx, handle)
 	if err != nil {
-		return err
+		return links.GetLink{}, err
 	}
 
-	if err = ls.LinksRepository.CreateLink(ctx, user.ID, url, title, description); err != nil {
-		return err
+	slug := ""
+	if link.Slug != "" {
+		slug, err = links.NormalizeSlug(link.Slug)
+		if err != nil {
+			return links.GetLink{}, err
+		}
 	}
 
-	return nil
+	newLink := links_repository.Link{
+		PageID:      owner.ID,
+		Url:         link.URL,
+		Title:       link.Title,
+		Description: link.Description,
+		Slug:        slug,
+		Sensitive:   link.Sensitive,
+		CreatedAt:   time.Now(),
+	}
+
+	if link.ThumbnailMediaID != 0 {
+		thumbnail, err := ls.MediaRepository.GetMedia(ctx, owner.ID, link.ThumbnailMediaID)
+		if err != nil {
+			if errors.Is(err, media_repository.ErrMediaNotFound) {
+				return links.GetLink{}, links_repository.ErrThumbnailNotFound
+			}
+			return links.GetLink{}, err
+		}
+		newLink.ThumbnailMediaID = thumbnail.ID
+		newLink.Thumbnail = thumbnail.ObjectKey
+	}
+
+	if link.Password != "" {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(link.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return links.GetLink{}, err
+		}
+		newLink.PasswordHash = string(hashedPassword)
+	}
+
+	if err := ls.recognizePlatform(&newLink, link.Platform, link.Handle); err != nil {
+		return links.GetLink{}, err
+	}
+
+	for attempt := 0; attempt < shortCodeMaxAttempts; attempt++ {
+		newLink.ShortCode, err = generateShortCode()
+		if err != nil {
+			return links.GetLink{}, err
+		}
+		newLink.ID, err = ls.LinksRepository.CreateLink(ctx, newLink)
+		if !errors.Is(err, links_repository.ErrDuplicatedShortCode) {
+			break
+		}
+	}
+	if err != nil {
+		return links.GetLink{}, err
+	}
+
+	return toGetLink(newLink), nil
 }
 
-func (ls *LinksService) GetAllLinksFromAUser(ctx context.Context, username string) ([]links_repository.Link, error) {
-	user, err := ls.UserRepository.GetUserByUsername(ctx, username)
+func (ls *LinksService) GetAllLinksFromAPage(ctx context.Context, handle string) ([]links.GetLink, error) {
+	owner, err := ls.PageRepository.GetPageByHandle(ctx, handle)
 	if err != nil {
 		return nil, err
 	}
 
-	links, err := ls.LinksRepository.FindAllLinksFromAUser(ctx, user.ID)
+	found, err := ls.LinksRepository.FindAllLinksFromAPage(ctx, owner.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	return links, nil
+	result := make([]links.GetLink, 0, len(found))
+	for _, link := range found {
+		link.Url = tagURL(link.Url, owner.UTM.Merge(link.UTM))
+		result = append(result, toPublicLink(link))
+	}
+
+	return result, nil
+}
+
+// GetPublishedLinks returns the links visitors see, hiding every link of
+// private profiles.
+func (ls *LinksService) GetPublishedLinks(ctx context.Context, handle string) ([]links.GetLink, error) {
+	owner, err := ls.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner.Visibility == page.VisibilityPrivate {
+		return nil, page_repository.ErrPageNotFound
+	}
+
+	found, err := ls.LinksRepository.FindPublishedLinksFromAPage(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := ls.publishedUTM(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]links.GetLink, 0, len(found))
+	for _, link := range found {
+		link.Url = tagURL(link.Url, tags.Merge(link.PublishedUTM))
+		result = append(result, toPublicLink(link))
+	}
+
+	return result, nil
+}
+
+func (ls *LinksService) ResolveSlug(ctx context.Context, handle, slug string) (links.ResolvedLink, error) {
+	slug, err := links.NormalizeSlug(slug)
+	if err != nil {
+		return links.ResolvedLink{}, links_repository.ErrLinkNotFound
+	}
+
+	key := slugCacheKey(handle, slug)
+	if resolved, ok := ls.getCachedResolution(ctx, key); ok {
+		return resolved, nil
+	}
+
+	owner, err := ls.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
+			return links.ResolvedLink{}, links_repository.ErrLinkNotFound
+		}
+		return links.ResolvedLink{}, err
+	}
+
+	link, err := ls.LinksRepository.FindLinkBySlug(ctx, owner.ID, slug)
+	if err != nil {
+		return links.ResolvedLink{}, err
+	}
+
+	if !isPublic(owner, link) {
+		return links.ResolvedLink{}, links_repository.ErrLinkNotFound
+	}
+
+	tags, err := ls.publishedUTM(ctx, owner.ID)
+	if err != nil {
+		return links.ResolvedLink{}, err
+	}
+	link.Url = tagURL(link.Url, tags.Merge(link.PublishedUTM))
+
+	return ls.cacheResolution(ctx, key, link), nil
+}
+
+func (ls *LinksService) ResolveShortCode(ctx context.Context, code string) (links.ResolvedLink, error) {
+	key := fmt.Sprintf("link:code:%s", code)
+	if resolved, ok := ls.getCachedResolution(ctx, key); ok {
+		return resolved, nil
+	}
+
+	link, err := ls.LinksRepository.FindLinkByShortCode(ctx, code)
+	if err != nil {
+		return links.ResolvedLink{}, err
+	}
+
+	link.Url, err = ls.destination(ctx, link)
+	if err != nil {
+		return links.ResolvedLink{}, err
+	}
+
+	return ls.cacheResolution(ctx, key, link), nil
+}
+
+func (ls *LinksService) getCachedResolution(ctx context.Context, key string) (links.ResolvedLink, bool) {
+	cached, err := ls.CacheRepository.Get(ctx, key)
+	if err != nil || cached == "" {
+		return links.ResolvedLink{}, false
+	}
+
+	var resolved links.ResolvedLink
+	if err := json.Unmarshal([]byte(cached), &resolved); err != nil {
+		return links.ResolvedLink{}, false
+	}
+
+	return resolved, true
+}
+
+func (ls *LinksService) cacheResolution(ctx context.Context, key string, link links_repository.Link) links.ResolvedLink {
+	resolved := links.ResolvedLink{
+		ID:        link.ID,
+		URL:       link.Url,
+		Sensitive: link.Sensitive,
+		Protected: link.PasswordHash != "",
+	}
+
+	if encoded, err := json.Marshal(resolved); err == nil {
+		ls.CacheRepository.Set(ctx, key, string(encoded), linkResolutionTTL)
+	}
+
+	return resolved
+}
+
+// UnlockLink checks the gates of a link and issues a short-lived token that
+// allows the visitor to follow it. clientIP identifies the visitor for the
+// attempt limits of password-protected links.
+func (ls *LinksService) UnlockLink(ctx context.Context, id int32, clientIP string, req links.UnlockLink) (links.UnlockedLink, error) {
+	link, err := ls.LinksRepository.FindLinkByID(ctx, id)
+	if err != nil {
+		return links.UnlockedLink{}, err
+	}
+
+	destination, err := ls.destination(ctx, link)
+	if err != nil {
+		return links.UnlockedLink{}, err
+	}
+
+	if link.Sensitive && !req.ConfirmSensitive {
+		return links.UnlockedLink{}, ErrSensitiveNotConfirmed
+	}
+
+	if link.PasswordHash != "" {
+		if err := ls.countUnlockAttempt(ctx, link.ID, clientIP); err != nil {
+			return links.UnlockedLink{}, err
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(req.Password)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return links.UnlockedLink{}, ErrInvalidLinkPassword
+			}
+			return links.UnlockedLink{}, err
+		}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.UnlockClaims{
+		LinkID: link.ID,
+		Typ:    "unlock",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(unlockTokenTTL)),
+		},
+	})
+
+	tokenString, err := token.SignedString([]byte(ls.unlockSecret))
+	if err != nil {
+		return links.UnlockedLink{}, err
+	}
+
+	return links.UnlockedLink{Token: tokenString, URL: destination}, nil
+}
+
+// countUnlockAttempt returns ErrTooManyUnlockAttempts once the client went
+// over its attempts on the link or overall in the current window. Every attempt counts,
+// since a correct guess can't be told apart before it is checked.
+func (ls *LinksService) countUnlockAttempt(ctx context.Context, linkID int32, clientIP string) error {
+	limited := false
+	for _, limit := range []struct {
+		key string
+		max int64
+	}{
+		{fmt.Sprintf("unlock:link:%d:ip:%s", linkID, clientIP), unlockMaxAttemptsPerLinkAndIP},
+		{fmt.Sprintf("unlock:ip:%s", clientIP), unlockMaxAttemptsPerIP},
+	} {
+		attempts, err := ls.CacheRepository.Incr(ctx, limit.key, UnlockAttemptWindow)
+		if err != nil {
+			return err
+		}
+		if attempts > limit.max {
+			limited = true
+		}
+	}
+	if limited {
+		return ErrTooManyUnlockAttempts
+	}
+	return nil
+}
+
+// CheckGate returns ErrLinkLocked unless the link is not gated or the token
+// was issued by UnlockLink for this same link.
+func (ls *LinksService) CheckGate(link links.ResolvedLink, tokenStr string) error {
+	if !link.IsGated() {
+		return nil
+	}
+
+	if tokenStr == "" {
+		return ErrLinkLocked
+	}
+
+	claims := &auth.UnlockClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return []byte(ls.unlockSecret), nil
+	})
+
+	if err != nil || !token.Valid || claims.Typ != "unlock" || claims.LinkID != link.ID {
+		return ErrLinkLocked
+	}
+
+	return nil
+}
+
+func (ls *LinksService) GetDefaultUTM(ctx context.Context, handle string) (utm.UTM, error) {
+	owner, err := ls.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return utm.UTM{}, err
+	}
+
+	return owner.UTM, nil
+}
+
+// UpdateDefaultUTM changes the draft tags of the page. Visitors keep the
+// published tags until the next publish.
+func (ls *LinksService) UpdateDefaultUTM(ctx context.Context, handle string, tags utm.UTM) error {
+	if err := tags.Validate(); err != nil {
+		return err
+	}
+
+	return ls.PageRepository.UpdateUTM(ctx, handle, tags)
+}
+
+// UpdateLinkUTM changes the draft tags of a link, published like
+// UpdateDefaultUTM.
+func (ls *LinksService) UpdateLinkUTM(ctx context.Context, handle string, linkID int32, tags utm.UTM) error {
+	if err := tags.Validate(); err != nil {
+		return err
+	}
+
+	owner, err := ls.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+
+	return ls.LinksRepository.UpdateLinkUTM(ctx, owner.ID, linkID, tags)
+}
+
+// destination returns the link url tagged with the owner's published default
+// UTM parameters and the link overrides. Links that visitors can't see are
+// reported as not found.
+func (ls *LinksService) destination(ctx context.Context, link links_repository.Link) (string, error) {
+	owner, err := ls.PageRepository.GetPageByID(ctx, link.PageID)
+	if err != nil {
+		if errors.Is(err, page_repository.ErrPageNotFound) {
+			return "", links_repository.ErrLinkNotFound
+		}
+		return "", err
+	}
+
+	if !isPublic(owner, link) {
+		return "", links_repository.ErrLinkNotFound
+	}
+
+	tags, err := ls.publishedUTM(ctx, owner.ID)
+	if err != nil {
+		return "", err
+	}
+
+	return tagURL(link.Url, tags.Merge(link.PublishedUTM)), nil
+}
+
+// publishedUTM returns the default UTM tags of the last publish, which are
+// empty while the page was never published.
+func (ls *LinksService) publishedUTM(ctx context.Context, pageID int32) (utm.UTM, error) {
+	published, err := ls.PublishedProfileRepository.GetPublishedProfile(ctx, pageID)
+	if errors.Is(err, published_profile_repository.ErrProfileNotPublished) {
+		return utm.UTM{}, nil
+	}
+	return published.UTM, err
+}
+
+// isPublic reports whether visitors can follow the link: it must be published
+// and its owner can't be private.
+func isPublic(owner page_repository.Page, link links_repository.Link) bool {
+	return link.Published && owner.Visibility != page.VisibilityPrivate
+}
+
+func invalidateLinkResolution(ctx context.Context, cache cache_repository.CacheRepository, handle string, link links_repository.Link) {
+	cache.Del(ctx, fmt.Sprintf("link:code:%s", link.ShortCode))
+	if link.Slug != "" {
+		cache.Del(ctx, slugCacheKey(handle, link.Slug))
+	}
+}
+
+// slugCacheKey lowercases the handle, which is matched case-insensitively,
+// so every spelling of it shares one cache entry.
+func slugCacheKey(handle, slug string) string {
+	return fmt.Sprintf("link:slug:%s:%s", strings.ToLower(strings.TrimSpace(handle)), slug)
+}
+
+func tagURL(rawURL string, tags utm.UTM) string {
+	tagged, err := utm.Apply(rawURL, tags)
+	if err != nil {
+		return rawURL
+	}
+	return tagged
+}
+
+func (ls *LinksService) recognizePlatform(link *links_repository.Link, platformID, handle string) error {
+	var match platform.Match
+	if handle != "" {
+		m, err := platform.Default.FromHandle(platformID, handle)
+		if err != nil {
+			return err
+		}
+		match = m
+	} else {
+		m, ok := platform.Default.Recognize(link.Url)
+		if !ok {
+			if platformID != "" {
+				return platform.ErrInvalidHandle
+			}
+			return nil
+		}
+		if platformID != "" && !strings.EqualFold(platformID, m.Platform) {
+			return platform.ErrInvalidHandle
+		}
+		match = m
+	}
+
+	link.Platform = match.Platform
+	link.Handle = match.Handle
+	link.Url = match.URL
+	if link.Title == "" {
+		p, _ := platform.Default.Get(match.Platform)
+		link.Title = p.Name
+	}
+
+	return nil
+}
+
+// generateShortCode draws each character uniformly from shortCodeAlphabet,
+// discarding the random bytes that would bias the modulo towards its start.
+func generateShortCode() (string, error) {
+	const limit = 256 - 256%len(shortCodeAlphabet)
+
+	code := make([]byte, 0, shortCodeLength)
+	buf := make([]byte, shortCodeLength*2)
+	for len(code) < shortCodeLength {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) >= limit || len(code) == shortCodeLength {
+				continue
+			}
+			code = append(code, shortCodeAlphabet[int(b)%len(shortCodeAlphabet)])
+		}
+	}
+	return string(code), nil
+}
+
+func toGetLink(link links_repository.Link) links.GetLink {
+	return links.GetLink{
+		ID:          link.ID,
+		URL:         link.Url,
+		Title:       link.Title,
+		Description: link.Description,
+		Slug:        link.Slug,
+		ShortCode:   link.ShortCode,
+		Platform:    link.Platform,
+		Handle:      link.Handle,
+		Sensitive:   link.Sensitive,
+		Protected:   link.PasswordHash != "",
+		Embed:       link.Embed,
+		Thumbnail:   link.Thumbnail,
+		CreatedAt:   link.CreatedAt,
+	}
+}
+
+// toPublicLink hides the destination of gated links until they are unlocked.
+func toPublicLink(link links_repository.Link) links.GetLink {
+	result := toGetLink(link)
+	if result.IsGated() {
+		result.URL = ""
+		result.Handle = ""
+		result.Embed = nil
+	}
+	return result
 }
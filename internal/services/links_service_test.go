@@ -0,0 +1,384 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/platform"
+	"github.com/theNixagen/linker/internal/domain/utm"
+	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+)
+
+func newTestLinksService(t *testing.T) (*LinksService, *links_repository.InMemoryLinksRepository, *cache_repository.InMemoryCacheRepository) {
+	pr := page_repository.NewInMemoryPageRepository()
+	lr := links_repository.NewInMemoryLinksRepository()
+	cr := cache_repository.NewInMemoryCacheRepository()
+	pr.CreatePage(t.Context(), page_repository.Page{
+		ID:        1,
+		AccountID: 1,
+		Handle:    "johndoe",
+		Name:      "John Doe",
+	})
+	pub := published_profile_repository.NewInMemoryPublishedProfileRepository(lr)
+	tr := theme_repository.NewInMemoryThemeRepository()
+	rr := revision_repository.NewInMemoryRevisionRepository(pr, tr, lr, pub)
+	return NewLinksService("test_unlock", pr, lr, pub, media_repository.NewInMemoryMediaRepository(pr, tr, pub, rr), cr), lr, cr
+}
+
+func TestLinksService_CreateLink(t *testing.T) {
+	ls, lr, _ := newTestLinksService(t)
+
+	link, err := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:   "https://www.youtube.com/@johndoe",
+		Title: "YouTube",
+		Slug:  "YT",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if link.Slug != "yt" {
+		t.Fatalf("expected slug 'yt', got %v", link.Slug)
+	}
+
+	if len(link.ShortCode) != shortCodeLength {
+		t.Fatalf("expected short code with %d characters, got %q", shortCodeLength, link.ShortCode)
+	}
+
+	if len(lr.Links) != 1 {
+		t.Fatalf("expected 1 link, got %d", len(lr.Links))
+	}
+}
+
+func TestLinksService_CreateLink_Thumbnail(t *testing.T) {
+	ls, lr, _ := newTestLinksService(t)
+	m, _ := ls.MediaRepository.CreateMedia(t.Context(), media_repository.Media{PageID: 1, ObjectKey: "pages/1/media/abc.jpg"})
+	other, _ := ls.MediaRepository.CreateMedia(t.Context(), media_repository.Media{PageID: 2, ObjectKey: "pages/2/media/abc.jpg"})
+
+	link, err := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com", Title: "Example", ThumbnailMediaID: m.ID})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if link.Thumbnail != m.ObjectKey || lr.Links[0].ThumbnailMediaID != m.ID {
+		t.Fatalf("expected the media as thumbnail, got %+v", lr.Links[0])
+	}
+
+	// Images of the library of another page can't be used.
+	_, err = ls.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com", Title: "Example", ThumbnailMediaID: other.ID})
+	if !errors.Is(err, links_repository.ErrThumbnailNotFound) {
+		t.Fatalf("expected ErrThumbnailNotFound, got %v", err)
+	}
+}
+
+func TestLinksService_CreateLink_ReservedSlug(t *testing.T) {
+	ls, _, _ := newTestLinksService(t)
+
+	_, err := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:   "https://example.com",
+		Title: "Example",
+		Slug:  "admin",
+	})
+
+	if !errors.Is(err, links.ErrReservedSlug) {
+		t.Fatalf("expected ErrReservedSlug, got %v", err)
+	}
+}
+
+func TestLinksService_CreateLink_DuplicatedSlug(t *testing.T) {
+	ls, _, _ := newTestLinksService(t)
+
+	link := links.CreateLink{URL: "https://example.com", Title: "Example", Slug: "site"}
+	if _, err := ls.CreateLink(t.Context(), "johndoe", link); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	_, err := ls.CreateLink(t.Context(), "johndoe", link)
+	if !errors.Is(err, links_repository.ErrDuplicatedSlug) {
+		t.Fatalf("expected ErrDuplicatedSlug, got %v", err)
+	}
+}
+
+func TestLinksService_ResolveSlug(t *testing.T) {
+	ls, lr, cr := newTestLinksService(t)
+
+	ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:   "https://www.youtube.com/@johndoe",
+		Title: "YouTube",
+		Slug:  "yt",
+	})
+
+	if _, err := ls.ResolveSlug(t.Context(), "johndoe", "yt"); !errors.Is(err, links_repository.ErrLinkNotFound) {
+		t.Fatalf("expected draft link not to resolve, got %v", err)
+	}
+
+	lr.PublishLinks(1)
+
+	link, err := ls.ResolveSlug(t.Context(), "JohnDoe", "yt")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if link.URL != "https://www.youtube.com/@johndoe" {
+		t.Fatalf("expected youtube url, got %v", link.URL)
+	}
+
+	if cached, _ := cr.Get(t.Context(), "link:slug:johndoe:yt"); cached == "" {
+		t.Fatalf("expected resolution to be cached")
+	}
+}
+
+func TestGenerateShortCode(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := generateShortCode()
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(code) != shortCodeLength || strings.Trim(code, shortCodeAlphabet) != "" {
+			t.Fatalf("expected %d characters of the alphabet, got %q", shortCodeLength, code)
+		}
+	}
+}
+
+func TestLinksService_ResolveShortCode(t *testing.T) {
+	ls, lr, _ := newTestLinksService(t)
+
+	link, _ := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:   "https://example.com",
+		Title: "Example",
+	})
+	lr.PublishLinks(1)
+
+	resolved, err := ls.ResolveShortCode(t.Context(), link.ShortCode)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if resolved.URL != "https://example.com" {
+		t.Fatalf("expected example url, got %v", resolved.URL)
+	}
+
+	if _, err := ls.ResolveShortCode(t.Context(), "missing"); !errors.Is(err, links_repository.ErrLinkNotFound) {
+		t.Fatalf("expected ErrLinkNotFound, got %v", err)
+	}
+}
+
+func TestLinksService_CreateLink_RecognizesPlatform(t *testing.T) {
+	ls, _, _ := newTestLinksService(t)
+
+	link, err := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL: "https://instagram.com/johndoe?igshid=abc",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if link.Platform != "instagram" || link.Handle != "johndoe" {
+		t.Fatalf("expected instagram/johndoe, got %v/%v", link.Platform, link.Handle)
+	}
+
+	if link.URL != "https://www.instagram.com/johndoe" {
+		t.Fatalf("expected normalized url, got %v", link.URL)
+	}
+
+	if link.Title != "Instagram" {
+		t.Fatalf("expected default title 'Instagram', got %v", link.Title)
+	}
+}
+
+func TestLinksService_CreateLink_FromHandle(t *testing.T) {
+	ls, _, _ := newTestLinksService(t)
+
+	link, err := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		Platform: "tiktok",
+		Handle:   "@johndoe",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if link.URL != "https://www.tiktok.com/@johndoe" {
+		t.Fatalf("expected tiktok url, got %v", link.URL)
+	}
+
+	_, err = ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:      "https://github.com/johndoe",
+		Platform: "instagram",
+	})
+	if !errors.Is(err, platform.ErrInvalidHandle) {
+		t.Fatalf("expected ErrInvalidHandle, got %v", err)
+	}
+}
+
+func TestLinksService_GetAllLinksFromAPage_HidesGatedLinks(t *testing.T) {
+	ls, _, _ := newTestLinksService(t)
+
+	ls.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com", Title: "Open"})
+	ls.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com/18", Title: "Sensitive", Sensitive: true})
+	ls.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com/vip", Title: "Private", Password: "secret"})
+
+	userLinks, err := ls.GetAllLinksFromAPage(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if userLinks[0].URL == "" {
+		t.Fatalf("expected open link to expose its url")
+	}
+
+	if userLinks[1].URL != "" || !userLinks[1].Sensitive {
+		t.Fatalf("expected sensitive link to hide its url, got %+v", userLinks[1])
+	}
+
+	if userLinks[2].URL != "" || !userLinks[2].Protected {
+		t.Fatalf("expected protected link to hide its url, got %+v", userLinks[2])
+	}
+}
+
+func TestLinksService_UnlockLink(t *testing.T) {
+	ls, lr, _ := newTestLinksService(t)
+
+	created, _ := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:       "https://example.com/vip",
+		Title:     "Private",
+		Sensitive: true,
+		Password:  "secret",
+	})
+	lr.PublishLinks(1)
+
+	resolved, err := ls.ResolveShortCode(t.Context(), created.ShortCode)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := ls.CheckGate(resolved, ""); !errors.Is(err, ErrLinkLocked) {
+		t.Fatalf("expected ErrLinkLocked, got %v", err)
+	}
+
+	if _, err := ls.UnlockLink(t.Context(), created.ID, "10.0.0.1", links.UnlockLink{Password: "secret"}); !errors.Is(err, ErrSensitiveNotConfirmed) {
+		t.Fatalf("expected ErrSensitiveNotConfirmed, got %v", err)
+	}
+
+	if _, err := ls.UnlockLink(t.Context(), created.ID, "10.0.0.1", links.UnlockLink{Password: "wrong", ConfirmSensitive: true}); !errors.Is(err, ErrInvalidLinkPassword) {
+		t.Fatalf("expected ErrInvalidLinkPassword, got %v", err)
+	}
+
+	unlocked, err := ls.UnlockLink(t.Context(), created.ID, "10.0.0.1", links.UnlockLink{Password: "secret", ConfirmSensitive: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if unlocked.URL != "https://example.com/vip" {
+		t.Fatalf("expected destination url, got %v", unlocked.URL)
+	}
+
+	if err := ls.CheckGate(resolved, unlocked.Token); err != nil {
+		t.Fatalf("expected token to unlock the link, got %v", err)
+	}
+
+	other, _ := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com/other", Title: "Other", Sensitive: true})
+	lr.PublishLinks(1)
+	otherResolved, _ := ls.ResolveShortCode(t.Context(), other.ShortCode)
+	if err := ls.CheckGate(otherResolved, unlocked.Token); !errors.Is(err, ErrLinkLocked) {
+		t.Fatalf("expected token not to unlock other links, got %v", err)
+	}
+}
+
+func TestLinksService_UnlockLink_AttemptLimits(t *testing.T) {
+	ls, lr, _ := newTestLinksService(t)
+
+	created, _ := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:      "https://example.com/vip",
+		Title:    "Private",
+		Password: "correct horse",
+	})
+	lr.PublishLinks(1)
+
+	other, _ := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:      "https://example.com/other",
+		Title:    "Other",
+		Password: "battery staple",
+	})
+	lr.PublishLinks(1)
+
+	wrong := links.UnlockLink{Password: "wrong"}
+	for i := 0; i < unlockMaxAttemptsPerLinkAndIP; i++ {
+		if _, err := ls.UnlockLink(t.Context(), created.ID, "10.0.0.1", wrong); !errors.Is(err, ErrInvalidLinkPassword) {
+			t.Fatalf("expected ErrInvalidLinkPassword, got %v", err)
+		}
+	}
+	if _, err := ls.UnlockLink(t.Context(), created.ID, "10.0.0.1", links.UnlockLink{Password: "correct horse"}); !errors.Is(err, ErrTooManyUnlockAttempts) {
+		t.Fatalf("expected the client to be limited on the link, got %v", err)
+	}
+
+	// Other clients are not locked out of the link by someone else's
+	// guesses.
+	if _, err := ls.UnlockLink(t.Context(), created.ID, "10.0.0.2", links.UnlockLink{Password: "correct horse"}); err != nil {
+		t.Fatalf("expected another client to unlock the link, got %v", err)
+	}
+
+	for i := 0; i < unlockMaxAttemptsPerIP-unlockMaxAttemptsPerLinkAndIP-1; i++ {
+		if _, err := ls.UnlockLink(t.Context(), other.ID, "10.0.0.1", wrong); !errors.Is(err, ErrInvalidLinkPassword) {
+			t.Fatalf("expected ErrInvalidLinkPassword, got %v", err)
+		}
+	}
+	if _, err := ls.UnlockLink(t.Context(), other.ID, "10.0.0.1", links.UnlockLink{Password: "battery staple"}); !errors.Is(err, ErrTooManyUnlockAttempts) {
+		t.Fatalf("expected the client to be limited across links, got %v", err)
+	}
+}
+
+func TestLinksService_UTM(t *testing.T) {
+	ls, lr, _ := newTestLinksService(t)
+
+	created, _ := ls.CreateLink(t.Context(), "johndoe", links.CreateLink{
+		URL:   "https://example.com/shop?ref=bio#top",
+		Title: "Shop",
+	})
+	lr.PublishLinks(1)
+
+	if _, err := ls.ResolveShortCode(t.Context(), created.ShortCode); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := ls.UpdateDefaultUTM(t.Context(), "johndoe", utm.UTM{Source: "linker", Medium: "profile"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := ls.UpdateLinkUTM(t.Context(), "johndoe", created.ID, utm.UTM{Campaign: "launch"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := "https://example.com/shop?ref=bio&utm_source=linker&utm_medium=profile&utm_campaign=launch#top"
+
+	userLinks, _ := ls.GetAllLinksFromAPage(t.Context(), "johndoe")
+	if userLinks[0].URL != want {
+		t.Fatalf("expected %q, got %q", want, userLinks[0].URL)
+	}
+
+	resolved, err := ls.ResolveShortCode(t.Context(), created.ShortCode)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if resolved.URL != "https://example.com/shop?ref=bio#top" {
+		t.Fatalf("expected draft tags not to be served, got %q", resolved.URL)
+	}
+
+	if err := ls.UpdateLinkUTM(t.Context(), "johndoe", 99, utm.UTM{}); !errors.Is(err, links_repository.ErrLinkNotFound) {
+		t.Fatalf("expected ErrLinkNotFound, got %v", err)
+	}
+
+	if err := ls.UpdateDefaultUTM(t.Context(), "johndoe", utm.UTM{Params: map[string]string{"bad key": "x"}}); !errors.Is(err, utm.ErrInvalidParam) {
+		t.Fatalf("expected ErrInvalidParam, got %v", err)
+	}
+}
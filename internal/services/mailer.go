@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+const smtpDefaultTimeout = 30 * time.Second
+
+var (
+	ErrMailerUnavailable = errors.New("no mailer is configured")
+)
+
+// Mailer delivers transactional emails such as collaborator invitations.
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// SMTPMailer delivers emails through an SMTP relay at Address, a host:port
+// pair. The connection is upgraded with STARTTLS when the server offers it,
+// and Username and Password are used when set.
+type SMTPMailer struct {
+	Address  string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+func NewSMTPMailer(address, username, password, from string) *SMTPMailer {
+	return &SMTPMailer{
+		Address:  address,
+		Username: username,
+		Password: password,
+		From:     from,
+		Timeout:  smtpDefaultTimeout,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	host, _, err := net.SplitHostPort(m.Address)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.Timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.Address)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(plainTextEmail(m.From, to, subject, body)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// plainTextEmail builds a plain text email. Header values come from the
+// service, but line breaks are still dropped so they can't add headers.
+func plainTextEmail(from, to, subject, body string) []byte {
+	clean := strings.NewReplacer("\r", "", "\n", "")
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", clean.Replace(from))
+	fmt.Fprintf(&msg, "To: %s\r\n", clean.Replace(to))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", clean.Replace(subject)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(msg.String())
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP accepts a single message and sends what it received on the
+// returned channel.
+func fakeSMTP(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		serveSMTP(conn, received)
+	}()
+	return listener.Addr().String(), received
+}
+
+func serveSMTP(conn net.Conn, received chan<- string) {
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	var session strings.Builder
+	reply("220 localhost ready")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL"), strings.HasPrefix(command, "RCPT"):
+			session.WriteString(line)
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 go ahead")
+			for {
+				data, err := reader.ReadString('\n')
+				if err != nil || data == ".\r\n" {
+					break
+				}
+				session.WriteString(data)
+			}
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 bye")
+			received <- session.String()
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	address, received := fakeSMTP(t)
+	mailer := NewSMTPMailer(address, "", "", "linker@example.com")
+
+	if err := mailer.Send(t.Context(), "manager@example.com", "Invitation to manage @johndoe", "Accept it\nhere"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	session := <-received
+	for _, want := range []string{
+		"MAIL FROM:<linker@example.com>",
+		"RCPT TO:<manager@example.com>",
+		"Subject: Invitation to manage @johndoe\r\n",
+		"Accept it\r\nhere",
+	} {
+		if !strings.Contains(session, want) {
+			t.Errorf("expected the session to contain %q, got %q", want, session)
+		}
+	}
+}
+
+func TestPlainTextEmail_DropsLineBreaksInHeaders(t *testing.T) {
+	msg := string(plainTextEmail("linker@example.com", "manager@example.com\r\nBcc: evil@example.com", "Hi", "body"))
+	if strings.Contains(msg, "\r\nBcc:") {
+		t.Fatalf("expected no injected header, got %q", msg)
+	}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/theNixagen/linker/internal/domain/collaborator"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/domain/username"
+	"github.com/theNixagen/linker/internal/repositories/collaborator_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+)
+
+type PageService struct {
+	PageRepository             page_repository.PageRepository
+	PublishedProfileRepository published_profile_repository.PublishedProfileRepository
+	CollaboratorRepository     collaborator_repository.CollaboratorRepository
+}
+
+func NewPageService(pageRepository page_repository.PageRepository, publishedProfileRepository published_profile_repository.PublishedProfileRepository, collaboratorRepository collaborator_repository.CollaboratorRepository) *PageService {
+	return &PageService{
+		PageRepository:             pageRepository,
+		PublishedProfileRepository: publishedProfileRepository,
+		CollaboratorRepository:     collaboratorRepository,
+	}
+}
+
+func (ps *PageService) CreatePage(ctx context.Context, accountID int32, req page.CreatePage) (page.GetPage, error) {
+	handle, err := username.Normalize(req.Handle)
+	if err != nil {
+		return page.GetPage{}, err
+	}
+
+	id, err := ps.PageRepository.CreatePage(ctx, page_repository.Page{
+		AccountID:  accountID,
+		Handle:     handle,
+		Name:       req.Name,
+		Visibility: page.VisibilityPublic,
+	})
+	if err != nil {
+		return page.GetPage{}, err
+	}
+
+	created, err := ps.PageRepository.GetPageByID(ctx, id)
+	if err != nil {
+		return page.GetPage{}, err
+	}
+
+	result := toGetPage(created)
+	result.Role = collaborator.RoleOwner
+	return result, nil
+}
+
+// ListPages returns the pages the account owns followed by the pages it
+// collaborates on.
+func (ps *PageService) ListPages(ctx context.Context, accountID int32) ([]page.GetPage, error) {
+	pages, err := ps.PageRepository.ListPagesByAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborations, err := ps.CollaboratorRepository.ListCollaborations(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]page.GetPage, 0, len(pages)+len(collaborations))
+	for _, p := range pages {
+		owned := toGetPage(p)
+		owned.Role = collaborator.RoleOwner
+		result = append(result, owned)
+	}
+
+	for _, c := range collaborations {
+		p, err := ps.PageRepository.GetPageByID(ctx, c.PageID)
+		if err != nil {
+			return nil, err
+		}
+		shared := toGetPage(p)
+		shared.Role = c.Role
+		result = append(result, shared)
+	}
+
+	return result, nil
+}
+
+// ManagedPage returns the page an account is editing and the account role on
+// it. An empty handle selects the account's first page; pages the account
+// neither owns nor collaborates on are not found.
+func (ps *PageService) ManagedPage(ctx context.Context, accountID int32, handle string) (page_repository.Page, string, error) {
+	if handle == "" {
+		pages, err := ps.ListPages(ctx, accountID)
+		if err != nil {
+			return page_repository.Page{}, "", err
+		}
+		if len(pages) == 0 {
+			return page_repository.Page{}, "", page_repository.ErrPageNotFound
+		}
+		handle = pages[0].Handle
+	}
+
+	p, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return page_repository.Page{}, "", err
+	}
+
+	role, err := ps.Role(ctx, p, accountID)
+	if err != nil {
+		return page_repository.Page{}, "", err
+	}
+
+	return p, role, nil
+}
+
+// Role returns the role of an account on a page.
+func (ps *PageService) Role(ctx context.Context, p page_repository.Page, accountID int32) (string, error) {
+	if p.AccountID == accountID {
+		return collaborator.RoleOwner, nil
+	}
+
+	c, err := ps.CollaboratorRepository.GetCollaborator(ctx, p.ID, accountID)
+	if err != nil {
+		if errors.Is(err, collaborator_repository.ErrCollaboratorNotFound) {
+			return "", page_repository.ErrPageNotFound
+		}
+		return "", err
+	}
+
+	return c.Role, nil
+}
+
+func (ps *PageService) GetPage(ctx context.Context, handle string) (page.GetPage, error) {
+	p, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return page.GetPage{}, err
+	}
+	return toGetPage(p), nil
+}
+
+// GetPublishedPage returns the page as visitors see it, built from the last
+// published snapshot. Private and never published pages are not found.
+func (ps *PageService) GetPublishedPage(ctx context.Context, handle string) (page.GetPage, error) {
+	p, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return page.GetPage{}, err
+	}
+
+	if p.Visibility == page.VisibilityPrivate {
+		return page.GetPage{}, page_repository.ErrPageNotFound
+	}
+
+	published, err := ps.PublishedProfileRepository.GetPublishedProfile(ctx, p.ID)
+	if err != nil {
+		if errors.Is(err, published_profile_repository.ErrProfileNotPublished) {
+			return page.GetPage{}, page_repository.ErrPageNotFound
+		}
+		return page.GetPage{}, err
+	}
+
+	pageTheme := theme.Default()
+	if published.Theme != nil {
+		pageTheme = *published.Theme
+	}
+
+	return page.GetPage{
+		ID:             p.ID,
+		Handle:         p.Handle,
+		Name:           published.Name,
+		Bio:            published.Bio,
+		ProfilePicture: published.ProfilePicture,
+		BannerPicture:  published.BannerPicture,
+		Theme:          &pageTheme,
+		Visibility:     visibilityOf(p),
+		PublishedAt:    &published.PublishedAt,
+		CreatedAt:      p.CreatedAt,
+	}, nil
+}
+
+func (ps *PageService) UpdateBio(ctx context.Context, handle, bio string) error {
+	return ps.PageRepository.UpdateBio(ctx, handle, bio)
+}
+
+func (ps *PageService) UploadProfilePhoto(ctx context.Context, handle, objectName string) error {
+	return ps.PageRepository.UpdateProfilePhoto(ctx, handle, objectName)
+}
+
+func (ps *PageService) UploadBanner(ctx context.Context, handle, objectName string) error {
+	return ps.PageRepository.UpdateBannerPhoto(ctx, handle, objectName)
+}
+
+func toGetPage(p page_repository.Page) page.GetPage {
+	return page.GetPage{
+		ID:                  p.ID,
+		Handle:              p.Handle,
+		Name:                p.Name,
+		Bio:                 p.Bio,
+		ProfilePicture:      p.ProfilePicture,
+		BannerPicture:       p.BannerPicture,
+		ProfilePictureState: pictureStateOf(p.ProfilePictureState),
+		BannerPictureState:  pictureStateOf(p.BannerPictureState),
+		Visibility:          visibilityOf(p),
+		CreatedAt:           p.CreatedAt,
+	}
+}
+
+// pictureStateOf treats pictures uploaded before scanning existed as ready.
+func pictureStateOf(state string) string {
+	if state == "" {
+		return page.PictureReady
+	}
+	return state
+}
+
+// visibilityOf treats pages created before visibility existed as public.
+func visibilityOf(p page_repository.Page) string {
+	if p.Visibility == "" {
+		return page.VisibilityPublic
+	}
+	return p.Visibility
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/collaborator"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/repositories/collaborator_repository"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+)
+
+func newTestPageService(t *testing.T) (*PageService, *page_repository.InMemoryPageRepository) {
+	pr := page_repository.NewInMemoryPageRepository()
+	pub := published_profile_repository.NewInMemoryPublishedProfileRepository(links_repository.NewInMemoryLinksRepository())
+	pr.CreatePage(t.Context(), page_repository.Page{
+		ID:        1,
+		AccountID: 1,
+		Handle:    "johndoe",
+		Name:      "John Doe",
+	})
+	return NewPageService(pr, pub, collaborator_repository.NewInMemoryCollaboratorRepository()), pr
+}
+
+func TestPageService_GetPage(t *testing.T) {
+	ps, _ := newTestPageService(t)
+
+	p, err := ps.GetPage(t.Context(), "johndoe")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if p.Handle != "johndoe" {
+		t.Fatalf("expected handle 'johndoe', got %v", p.Handle)
+	}
+}
+
+func TestPageService_UpdateBio(t *testing.T) {
+	ps, _ := newTestPageService(t)
+
+	ps.UpdateBio(t.Context(), "johndoe", "bio")
+
+	p, err := ps.GetPage(t.Context(), "johndoe")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if p.Bio != "bio" {
+		t.Fatalf("expected bio 'bio', got %v", p.Bio)
+	}
+}
+
+func TestPageService_UploadProfilePhoto(t *testing.T) {
+	ps, _ := newTestPageService(t)
+
+	ps.UploadProfilePhoto(t.Context(), "johndoe", "fototeste.jpg")
+
+	p, err := ps.GetPage(t.Context(), "johndoe")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if p.ProfilePicture != "fototeste.jpg" {
+		t.Fatalf("expected profile picture 'fototeste.jpg', got %v", p.ProfilePicture)
+	}
+}
+
+func TestPageService_UploadBanner(t *testing.T) {
+	ps, _ := newTestPageService(t)
+
+	ps.UploadBanner(t.Context(), "johndoe", "fototeste.jpg")
+
+	p, err := ps.GetPage(t.Context(), "johndoe")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if p.BannerPicture != "fototeste.jpg" {
+		t.Fatalf("expected banner picture 'fototeste.jpg', got %v", p.BannerPicture)
+	}
+}
+
+func TestPageService_ManagedPage(t *testing.T) {
+	ps, _ := newTestPageService(t)
+
+	if _, err := ps.CreatePage(t.Context(), 1, page.CreatePage{Handle: "johnsbrand", Name: "John's Brand"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := ps.CreatePage(t.Context(), 2, page.CreatePage{Handle: "janedoe", Name: "Jane Doe"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	pages, _ := ps.ListPages(t.Context(), 1)
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+
+	first, role, err := ps.ManagedPage(t.Context(), 1, "")
+	if err != nil || first.Handle != "johndoe" || role != collaborator.RoleOwner {
+		t.Fatalf("expected owned page 'johndoe', got %v as %v (%v)", first.Handle, role, err)
+	}
+
+	brand, _, err := ps.ManagedPage(t.Context(), 1, "johnsbrand")
+	if err != nil || brand.Handle != "johnsbrand" {
+		t.Fatalf("expected page 'johnsbrand', got %v (%v)", brand.Handle, err)
+	}
+
+	if _, _, err := ps.ManagedPage(t.Context(), 1, "janedoe"); !errors.Is(err, page_repository.ErrPageNotFound) {
+		t.Fatalf("expected ErrPageNotFound for another account's page, got %v", err)
+	}
+
+	if _, err := ps.CreatePage(t.Context(), 2, page.CreatePage{Handle: "johndoe", Name: "Copy"}); !errors.Is(err, page_repository.ErrDuplicatedHandle) {
+		t.Fatalf("expected ErrDuplicatedHandle, got %v", err)
+	}
+}
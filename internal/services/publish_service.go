@@ -0,0 +1,338 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/revision"
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/domain/utm"
+	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+)
+
+// DefaultRevisionLimit is how many revisions are kept per page when no limit
+// is configured.
+const DefaultRevisionLimit = 20
+
+// PublishService manages the draft of a profile, the snapshot that is served
+// to visitors and the history of published revisions.
+type PublishService struct {
+	PageRepository             page_repository.PageRepository
+	LinksRepository            links_repository.LinksRepository
+	ThemeRepository            theme_repository.ThemeRepository
+	PublishedProfileRepository published_profile_repository.PublishedProfileRepository
+	RevisionRepository         revision_repository.RevisionRepository
+	CacheRepository            cache_repository.CacheRepository
+	revisionLimit              int
+}
+
+func NewPublishService(revisionLimit int, pageRepository page_repository.PageRepository, linksRepository links_repository.LinksRepository, themeRepository theme_repository.ThemeRepository, publishedProfileRepository published_profile_repository.PublishedProfileRepository, revisionRepository revision_repository.RevisionRepository, cacheRepository cache_repository.CacheRepository) *PublishService {
+	if revisionLimit <= 0 {
+		revisionLimit = DefaultRevisionLimit
+	}
+
+	return &PublishService{
+		PageRepository:             pageRepository,
+		LinksRepository:            linksRepository,
+		ThemeRepository:            themeRepository,
+		PublishedProfileRepository: publishedProfileRepository,
+		RevisionRepository:         revisionRepository,
+		CacheRepository:            cacheRepository,
+		revisionLimit:              revisionLimit,
+	}
+}
+
+// Preview returns the draft profile and links as they will be served once
+// published.
+func (ps *PublishService) Preview(ctx context.Context, handle string) (page.PagePreview, error) {
+	owner, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return page.PagePreview{}, err
+	}
+
+	draft, err := ps.draft(ctx, owner)
+	if err != nil {
+		return page.PagePreview{}, err
+	}
+
+	userLinks, err := ps.LinksRepository.FindAllLinksFromAPage(ctx, owner.ID)
+	if err != nil && !errors.Is(err, links_repository.ErrLinksNotFound) {
+		return page.PagePreview{}, err
+	}
+
+	changed := false
+	result := make([]links.GetLink, 0, len(userLinks))
+	for _, link := range userLinks {
+		if !link.Published || !sameUTM(link.UTM, link.PublishedUTM) {
+			changed = true
+		}
+		link.Url = tagURL(link.Url, owner.UTM.Merge(link.UTM))
+		result = append(result, toPublicLink(link))
+	}
+
+	published, err := ps.PublishedProfileRepository.GetPublishedProfile(ctx, owner.ID)
+	switch {
+	case errors.Is(err, published_profile_repository.ErrProfileNotPublished):
+		changed = true
+	case err != nil:
+		return page.PagePreview{}, err
+	default:
+		changed = changed || !sameSnapshot(draft, published)
+	}
+
+	profileTheme := theme.Default()
+	if draft.Theme != nil {
+		profileTheme = *draft.Theme
+	}
+
+	preview := page.PagePreview{
+		Page: page.GetPage{
+			ID:             owner.ID,
+			CreatedAt:      owner.CreatedAt,
+			ProfilePicture: draft.ProfilePicture,
+			Bio:            draft.Bio,
+			BannerPicture:  draft.BannerPicture,
+			Name:           draft.Name,
+			Handle:         owner.Handle,
+			Theme:          &profileTheme,
+			Visibility:     visibilityOf(owner),
+		},
+		HasUnpublishedChanges: changed,
+	}
+	if err == nil {
+		preview.Page.PublishedAt = &published.PublishedAt
+	}
+	preview.Links, preview.Socials = links.SplitSocials(result)
+
+	return preview, nil
+}
+
+// Publish replaces the public snapshot with the current draft, publishes
+// every link and records the result as a revision in one transaction.
+// Cached link resolutions are dropped since the published UTM tags may have
+// changed.
+func (ps *PublishService) Publish(ctx context.Context, handle string) (page.PublishedPage, error) {
+	owner, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return page.PublishedPage{}, err
+	}
+
+	draft, err := ps.draft(ctx, owner)
+	if err != nil {
+		return page.PublishedPage{}, err
+	}
+
+	// Every link is published, so the snapshot is taken from all of them.
+	published, err := ps.LinksRepository.FindAllLinksFromAPage(ctx, owner.ID)
+	if err != nil && !errors.Is(err, links_repository.ErrLinksNotFound) {
+		return page.PublishedPage{}, err
+	}
+
+	publishedAt, err := ps.RevisionRepository.Publish(ctx, draft, toSnapshot(draft, published), ps.revisionLimit)
+	if err != nil {
+		return page.PublishedPage{}, err
+	}
+
+	for _, link := range published {
+		invalidateLinkResolution(ctx, ps.CacheRepository, handle, link)
+	}
+
+	return page.PublishedPage{PublishedAt: publishedAt}, nil
+}
+
+func (ps *PublishService) ListRevisions(ctx context.Context, handle string) ([]revision.GetRevision, error) {
+	owner, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := ps.RevisionRepository.ListRevisions(ctx, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]revision.GetRevision, 0, len(revisions))
+	for _, rev := range revisions {
+		result = append(result, toGetRevision(rev))
+	}
+	return result, nil
+}
+
+func (ps *PublishService) DiffRevisions(ctx context.Context, handle string, fromID, toID int32) (revision.RevisionDiff, error) {
+	owner, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return revision.RevisionDiff{}, err
+	}
+
+	from, err := ps.RevisionRepository.GetRevision(ctx, owner.ID, fromID)
+	if err != nil {
+		return revision.RevisionDiff{}, err
+	}
+
+	to, err := ps.RevisionRepository.GetRevision(ctx, owner.ID, toID)
+	if err != nil {
+		return revision.RevisionDiff{}, err
+	}
+
+	return revision.RevisionDiff{
+		From:    from.ID,
+		To:      to.ID,
+		Changes: revision.Diff(from.Snapshot, to.Snapshot),
+	}, nil
+}
+
+// RestoreRevision brings the draft and the public profile back to a previous
+// revision. The restore is recorded as a new revision so it can be undone.
+func (ps *PublishService) RestoreRevision(ctx context.Context, handle string, id int32) (revision.GetRevision, error) {
+	owner, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return revision.GetRevision{}, err
+	}
+
+	rev, err := ps.RevisionRepository.GetRevision(ctx, owner.ID, id)
+	if err != nil {
+		return revision.GetRevision{}, err
+	}
+	if rev.Snapshot.UTM == nil {
+		// Older revisions didn't record the default tags, so the current
+		// ones are kept.
+		tags := owner.UTM
+		rev.Snapshot.UTM = &tags
+	}
+
+	userLinks, err := ps.LinksRepository.FindAllLinksFromAPage(ctx, owner.ID)
+	if err != nil && !errors.Is(err, links_repository.ErrLinksNotFound) {
+		return revision.GetRevision{}, err
+	}
+
+	restored, err := ps.RevisionRepository.Restore(ctx, owner.ID, rev.Snapshot, ps.revisionLimit)
+	if err != nil {
+		return revision.GetRevision{}, err
+	}
+
+	for _, link := range userLinks {
+		invalidateLinkResolution(ctx, ps.CacheRepository, handle, link)
+	}
+	for _, link := range rev.Snapshot.Links {
+		invalidateLinkResolution(ctx, ps.CacheRepository, handle, links_repository.Link{ShortCode: link.ShortCode, Slug: link.Slug})
+	}
+
+	return toGetRevision(restored), nil
+}
+
+// UpdateVisibility changes who can see the profile. Cached link resolutions
+// are dropped so private profiles stop redirecting right away.
+func (ps *PublishService) UpdateVisibility(ctx context.Context, handle, visibility string) error {
+	owner, err := ps.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+
+	if err := ps.PageRepository.UpdateVisibility(ctx, handle, visibility); err != nil {
+		return err
+	}
+
+	userLinks, err := ps.LinksRepository.FindAllLinksFromAPage(ctx, owner.ID)
+	if err != nil && !errors.Is(err, links_repository.ErrLinksNotFound) {
+		return err
+	}
+
+	for _, link := range userLinks {
+		invalidateLinkResolution(ctx, ps.CacheRepository, handle, link)
+	}
+
+	return nil
+}
+
+func (ps *PublishService) draft(ctx context.Context, owner page_repository.Page) (published_profile_repository.PublishedProfile, error) {
+	draft := published_profile_repository.PublishedProfile{
+		PageID:         owner.ID,
+		Name:           owner.Name,
+		Bio:            owner.Bio,
+		ProfilePicture: owner.ProfilePicture,
+		BannerPicture:  owner.BannerPicture,
+		UTM:            owner.UTM,
+	}
+
+	t, err := ps.ThemeRepository.GetTheme(ctx, owner.ID)
+	if err != nil && !errors.Is(err, theme_repository.ErrThemeNotFound) {
+		return published_profile_repository.PublishedProfile{}, err
+	}
+	if err == nil {
+		draft.Theme = &t
+	}
+
+	return draft, nil
+}
+
+func sameSnapshot(a, b published_profile_repository.PublishedProfile) bool {
+	if a.Name != b.Name || a.Bio != b.Bio || a.ProfilePicture != b.ProfilePicture || a.BannerPicture != b.BannerPicture || !sameUTM(a.UTM, b.UTM) {
+		return false
+	}
+
+	if a.Theme == nil || b.Theme == nil {
+		return a.Theme == b.Theme
+	}
+
+	encodedA, errA := json.Marshal(a.Theme)
+	encodedB, errB := json.Marshal(b.Theme)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+func sameUTM(a, b utm.UTM) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+func toSnapshot(profile published_profile_repository.PublishedProfile, userLinks []links_repository.Link) revision.Snapshot {
+	tags := profile.UTM
+	snapshot := revision.Snapshot{
+		Name:           profile.Name,
+		Bio:            profile.Bio,
+		ProfilePicture: profile.ProfilePicture,
+		BannerPicture:  profile.BannerPicture,
+		Theme:          profile.Theme,
+		UTM:            &tags,
+		Links:          make([]revision.Link, 0, len(userLinks)),
+	}
+
+	for _, link := range userLinks {
+		snapshot.Links = append(snapshot.Links, revision.Link{
+			ID:           link.ID,
+			URL:          link.Url,
+			Title:        link.Title,
+			Description:  link.Description,
+			Slug:         link.Slug,
+			ShortCode:    link.ShortCode,
+			Platform:     link.Platform,
+			Handle:       link.Handle,
+			Sensitive:    link.Sensitive,
+			PasswordHash: link.PasswordHash,
+			UTM:          link.UTM,
+			CreatedAt:    link.CreatedAt,
+
+			ThumbnailMediaID: link.ThumbnailMediaID,
+			Thumbnail:        link.Thumbnail,
+		})
+	}
+
+	return snapshot
+}
+
+func toGetRevision(rev revision_repository.Revision) revision.GetRevision {
+	return revision.GetRevision{
+		ID:        rev.ID,
+		Reason:    rev.Reason,
+		CreatedAt: rev.CreatedAt,
+	}
+}
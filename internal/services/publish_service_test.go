@@ -0,0 +1,257 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/links"
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/revision"
+	"github.com/theNixagen/linker/internal/domain/utm"
+	"github.com/theNixagen/linker/internal/repositories/cache_repository"
+	"github.com/theNixagen/linker/internal/repositories/collaborator_repository"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+)
+
+type publishTestServices struct {
+	publish *PublishService
+	pages   *PageService
+	links   *LinksService
+	cache   *cache_repository.InMemoryCacheRepository
+}
+
+func newTestPublishService(t *testing.T) publishTestServices {
+	pr := page_repository.NewInMemoryPageRepository()
+	lr := links_repository.NewInMemoryLinksRepository()
+	cr := cache_repository.NewInMemoryCacheRepository()
+	tr := theme_repository.NewInMemoryThemeRepository()
+	pub := published_profile_repository.NewInMemoryPublishedProfileRepository(lr)
+	rr := revision_repository.NewInMemoryRevisionRepository(pr, tr, lr, pub)
+	pr.CreatePage(t.Context(), page_repository.Page{
+		ID:        1,
+		AccountID: 1,
+		Handle:    "johndoe",
+		Name:      "John Doe",
+	})
+
+	return publishTestServices{
+		publish: NewPublishService(2, pr, lr, tr, pub, rr, cr),
+		pages:   NewPageService(pr, pub, collaborator_repository.NewInMemoryCollaboratorRepository()),
+		links:   NewLinksService("test_unlock", pr, lr, pub, media_repository.NewInMemoryMediaRepository(pr, tr, pub, rr), cr),
+		cache:   cr,
+	}
+}
+
+func TestPublishService_Publish(t *testing.T) {
+	s := newTestPublishService(t)
+
+	s.pages.UpdateBio(t.Context(), "johndoe", "first bio")
+	s.links.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com", Title: "Example"})
+
+	if _, err := s.pages.GetPublishedPage(t.Context(), "johndoe"); !errors.Is(err, page_repository.ErrPageNotFound) {
+		t.Fatalf("expected unpublished profile to be hidden, got %v", err)
+	}
+
+	preview, err := s.publish.Preview(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !preview.HasUnpublishedChanges || preview.Page.Bio != "first bio" || len(preview.Links) != 1 {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+
+	if _, err := s.publish.Publish(t.Context(), "johndoe"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	s.pages.UpdateBio(t.Context(), "johndoe", "draft bio")
+	s.links.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com/draft", Title: "Draft"})
+
+	profile, err := s.pages.GetPublishedPage(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if profile.Bio != "first bio" || profile.PublishedAt == nil || profile.Theme == nil {
+		t.Fatalf("expected published snapshot, got %+v", profile)
+	}
+
+	published, err := s.links.GetPublishedLinks(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(published) != 1 || published[0].Title != "Example" {
+		t.Fatalf("expected only the published link, got %+v", published)
+	}
+
+	preview, _ = s.publish.Preview(t.Context(), "johndoe")
+	if !preview.HasUnpublishedChanges || preview.Page.Bio != "draft bio" || len(preview.Links) != 2 {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+
+	s.publish.Publish(t.Context(), "johndoe")
+
+	preview, _ = s.publish.Preview(t.Context(), "johndoe")
+	if preview.HasUnpublishedChanges {
+		t.Fatalf("expected no unpublished changes after publishing")
+	}
+}
+
+func TestPublishService_PublishUTM(t *testing.T) {
+	s := newTestPublishService(t)
+
+	created, _ := s.links.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com", Title: "Example"})
+	s.publish.Publish(t.Context(), "johndoe")
+	s.links.ResolveShortCode(t.Context(), created.ShortCode)
+
+	s.links.UpdateDefaultUTM(t.Context(), "johndoe", utm.UTM{Source: "linker"})
+	s.links.UpdateLinkUTM(t.Context(), "johndoe", created.ID, utm.UTM{Campaign: "launch"})
+
+	preview, _ := s.publish.Preview(t.Context(), "johndoe")
+	if !preview.HasUnpublishedChanges {
+		t.Fatalf("expected UTM changes to be unpublished changes")
+	}
+
+	published, _ := s.links.GetPublishedLinks(t.Context(), "johndoe")
+	if published[0].URL != "https://example.com" {
+		t.Fatalf("expected draft tags not to be served, got %q", published[0].URL)
+	}
+
+	s.publish.Publish(t.Context(), "johndoe")
+
+	want := "https://example.com?utm_source=linker&utm_campaign=launch"
+	resolved, err := s.links.ResolveShortCode(t.Context(), created.ShortCode)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resolved.URL != want {
+		t.Fatalf("expected %q, got %q", want, resolved.URL)
+	}
+}
+
+func TestPublishService_UpdateVisibility(t *testing.T) {
+	s := newTestPublishService(t)
+
+	created, _ := s.links.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com", Title: "Example"})
+	s.publish.Publish(t.Context(), "johndoe")
+
+	if _, err := s.links.ResolveShortCode(t.Context(), created.ShortCode); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := s.publish.UpdateVisibility(t.Context(), "johndoe", page.VisibilityUnlisted); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	profile, err := s.pages.GetPublishedPage(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected unlisted profile to be served, got %v", err)
+	}
+	if profile.Visibility != page.VisibilityUnlisted {
+		t.Fatalf("expected unlisted visibility, got %v", profile.Visibility)
+	}
+
+	s.links.ResolveShortCode(t.Context(), created.ShortCode)
+
+	if err := s.publish.UpdateVisibility(t.Context(), "johndoe", page.VisibilityPrivate); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if cached, _ := s.cache.Get(t.Context(), "link:code:"+created.ShortCode); cached != "" {
+		t.Fatalf("expected cached resolution to be dropped")
+	}
+
+	if _, err := s.pages.GetPublishedPage(t.Context(), "johndoe"); !errors.Is(err, page_repository.ErrPageNotFound) {
+		t.Fatalf("expected private profile to be hidden, got %v", err)
+	}
+
+	if _, err := s.links.ResolveShortCode(t.Context(), created.ShortCode); !errors.Is(err, links_repository.ErrLinkNotFound) {
+		t.Fatalf("expected links of private profiles not to resolve, got %v", err)
+	}
+
+	if _, err := s.links.GetPublishedLinks(t.Context(), "johndoe"); !errors.Is(err, page_repository.ErrPageNotFound) {
+		t.Fatalf("expected links of private profiles to be hidden, got %v", err)
+	}
+}
+
+func TestPublishService_Revisions(t *testing.T) {
+	s := newTestPublishService(t)
+
+	s.pages.UpdateBio(t.Context(), "johndoe", "v1")
+	s.publish.Publish(t.Context(), "johndoe")
+
+	s.pages.UpdateBio(t.Context(), "johndoe", "v2")
+	s.links.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com", Title: "Example"})
+	s.publish.Publish(t.Context(), "johndoe")
+
+	s.pages.UpdateBio(t.Context(), "johndoe", "v3")
+	broken, _ := s.links.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com/broken", Title: "Broken"})
+	s.publish.Publish(t.Context(), "johndoe")
+
+	revisions, err := s.publish.ListRevisions(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected revisions to be limited to 2, got %d", len(revisions))
+	}
+	latest, previous := revisions[0], revisions[1]
+
+	diff, err := s.publish.DiffRevisions(t.Context(), "johndoe", previous.ID, latest.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(diff.Changes) != 2 || diff.Changes[0].Field != "bio" || diff.Changes[1].Field != "links[2]" {
+		t.Fatalf("unexpected diff %+v", diff.Changes)
+	}
+
+	restored, err := s.publish.RestoreRevision(t.Context(), "johndoe", previous.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if restored.Reason != revision.ReasonRestore {
+		t.Fatalf("expected restore revision, got %v", restored.Reason)
+	}
+
+	profile, _ := s.pages.GetPublishedPage(t.Context(), "johndoe")
+	if profile.Bio != "v2" {
+		t.Fatalf("expected restored public bio, got %v", profile.Bio)
+	}
+
+	preview, _ := s.publish.Preview(t.Context(), "johndoe")
+	if preview.Page.Bio != "v2" || !preview.HasUnpublishedChanges {
+		t.Fatalf("expected restored draft with the newer link unpublished, got %+v", preview)
+	}
+
+	if _, err := s.links.ResolveShortCode(t.Context(), broken.ShortCode); !errors.Is(err, links_repository.ErrLinkNotFound) {
+		t.Fatalf("expected link added after the revision not to resolve, got %v", err)
+	}
+
+	if _, err := s.publish.RestoreRevision(t.Context(), "johndoe", 99); !errors.Is(err, revision_repository.ErrRevisionNotFound) {
+		t.Fatalf("expected ErrRevisionNotFound, got %v", err)
+	}
+}
+
+func TestPublishService_RestoreConflict(t *testing.T) {
+	s := newTestPublishService(t)
+
+	s.links.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com", Title: "Shop", Slug: "shop"})
+	s.publish.Publish(t.Context(), "johndoe")
+
+	revisions, _ := s.publish.ListRevisions(t.Context(), "johndoe")
+	if len(revisions) != 1 {
+		t.Fatalf("expected publish to record a revision, got %d", len(revisions))
+	}
+
+	lr := s.links.LinksRepository.(*links_repository.InMemoryLinksRepository)
+	lr.Links[0].Slug = "old-shop"
+	s.links.CreateLink(t.Context(), "johndoe", links.CreateLink{URL: "https://example.com/new", Title: "New shop", Slug: "shop"})
+
+	if _, err := s.publish.RestoreRevision(t.Context(), "johndoe", revisions[0].ID); !errors.Is(err, revision_repository.ErrRestoreConflict) {
+		t.Fatalf("expected ErrRestoreConflict, got %v", err)
+	}
+}
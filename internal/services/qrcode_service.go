@@ -0,0 +1,221 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"net/url"
+
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/domain/qrcode"
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"rsc.io/qr"
+)
+
+const (
+	qrQuietZone = 4
+	// qrLogoRatio is the share of the code side covered by the logo, small
+	// enough to be recovered by the H error correction level.
+	qrLogoRatio = 0.22
+)
+
+var qrLevels = map[string]qr.Level{
+	"L": qr.L,
+	"M": qr.M,
+	"Q": qr.Q,
+	"H": qr.H,
+}
+
+type QRCodeService struct {
+	PageRepository  page_repository.PageRepository
+	LinksRepository links_repository.LinksRepository
+	FileService     *FileService
+	publicURL       string
+}
+
+func NewQRCodeService(publicURL string, pageRepository page_repository.PageRepository, linksRepository links_repository.LinksRepository, fileService *FileService) *QRCodeService {
+	return &QRCodeService{
+		PageRepository:  pageRepository,
+		LinksRepository: linksRepository,
+		FileService:     fileService,
+		publicURL:       publicURL,
+	}
+}
+
+func (qs *QRCodeService) ProfileQRCode(ctx context.Context, handle string, opts qrcode.Options) ([]byte, error) {
+	owner, err := qs.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner.Visibility == page.VisibilityPrivate {
+		return nil, page_repository.ErrPageNotFound
+	}
+
+	target := qs.trackedURL(fmt.Sprintf("/%s", url.PathEscape(owner.Handle)))
+	return RenderQRCode(target, opts, qs.logo(ctx, owner, opts))
+}
+
+// LinkQRCode points to the short link so scans are counted and gates are
+// still enforced. Only links of the page pageID are found, since the code
+// reveals the short code of drafts and locked links.
+func (qs *QRCodeService) LinkQRCode(ctx context.Context, pageID, linkID int32, opts qrcode.Options) ([]byte, error) {
+	link, err := qs.LinksRepository.FindLinkByID(ctx, linkID)
+	if err != nil {
+		return nil, err
+	}
+	if link.PageID != pageID {
+		return nil, links_repository.ErrLinkNotFound
+	}
+
+	owner, err := qs.PageRepository.GetPageByID(ctx, link.PageID)
+	if err != nil {
+		return nil, err
+	}
+
+	target := qs.trackedURL(fmt.Sprintf("/s/%s", link.ShortCode))
+	return RenderQRCode(target, opts, qs.logo(ctx, owner, opts))
+}
+
+func (qs *QRCodeService) trackedURL(path string) string {
+	return fmt.Sprintf("%s%s?source=%s", qs.publicURL, path, qrcode.Source)
+}
+
+func (qs *QRCodeService) logo(ctx context.Context, owner page_repository.Page, opts qrcode.Options) image.Image {
+	if !opts.Logo || owner.ProfilePicture == "" {
+		return nil
+	}
+
+	logo, err := qs.FileService.GetImage(ctx, owner.ProfilePicture)
+	if err != nil {
+		log.Printf("could not load qr code logo for %s: %v", owner.Handle, err)
+		return nil
+	}
+	return logo
+}
+
+// RenderQRCode encodes content with the given options. When a logo is given
+// the error correction is raised to H so the covered modules can be recovered.
+func RenderQRCode(content string, opts qrcode.Options, logo image.Image) ([]byte, error) {
+	level, ok := qrLevels[opts.Level]
+	if !ok {
+		level = qr.M
+	}
+	if logo != nil {
+		level = qr.H
+	}
+
+	code, err := qr.Encode(content, level)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Format == qrcode.FormatSVG {
+		return renderQRCodeSVG(code, opts, logo)
+	}
+	return renderQRCodePNG(code, opts, logo)
+}
+
+func renderQRCodePNG(code *qr.Code, opts qrcode.Options, logo image.Image) ([]byte, error) {
+	fg, err := qrColor(opts.Foreground)
+	if err != nil {
+		return nil, err
+	}
+	bg, err := qrColor(opts.Background)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := code.Size + 2*qrQuietZone
+	size := max(opts.Size, modules)
+	scale := size / modules
+	offset := (size - modules*scale) / 2
+
+	canvas := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(canvas, canvas.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+
+	dark := image.NewUniform(fg)
+	for y := range code.Size {
+		for x := range code.Size {
+			if !code.Black(x, y) {
+				continue
+			}
+			px := offset + (x+qrQuietZone)*scale
+			py := offset + (y+qrQuietZone)*scale
+			draw.Draw(canvas, image.Rect(px, py, px+scale, py+scale), dark, image.Point{}, draw.Src)
+		}
+	}
+
+	if logo != nil {
+		side := int(float64(code.Size*scale) * qrLogoRatio)
+		center := size / 2
+		padding := max(2, scale)
+		backdrop := image.Rect(center-side/2-padding, center-side/2-padding, center+side/2+padding, center+side/2+padding)
+		draw.DrawMask(canvas, backdrop, image.NewUniform(bg), image.Point{}, circleMask(backdrop.Dx()), image.Point{}, draw.Over)
+		drawAvatar(canvas, image.Rect(center-side/2, center-side/2, center+side/2, center+side/2), logo)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderQRCodeSVG(code *qr.Code, opts qrcode.Options, logo image.Image) ([]byte, error) {
+	if _, err := qrColor(opts.Foreground); err != nil {
+		return nil, err
+	}
+	if _, err := qrColor(opts.Background); err != nil {
+		return nil, err
+	}
+
+	modules := code.Size + 2*qrQuietZone
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, opts.Size, opts.Size, modules, modules)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"/>`, opts.Background)
+	fmt.Fprintf(&buf, `<path fill="%s" d="`, opts.Foreground)
+	for y := range code.Size {
+		for x := range code.Size {
+			if code.Black(x, y) {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x+qrQuietZone, y+qrQuietZone)
+			}
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if logo != nil {
+		side := float64(code.Size) * qrLogoRatio
+		center := float64(modules) / 2
+		fmt.Fprintf(&buf, `<circle cx="%g" cy="%g" r="%g" fill="%s"/>`, center, center, side/2+0.5, opts.Background)
+
+		rendered := image.NewRGBA(image.Rect(0, 0, 256, 256))
+		drawAvatar(rendered, rendered.Bounds(), logo)
+		var logoPNG bytes.Buffer
+		if err := png.Encode(&logoPNG, rendered); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, `<image x="%g" y="%g" width="%g" height="%g" href="data:image/png;base64,%s"/>`,
+			center-side/2, center-side/2, side, side, base64.StdEncoding.EncodeToString(logoPNG.Bytes()))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+func qrColor(hex string) (color.RGBA, error) {
+	c, err := theme.ParseColor(hex)
+	if err != nil {
+		return color.RGBA{}, err
+	}
+	return color.RGBA{R: c.R, G: c.G, B: c.B, A: 0xff}, nil
+}
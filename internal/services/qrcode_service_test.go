@@ -0,0 +1,134 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/qrcode"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+)
+
+func TestRenderQRCode_PNG(t *testing.T) {
+	opts := qrcode.DefaultOptions()
+	opts.Size = 300
+	opts.Foreground = "#112233"
+	opts.Background = "#fafafa"
+
+	data, err := RenderQRCode("https://linker.app/johndoe?source=qr", opts, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a valid png, got %v", err)
+	}
+
+	if img.Bounds().Dx() != 300 || img.Bounds().Dy() != 300 {
+		t.Fatalf("expected 300x300, got %v", img.Bounds())
+	}
+
+	r, g, b, _ := img.At(0, 0).RGBA()
+	if r>>8 != 0xfa || g>>8 != 0xfa || b>>8 != 0xfa {
+		t.Fatalf("expected background color in the quiet zone")
+	}
+
+	found := false
+	for x := range 300 {
+		r, g, b, _ := img.At(x, x).RGBA()
+		if r>>8 == 0x11 && g>>8 == 0x22 && b>>8 == 0x33 {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Fatalf("expected foreground modules to be drawn")
+	}
+}
+
+func TestRenderQRCode_SVG(t *testing.T) {
+	opts := qrcode.DefaultOptions()
+	opts.Format = qrcode.FormatSVG
+
+	data, err := RenderQRCode("https://linker.app/s/abc1234?source=qr", opts, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	svg := string(data)
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>") {
+		t.Fatalf("expected an svg document, got %v", svg)
+	}
+
+	if !strings.Contains(svg, `fill="#000000"`) || !strings.Contains(svg, `width="512"`) {
+		t.Fatalf("expected options to be applied, got %v", svg)
+	}
+}
+
+func TestRenderQRCode_Logo(t *testing.T) {
+	logo := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			logo.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+		}
+	}
+
+	for _, format := range []string{qrcode.FormatPNG, qrcode.FormatSVG} {
+		opts := qrcode.DefaultOptions()
+		opts.Format = format
+		opts.Logo = true
+
+		data, err := RenderQRCode("https://linker.app/johndoe?source=qr", opts, logo)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if format == qrcode.FormatSVG {
+			if !strings.Contains(string(data), "data:image/png;base64,") {
+				t.Fatalf("expected logo to be embedded in svg")
+			}
+			continue
+		}
+
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("expected a valid png, got %v", err)
+		}
+		r, g, b, _ := img.At(256, 256).RGBA()
+		if r>>8 != 0xff || g != 0 || b != 0 {
+			t.Fatalf("expected logo at the center")
+		}
+	}
+}
+
+func TestQRCodeService_LinkQRCode_NotFound(t *testing.T) {
+	lr := links_repository.NewInMemoryLinksRepository()
+	qs := NewQRCodeService("https://linker.app", page_repository.NewInMemoryPageRepository(), lr, nil)
+
+	if _, err := qs.LinkQRCode(t.Context(), 1, 42, qrcode.DefaultOptions()); !errors.Is(err, links_repository.ErrLinkNotFound) {
+		t.Fatalf("expected ErrLinkNotFound, got %v", err)
+	}
+
+	id, _ := lr.CreateLink(t.Context(), links_repository.Link{PageID: 2, Url: "https://example.com", ShortCode: "abc1234"})
+	if _, err := qs.LinkQRCode(t.Context(), 1, id, qrcode.DefaultOptions()); !errors.Is(err, links_repository.ErrLinkNotFound) {
+		t.Fatalf("expected links of other pages not to be found, got %v", err)
+	}
+
+	if _, err := qs.ProfileQRCode(t.Context(), "nobody", qrcode.DefaultOptions()); !errors.Is(err, page_repository.ErrPageNotFound) {
+		t.Fatalf("expected ErrPageNotFound, got %v", err)
+	}
+}
+
+func TestQRCodeService_TrackedURL(t *testing.T) {
+	qs := NewQRCodeService("https://linker.app", nil, nil, nil)
+
+	if got := qs.trackedURL("/s/abc1234"); got != "https://linker.app/s/abc1234?source=qr" {
+		t.Fatalf("unexpected tracked url %v", got)
+	}
+}
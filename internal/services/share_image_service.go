@@ -0,0 +1,375 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/theNixagen/linker/internal/domain/page"
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	ShareImageWidth  = 1200
+	ShareImageHeight = 630
+
+	// shareCardVersion is part of the cache key, bump it when the layout
+	// changes so every card is rendered again.
+	shareCardVersion = "1"
+	shareAvatarSize  = 280
+	shareTextX       = 440
+)
+
+var (
+	shareGradientFrom = color.RGBA{R: 0x4f, G: 0x46, B: 0xe5, A: 0xff}
+	shareGradientTo   = color.RGBA{R: 0x93, G: 0x33, B: 0xea, A: 0xff}
+	shareTextColor    = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	shareMutedColor   = color.RGBA{R: 0xe0, G: 0xe7, B: 0xff, A: 0xff}
+)
+
+// ShareCard holds what is printed on a profile preview card.
+type ShareCard struct {
+	Name      string
+	Handle    string
+	LinkCount int
+	Avatar    image.Image
+}
+
+var ErrShareImageNotFound = errors.New("share image not found")
+
+type ShareImageService struct {
+	PageRepository             page_repository.PageRepository
+	LinksRepository            links_repository.LinksRepository
+	PublishedProfileRepository published_profile_repository.PublishedProfileRepository
+	FileService                *FileService
+}
+
+func NewShareImageService(pageRepository page_repository.PageRepository, linksRepository links_repository.LinksRepository, publishedProfileRepository published_profile_repository.PublishedProfileRepository, fileService *FileService) *ShareImageService {
+	return &ShareImageService{
+		PageRepository:             pageRepository,
+		LinksRepository:            linksRepository,
+		PublishedProfileRepository: publishedProfileRepository,
+		FileService:                fileService,
+	}
+}
+
+// GetShareImage returns the bucket key of the share card of the published
+// profile. Cards are only rendered by RenderShareImage when a profile is
+// published, so visitors never trigger a render; ErrShareImageNotFound is
+// returned until then.
+func (ss *ShareImageService) GetShareImage(ctx context.Context, handle string) (string, error) {
+	owner, linkCount, err := ss.publishedCard(ctx, handle)
+	if err != nil {
+		return "", err
+	}
+
+	key := shareImageKey(owner, linkCount)
+	exists, err := ss.FileService.ObjectExists(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", ErrShareImageNotFound
+	}
+	return key, nil
+}
+
+// RenderShareImage renders and stores the share card of the published
+// profile, unless it is stored already, and removes the older ones.
+func (ss *ShareImageService) RenderShareImage(ctx context.Context, handle string) (string, error) {
+	owner, linkCount, err := ss.publishedCard(ctx, handle)
+	if err != nil {
+		return "", err
+	}
+
+	key := shareImageKey(owner, linkCount)
+	exists, err := ss.FileService.ObjectExists(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return key, nil
+	}
+
+	card := ShareCard{
+		Name:      owner.Name,
+		Handle:    owner.Handle,
+		LinkCount: linkCount,
+	}
+	if owner.ProfilePicture != "" {
+		avatar, err := ss.FileService.GetImage(ctx, owner.ProfilePicture)
+		if err != nil {
+			log.Printf("could not load avatar for share image of %s: %v", owner.Handle, err)
+		}
+		card.Avatar = avatar
+	}
+
+	data, err := RenderShareCard(card)
+	if err != nil {
+		return "", err
+	}
+
+	if err := ss.FileService.PutObject(ctx, key, bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		return "", err
+	}
+
+	if err := ss.FileService.RemoveObjectsWithPrefix(ctx, shareImagePrefix(owner.Handle), key); err != nil {
+		log.Printf("could not remove old share images of %s: %v", owner.Handle, err)
+	}
+
+	return key, nil
+}
+
+// publishedCard returns the page with the name and picture of its published
+// profile, along with its number of published links.
+func (ss *ShareImageService) publishedCard(ctx context.Context, handle string) (page_repository.Page, int, error) {
+	owner, err := ss.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return page_repository.Page{}, 0, err
+	}
+
+	if owner.Visibility == page.VisibilityPrivate {
+		return page_repository.Page{}, 0, page_repository.ErrPageNotFound
+	}
+
+	published, err := ss.PublishedProfileRepository.GetPublishedProfile(ctx, owner.ID)
+	if err != nil {
+		if errors.Is(err, published_profile_repository.ErrProfileNotPublished) {
+			return page_repository.Page{}, 0, page_repository.ErrPageNotFound
+		}
+		return page_repository.Page{}, 0, err
+	}
+
+	userLinks, err := ss.LinksRepository.FindPublishedLinksFromAPage(ctx, owner.ID)
+	if err != nil && !errors.Is(err, links_repository.ErrLinksNotFound) {
+		return page_repository.Page{}, 0, err
+	}
+
+	owner.Name = published.Name
+	owner.ProfilePicture = published.ProfilePicture
+	return owner, len(userLinks), nil
+}
+
+func shareImagePrefix(handle string) string {
+	return fmt.Sprintf("share/%s/", handle)
+}
+
+// shareImageKey is derived from everything printed on the card, so any
+// profile change produces a new key.
+func shareImageKey(owner page_repository.Page, linkCount int) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d\x00%s", shareCardVersion, owner.Name, owner.Handle, linkCount, owner.ProfilePicture)
+	return shareImagePrefix(owner.Handle) + hex.EncodeToString(h.Sum(nil)[:16]) + ".png"
+}
+
+type shareFontSet struct {
+	bold    *opentype.Font
+	regular *opentype.Font
+}
+
+var loadShareFonts = sync.OnceValues(func() (shareFontSet, error) {
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return shareFontSet{}, err
+	}
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return shareFontSet{}, err
+	}
+	return shareFontSet{bold: bold, regular: regular}, nil
+})
+
+// RenderShareCard draws the card as a PNG sized for OpenGraph previews.
+func RenderShareCard(card ShareCard) ([]byte, error) {
+	fonts, err := loadShareFonts()
+	if err != nil {
+		return nil, err
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, ShareImageWidth, ShareImageHeight))
+	drawShareBackground(canvas)
+
+	avatarRect := image.Rect(100, (ShareImageHeight-shareAvatarSize)/2, 100+shareAvatarSize, (ShareImageHeight+shareAvatarSize)/2)
+	if card.Avatar != nil {
+		drawAvatar(canvas, avatarRect, card.Avatar)
+	} else {
+		if err := drawInitial(canvas, avatarRect, fonts.bold, card); err != nil {
+			return nil, err
+		}
+	}
+
+	maxWidth := ShareImageWidth - shareTextX - 80
+	lines := []struct {
+		font  *opentype.Font
+		size  float64
+		y     int
+		color color.Color
+		text  string
+	}{
+		{fonts.bold, 68, 270, shareTextColor, card.Name},
+		{fonts.regular, 42, 340, shareMutedColor, "@" + card.Handle},
+		{fonts.regular, 34, 410, shareMutedColor, linkCountLabel(card.LinkCount)},
+	}
+	for _, line := range lines {
+		if err := drawText(canvas, line.font, line.size, shareTextX, line.y, maxWidth, line.color, line.text); err != nil {
+			return nil, err
+		}
+	}
+
+	brand := "linker"
+	face, err := opentype.NewFace(fonts.bold, &opentype.FaceOptions{Size: 32, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+	brandWidth := font.MeasureString(face, brand).Ceil()
+	if err := drawText(canvas, fonts.bold, 32, ShareImageWidth-60-brandWidth, ShareImageHeight-50, brandWidth, shareTextColor, brand); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func linkCountLabel(count int) string {
+	if count == 1 {
+		return "1 link"
+	}
+	return fmt.Sprintf("%d links", count)
+}
+
+func drawShareBackground(canvas *image.RGBA) {
+	bounds := canvas.Bounds()
+	span := bounds.Dx() + bounds.Dy()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			canvas.SetRGBA(x, y, lerpRGBA(shareGradientFrom, shareGradientTo, float64(x+y)/float64(span)))
+		}
+	}
+}
+
+func lerpRGBA(from, to color.RGBA, t float64) color.RGBA {
+	lerp := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*t)
+	}
+	return color.RGBA{R: lerp(from.R, to.R), G: lerp(from.G, to.G), B: lerp(from.B, to.B), A: 0xff}
+}
+
+// drawAvatar scales the center square of src into a circle.
+func drawAvatar(canvas *image.RGBA, rect image.Rectangle, src image.Image) {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(bounds.Min).Add(image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2))
+
+	scaled := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	xdraw.CatmullRom.Scale(scaled, scaled.Bounds(), src, crop, draw.Src, nil)
+
+	draw.DrawMask(canvas, rect, scaled, image.Point{}, circleMask(rect.Dx()), image.Point{}, draw.Over)
+}
+
+func drawInitial(canvas *image.RGBA, rect image.Rectangle, f *opentype.Font, card ShareCard) error {
+	fill := image.NewUniform(color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x40})
+	draw.DrawMask(canvas, rect, fill, image.Point{}, circleMask(rect.Dx()), image.Point{}, draw.Over)
+
+	initial := firstInitial(card.Name, card.Handle)
+	if initial == "" {
+		return nil
+	}
+
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: 140, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	width := font.MeasureString(face, initial).Ceil()
+	metrics := face.Metrics()
+	x := rect.Min.X + (rect.Dx()-width)/2
+	y := rect.Min.Y + (rect.Dy()+metrics.CapHeight.Ceil())/2
+	return drawText(canvas, f, 140, x, y, rect.Dx(), shareTextColor, initial)
+}
+
+func firstInitial(values ...string) string {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if r, _ := utf8.DecodeRuneInString(value); r != utf8.RuneError {
+			return string(unicode.ToUpper(r))
+		}
+	}
+	return ""
+}
+
+// circleMask returns an anti-aliased circle of the given diameter.
+func circleMask(diameter int) *image.Alpha {
+	mask := image.NewAlpha(image.Rect(0, 0, diameter, diameter))
+	radius := float64(diameter) / 2
+	for y := range diameter {
+		for x := range diameter {
+			dx := float64(x) + 0.5 - radius
+			dy := float64(y) + 0.5 - radius
+			coverage := radius - math.Sqrt(dx*dx+dy*dy) + 0.5
+			coverage = max(0, min(1, coverage))
+			mask.SetAlpha(x, y, color.Alpha{A: uint8(coverage * 0xff)})
+		}
+	}
+	return mask
+}
+
+func drawText(canvas *image.RGBA, f *opentype.Font, size float64, x, y, maxWidth int, c color.Color, text string) error {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	drawer := font.Drawer{
+		Dst:  canvas,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(fitText(face, text, maxWidth))
+	return nil
+}
+
+// fitText cuts text with an ellipsis so it is at most maxWidth pixels wide.
+func fitText(face font.Face, text string, maxWidth int) string {
+	limit := fixed.I(maxWidth)
+	if font.MeasureString(face, text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if font.MeasureString(face, candidate) <= limit {
+			return candidate
+		}
+	}
+	return ""
+}
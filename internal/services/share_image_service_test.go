@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+)
+
+func TestRenderShareCard(t *testing.T) {
+	avatar := image.NewRGBA(image.Rect(0, 0, 400, 300))
+	for y := range 300 {
+		for x := range 400 {
+			avatar.Set(x, y, color.RGBA{R: 0xff, A: 0xff})
+		}
+	}
+
+	tests := []struct {
+		name string
+		card ShareCard
+	}{
+		{"with avatar", ShareCard{Name: "John Doe", Handle: "johndoe", LinkCount: 3, Avatar: avatar}},
+		{"without avatar", ShareCard{Name: "John Doe", Handle: "johndoe", LinkCount: 1}},
+		{"long name", ShareCard{Name: strings.Repeat("Very Long Name ", 20), Handle: "johndoe"}},
+		{"empty", ShareCard{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := RenderShareCard(tt.card)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("expected a valid png, got %v", err)
+			}
+
+			if img.Bounds().Dx() != ShareImageWidth || img.Bounds().Dy() != ShareImageHeight {
+				t.Fatalf("expected %dx%d, got %v", ShareImageWidth, ShareImageHeight, img.Bounds())
+			}
+		})
+	}
+}
+
+func TestShareImageKey(t *testing.T) {
+	p := page_repository.Page{Name: "John Doe", Handle: "johndoe", ProfilePicture: "avatar.png"}
+	key := shareImageKey(p, 2)
+
+	if !strings.HasPrefix(key, "share/johndoe/") || !strings.HasSuffix(key, ".png") {
+		t.Fatalf("unexpected key %v", key)
+	}
+
+	if shareImageKey(p, 2) != key {
+		t.Fatalf("expected key to be deterministic")
+	}
+
+	changed := []string{
+		shareImageKey(p, 3),
+		shareImageKey(page_repository.Page{Name: "Jane Doe", Handle: "johndoe", ProfilePicture: "avatar.png"}, 2),
+		shareImageKey(page_repository.Page{Name: "John Doe", Handle: "johndoe", ProfilePicture: "new.png"}, 2),
+	}
+	for _, other := range changed {
+		if other == key {
+			t.Fatalf("expected key to change with the profile")
+		}
+	}
+}
+
+func TestShareImageService_RendersOnlyOnPublish(t *testing.T) {
+	pr := page_repository.NewInMemoryPageRepository()
+	lr := links_repository.NewInMemoryLinksRepository()
+	pub := published_profile_repository.NewInMemoryPublishedProfileRepository(lr)
+	storage := newTestStorage(t)
+	ss := NewShareImageService(pr, lr, pub, NewFileService(storage, 0, 0))
+	pr.CreatePage(t.Context(), page_repository.Page{ID: 1, AccountID: 1, Handle: "johndoe", Name: "John Doe"})
+
+	if _, err := ss.GetShareImage(t.Context(), "johndoe"); !errors.Is(err, page_repository.ErrPageNotFound) {
+		t.Fatalf("expected unpublished pages to have no card, got %v", err)
+	}
+
+	pub.Publish(t.Context(), published_profile_repository.PublishedProfile{PageID: 1, Name: "John Doe"})
+	if _, err := ss.GetShareImage(t.Context(), "johndoe"); !errors.Is(err, ErrShareImageNotFound) {
+		t.Fatalf("expected ErrShareImageNotFound before rendering, got %v", err)
+	}
+
+	key, err := ss.RenderShareImage(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got, err := ss.GetShareImage(t.Context(), "johndoe")
+	if err != nil || got != key {
+		t.Fatalf("expected the rendered card %q, got %q (%v)", key, got, err)
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/theNixagen/linker/internal/domain/imaging"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/repositories/reference_repository"
+)
+
+const (
+	// DefaultGCGrace keeps objects younger than a day, which covers uploads
+	// whose page update has not been committed yet.
+	DefaultGCGrace = 24 * time.Hour
+	// DefaultQuarantineRetention keeps flagged uploads for a month of review.
+	DefaultQuarantineRetention = 30 * 24 * time.Hour
+)
+
+// gcStats adds up what the passes of this process scanned, removed and
+// reclaimed. It is published through expvar as "storage_gc"; dry runs only
+// count as passes.
+var gcStats = expvar.NewMap("storage_gc")
+
+// GCReport describes one garbage collection pass. In a dry run nothing is
+// deleted and Removed and ReclaimedBytes count what would have been.
+type GCReport struct {
+	DryRun         bool
+	Scanned        int
+	Removed        int
+	ReclaimedBytes int64
+}
+
+// StorageGCService deletes objects that no page, theme, revision or pending
+// upload references anymore, such as pictures of deleted pages or objects
+// left behind by failed updates. Quarantined uploads are deleted once they
+// are older than QuarantineRetention.
+type StorageGCService struct {
+	Grace               time.Duration
+	QuarantineRetention time.Duration
+	DryRun              bool
+	ReferenceRepository reference_repository.ReferenceRepository
+	MediaRepository     media_repository.MediaRepository
+	FileService         *FileService
+}
+
+func NewStorageGCService(grace, quarantineRetention time.Duration, dryRun bool, referenceRepository reference_repository.ReferenceRepository, mediaRepository media_repository.MediaRepository, fileService *FileService) *StorageGCService {
+	return &StorageGCService{
+		Grace:               grace,
+		QuarantineRetention: quarantineRetention,
+		DryRun:              dryRun,
+		ReferenceRepository: referenceRepository,
+		MediaRepository:     mediaRepository,
+		FileService:         fileService,
+	}
+}
+
+// CollectGarbage removes every unreferenced object last modified before the
+// grace period. Removed pictures stop counting in the size of the media they
+// were made from. References are read before the bucket is listed, so an
+// object referenced in between is always newer than the grace period.
+func (gs *StorageGCService) CollectGarbage(ctx context.Context) (GCReport, error) {
+	report := GCReport{DryRun: gs.DryRun}
+
+	referenced, prefixes, err := gs.references(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	objects, err := gs.FileService.Storage.List(ctx, "")
+	if err != nil {
+		return report, err
+	}
+
+	now := time.Now()
+	for _, object := range objects {
+		report.Scanned++
+		cutoff := now.Add(-gs.Grace)
+		if strings.HasPrefix(object.Key, quarantinePrefix) {
+			cutoff = now.Add(-gs.QuarantineRetention)
+		}
+		if referenced[object.Key] || !object.LastModified.Before(cutoff) || hasAnyPrefix(object.Key, prefixes) {
+			continue
+		}
+		if source, ok := resizedSource(object.Key); ok && referenced[source] {
+			continue
+		}
+
+		if gs.DryRun {
+			log.Printf("storage gc: would remove %s (%d bytes)", object.Key, object.Size)
+		} else if err := gs.FileService.RemoveObject(ctx, object.Key); err != nil {
+			log.Printf("storage gc: could not remove %s: %v", object.Key, err)
+			continue
+		} else if err := resetPictureSize(ctx, gs.MediaRepository, object.Key); err != nil {
+			log.Printf("storage gc: could not reset the size of %s: %v", object.Key, err)
+		}
+		report.Removed++
+		report.ReclaimedBytes += object.Size
+	}
+
+	gcStats.Add("passes", 1)
+	gcStats.Add("scanned", int64(report.Scanned))
+	if !report.DryRun {
+		gcStats.Add("removed", int64(report.Removed))
+		gcStats.Add("reclaimed_bytes", report.ReclaimedBytes)
+	}
+	return report, nil
+}
+
+// references returns the referenced keys, along with every variant of the
+// pictures, and the prefixes that are never collected: the share cards of
+// existing pages, which are rendered again whenever they are missing.
+func (gs *StorageGCService) references(ctx context.Context) (map[string]bool, []string, error) {
+	keys, err := gs.ReferenceRepository.ListReferencedKeys(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	referenced := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		referenced[key] = true
+		for _, variants := range [][]imaging.Variant{imaging.AvatarVariants, imaging.BannerVariants} {
+			for _, variantKey := range imaging.VariantKeys(key, variants) {
+				referenced[variantKey] = true
+			}
+		}
+	}
+
+	handles, err := gs.ReferenceRepository.ListPageHandles(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prefixes := make([]string, 0, len(handles))
+	for _, handle := range handles {
+		prefixes = append(prefixes, shareImagePrefix(handle))
+	}
+	return referenced, prefixes, nil
+}
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// RunGarbageCollection calls CollectGarbage on every tick until ctx is done
+// and logs what each pass reclaimed.
+func (gs *StorageGCService) RunGarbageCollection(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := gs.CollectGarbage(ctx)
+			if err != nil {
+				log.Printf("could not collect storage garbage: %v", err)
+				continue
+			}
+			log.Printf("storage gc: scanned %d objects, removed %d, reclaimed %d bytes (dry run: %t)", report.Scanned, report.Removed, report.ReclaimedBytes, report.DryRun)
+		}
+	}
+}
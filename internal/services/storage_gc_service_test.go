@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"expvar"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/theNixagen/linker/internal/repositories/links_repository"
+	"github.com/theNixagen/linker/internal/repositories/media_repository"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/published_profile_repository"
+	"github.com/theNixagen/linker/internal/repositories/reference_repository"
+	"github.com/theNixagen/linker/internal/repositories/revision_repository"
+	"github.com/theNixagen/linker/internal/repositories/storage_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+)
+
+func newTestStorageGCService(t *testing.T, grace time.Duration, dryRun bool, keys ...string) (*StorageGCService, *reference_repository.InMemoryReferenceRepository, *storage_repository.InMemoryObjectStorage) {
+	storage := newTestStorage(t)
+	for _, key := range keys {
+		if err := storage.Put(t.Context(), key, bytes.NewReader([]byte("data")), 4, "image/jpeg"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	rr := reference_repository.NewInMemoryReferenceRepository()
+	pr := page_repository.NewInMemoryPageRepository()
+	lr := links_repository.NewInMemoryLinksRepository()
+	pub := published_profile_repository.NewInMemoryPublishedProfileRepository(lr)
+	tr := theme_repository.NewInMemoryThemeRepository()
+	mr := media_repository.NewInMemoryMediaRepository(pr, tr, pub, revision_repository.NewInMemoryRevisionRepository(pr, tr, lr, pub))
+	return NewStorageGCService(grace, DefaultQuarantineRetention, dryRun, rr, mr, NewFileService(storage, 0, 0)), rr, storage
+}
+
+func TestStorageGCService_CollectGarbage(t *testing.T) {
+	gs, rr, storage := newTestStorageGCService(t, 0, false,
+		"pages/1/avatar/abc/sm.jpg",
+		"pages/1/avatar/abc/md.jpg",
+		"pages/1/avatar/abc/lg.jpg",
+		"pages/1/avatar/old/lg.jpg",
+		"resized/pages/1/avatar/abc/lg.jpg/w256.jpg",
+		"resized/pages/1/avatar/old/lg.jpg/w256.jpg",
+		"pages/2/background/def.jpg",
+		"share/johndoe/card.png",
+		"share/removed/card.png",
+		"legacy.png",
+		"uploads/1/pending",
+	)
+	rr.Keys = []string{"pages/1/avatar/abc/lg.jpg", "legacy.png", "uploads/1/pending"}
+	rr.Handles = []string{"johndoe"}
+	kept, _ := gs.MediaRepository.CreateMedia(t.Context(), media_repository.Media{PageID: 1, ObjectKey: "pages/1/media/abc.jpg", AvatarSize: 12})
+	replaced, _ := gs.MediaRepository.CreateMedia(t.Context(), media_repository.Media{PageID: 1, ObjectKey: "pages/1/media/old.jpg", AvatarSize: 4})
+	reclaimed := statValue(gcStats.Get("reclaimed_bytes"))
+
+	report, err := gs.CollectGarbage(t.Context())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Scanned != 11 || report.Removed != 4 || report.ReclaimedBytes != 16 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if got := statValue(gcStats.Get("reclaimed_bytes")) - reclaimed; got != 16 {
+		t.Errorf("expected the pass to add 16 reclaimed bytes to the stats, got %d", got)
+	}
+
+	keys := objectKeys(t, storage, "")
+	for _, key := range []string{"pages/1/avatar/old/lg.jpg", "resized/pages/1/avatar/old/lg.jpg/w256.jpg", "pages/2/background/def.jpg", "share/removed/card.png"} {
+		if slices.Contains(keys, key) {
+			t.Errorf("expected orphan %s to be removed", key)
+		}
+	}
+	if len(keys) != 7 {
+		t.Errorf("expected referenced objects, their variants and resized copies to be kept, got %v", keys)
+	}
+
+	kept, _ = gs.MediaRepository.GetMedia(t.Context(), 1, kept.ID)
+	replaced, _ = gs.MediaRepository.GetMedia(t.Context(), 1, replaced.ID)
+	if kept.AvatarSize != 12 || replaced.AvatarSize != 0 {
+		t.Errorf("expected only the removed avatar to stop counting, got %d and %d", kept.AvatarSize, replaced.AvatarSize)
+	}
+}
+
+func statValue(v expvar.Var) int64 {
+	if v == nil {
+		return 0
+	}
+	return v.(*expvar.Int).Value()
+}
+
+func TestStorageGCService_DryRun(t *testing.T) {
+	gs, _, storage := newTestStorageGCService(t, 0, true, "orphan.png")
+
+	report, err := gs.CollectGarbage(t.Context())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !report.DryRun || report.Removed != 1 || report.ReclaimedBytes != 4 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if keys := objectKeys(t, storage, ""); len(keys) != 1 {
+		t.Errorf("expected dry run to keep every object, got %v", keys)
+	}
+}
+
+func TestStorageGCService_KeepsRecentObjects(t *testing.T) {
+	gs, _, storage := newTestStorageGCService(t, time.Hour, false, "orphan.png")
+
+	report, err := gs.CollectGarbage(t.Context())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if report.Removed != 0 {
+		t.Errorf("expected objects within the grace period to be kept, got %+v", report)
+	}
+	if keys := objectKeys(t, storage, ""); len(keys) != 1 {
+		t.Errorf("expected the object to be kept, got %v", keys)
+	}
+}
+
+func TestStorageGCService_QuarantineRetention(t *testing.T) {
+	gs, _, storage := newTestStorageGCService(t, 0, false, "quarantine/pages/1/avatar/abc")
+
+	if _, err := gs.CollectGarbage(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys := objectKeys(t, storage, quarantinePrefix); len(keys) != 1 {
+		t.Fatalf("expected quarantined objects to be kept for review, got %v", keys)
+	}
+
+	gs.QuarantineRetention = 0
+	if _, err := gs.CollectGarbage(t.Context()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if keys := objectKeys(t, storage, quarantinePrefix); len(keys) != 0 {
+		t.Fatalf("expected quarantined objects past retention to be removed, got %v", keys)
+	}
+}
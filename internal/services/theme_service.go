@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+)
+
+var ErrBackgroundNotUploaded = errors.New("background image must be uploaded first")
+
+type ThemeService struct {
+	PageRepository  page_repository.PageRepository
+	ThemeRepository theme_repository.ThemeRepository
+}
+
+func NewThemeService(pageRepository page_repository.PageRepository, themeRepository theme_repository.ThemeRepository) *ThemeService {
+	return &ThemeService{
+		PageRepository:  pageRepository,
+		ThemeRepository: themeRepository,
+	}
+}
+
+func (ts *ThemeService) GetTheme(ctx context.Context, handle string) (theme.Theme, error) {
+	owner, err := ts.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return theme.Theme{}, err
+	}
+
+	return ts.GetThemeByPageID(ctx, owner.ID)
+}
+
+func (ts *ThemeService) GetThemeByPageID(ctx context.Context, pageID int32) (theme.Theme, error) {
+	t, err := ts.ThemeRepository.GetTheme(ctx, pageID)
+	if err != nil {
+		if errors.Is(err, theme_repository.ErrThemeNotFound) {
+			return theme.Default(), nil
+		}
+		return theme.Theme{}, err
+	}
+
+	return t, nil
+}
+
+// UpdateTheme replaces the theme of a page. Background images can only point to
+// the object uploaded through SetBackgroundImage.
+func (ts *ThemeService) UpdateTheme(ctx context.Context, handle string, t theme.Theme) error {
+	owner, err := ts.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+
+	if t.Background.Image != "" {
+		current, err := ts.GetThemeByPageID(ctx, owner.ID)
+		if err != nil {
+			return err
+		}
+		if current.Background.Image != t.Background.Image {
+			return ErrBackgroundNotUploaded
+		}
+	}
+
+	return ts.saveTheme(ctx, owner.ID, t)
+}
+
+func (ts *ThemeService) SetBackgroundImage(ctx context.Context, handle, objectName string) error {
+	owner, err := ts.PageRepository.GetPageByHandle(ctx, handle)
+	if err != nil {
+		return err
+	}
+
+	t, err := ts.GetThemeByPageID(ctx, owner.ID)
+	if err != nil {
+		return err
+	}
+
+	t.Background.Type = "image"
+	t.Background.Image = objectName
+	t.Background.Gradient = nil
+
+	return ts.saveTheme(ctx, owner.ID, t)
+}
+
+func (ts *ThemeService) saveTheme(ctx context.Context, pageID int32, t theme.Theme) error {
+	if t.Version == 0 {
+		t.Version = theme.CurrentVersion
+	}
+	t.Background.ImageURL = ""
+
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	return ts.ThemeRepository.UpsertTheme(ctx, pageID, t)
+}
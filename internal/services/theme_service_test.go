@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/theme"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/theme_repository"
+)
+
+func newTestThemeService(t *testing.T) (*ThemeService, *theme_repository.InMemoryThemeRepository) {
+	pr := page_repository.NewInMemoryPageRepository()
+	tr := theme_repository.NewInMemoryThemeRepository()
+	pr.CreatePage(t.Context(), page_repository.Page{
+		ID:        1,
+		AccountID: 1,
+		Handle:    "johndoe",
+		Name:      "John Doe",
+	})
+	return NewThemeService(pr, tr), tr
+}
+
+func TestThemeService_GetTheme_Default(t *testing.T) {
+	ts, _ := newTestThemeService(t)
+
+	got, err := ts.GetTheme(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got.Font != theme.Default().Font {
+		t.Fatalf("expected default theme, got %+v", got)
+	}
+}
+
+func TestThemeService_UpdateTheme(t *testing.T) {
+	ts, tr := newTestThemeService(t)
+
+	dark := theme.Default()
+	dark.Version = 0
+	dark.Background.Color = "#101010"
+	dark.TextColor = "#fafafa"
+	dark.Button.Color = "#fafafa"
+	dark.Button.TextColor = "#101010"
+
+	if err := ts.UpdateTheme(t.Context(), "johndoe", dark); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if tr.Themes[1].Version != theme.CurrentVersion {
+		t.Fatalf("expected theme to be stored with current version, got %d", tr.Themes[1].Version)
+	}
+
+	dark.TextColor = "#202020"
+	if err := ts.UpdateTheme(t.Context(), "johndoe", dark); !errors.Is(err, theme.ErrLowContrast) {
+		t.Fatalf("expected ErrLowContrast, got %v", err)
+	}
+}
+
+func TestThemeService_SetBackgroundImage(t *testing.T) {
+	ts, tr := newTestThemeService(t)
+
+	if err := ts.SetBackgroundImage(t.Context(), "johndoe", "background.png"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if tr.Themes[1].Background.Type != "image" || tr.Themes[1].Background.Image != "background.png" {
+		t.Fatalf("expected image background, got %+v", tr.Themes[1].Background)
+	}
+}
+
+func TestThemeService_UpdateTheme_ForeignImage(t *testing.T) {
+	ts, _ := newTestThemeService(t)
+
+	custom := theme.Default()
+	custom.Background = theme.Background{Type: "image", Color: "#ffffff", Image: "someone-else.png"}
+
+	if err := ts.UpdateTheme(t.Context(), "johndoe", custom); !errors.Is(err, ErrBackgroundNotUploaded) {
+		t.Fatalf("expected ErrBackgroundNotUploaded, got %v", err)
+	}
+}
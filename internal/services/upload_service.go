@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/theNixagen/linker/internal/domain/imaging"
+	"github.com/theNixagen/linker/internal/domain/upload"
+	"github.com/theNixagen/linker/internal/repositories/storage_repository"
+	"github.com/theNixagen/linker/internal/repositories/upload_repository"
+)
+
+// uploadPrefix holds objects sent by clients that were not verified yet.
+const uploadPrefix = "uploads/"
+
+var (
+	ErrUploadExpired    = errors.New("upload expired")
+	ErrUploadIncomplete = errors.New("file was not uploaded yet")
+	ErrUploadMismatch   = errors.New("uploaded file does not match the declared size, type or checksum")
+)
+
+// UploadService lets clients send pictures straight to storage and attaches
+// them to the page once the object is verified.
+type UploadService struct {
+	UploadRepository upload_repository.UploadRepository
+	ImageService     *ImageService
+	FileService      *FileService
+}
+
+func NewUploadService(uploadRepository upload_repository.UploadRepository, imageService *ImageService, fileService *FileService) *UploadService {
+	return &UploadService{
+		UploadRepository: uploadRepository,
+		ImageService:     imageService,
+		FileService:      fileService,
+	}
+}
+
+func (us *UploadService) CreateUpload(ctx context.Context, pageID int32, req upload.CreateUpload) (upload.PresignedUpload, error) {
+	if req.Size > us.FileService.MaxUploadSize {
+		return upload.PresignedUpload{}, ErrUploadTooLarge
+	}
+	// The declared size is reserved by the upload row until the upload is
+	// completed or expires. The lock keeps concurrent uploads of the account
+	// from reserving the same room.
+	unlock, err := us.ImageService.lock(ctx, pageID)
+	if err != nil {
+		return upload.PresignedUpload{}, err
+	}
+	defer unlock()
+	if err := us.ImageService.checkQuota(ctx, pageID, req.Size); err != nil {
+		return upload.PresignedUpload{}, err
+	}
+
+	now := time.Now()
+	key := uploadKey(pageID)
+
+	postURL, fields, err := us.FileService.PresignedPost(ctx, key, req.ContentType, req.Size, now.Add(upload.URLTTL))
+	if err != nil {
+		return upload.PresignedUpload{}, err
+	}
+
+	created, err := us.UploadRepository.CreateUpload(ctx, upload_repository.Upload{
+		PageID:      pageID,
+		Kind:        req.Kind,
+		ObjectKey:   key,
+		ContentType: req.ContentType,
+		Size:        req.Size,
+		Checksum:    strings.ToLower(req.ChecksumSHA256),
+		ExpiresAt:   now.Add(upload.Expiry),
+	})
+	if err != nil {
+		return upload.PresignedUpload{}, err
+	}
+
+	return upload.PresignedUpload{
+		ID:        created.ID,
+		URL:       postURL,
+		Method:    http.MethodPost,
+		Fields:    fields,
+		ExpiresAt: now.Add(upload.URLTTL),
+	}, nil
+}
+
+// CompleteUpload checks the uploaded object against what the client declared
+// and hands it to Attach, which scans and sets it as the avatar or banner of
+// the page in the background and discards it once done. Uploads that fail
+// the checks are discarded, other failures can be retried. Attach checks the
+// quota again against what the upload will store, which replaces the room
+// reserved by CreateUpload.
+func (us *UploadService) CompleteUpload(ctx context.Context, pageID int32, handle string, id int32) error {
+	pending, err := us.UploadRepository.GetUpload(ctx, pageID, id)
+	if err != nil {
+		return err
+	}
+	if !pending.ProcessingSince.IsZero() {
+		return nil
+	}
+
+	if time.Now().After(pending.ExpiresAt) {
+		us.discard(ctx, pending)
+		return ErrUploadExpired
+	}
+
+	data, err := us.FileService.ReadObject(ctx, pending.ObjectKey)
+	if err != nil {
+		if errors.Is(err, storage_repository.ErrObjectNotFound) {
+			return ErrUploadIncomplete
+		}
+		if errors.Is(err, ErrUploadTooLarge) {
+			us.discard(ctx, pending)
+		}
+		return err
+	}
+
+	if err := verifyUpload(pending, data); err != nil {
+		us.discard(ctx, pending)
+		return err
+	}
+
+	if err := us.ImageService.Attach(ctx, handle, pending, data); err != nil {
+		if errors.Is(err, imaging.ErrUnsupportedType) || errors.Is(err, imaging.ErrTooManyPixels) {
+			us.discard(ctx, pending)
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveExpired deletes pending uploads that were never completed, along with
+// whatever the client managed to store.
+func (us *UploadService) RemoveExpired(ctx context.Context) error {
+	expired, err := us.UploadRepository.ListExpiredUploads(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, pending := range expired {
+		us.discard(ctx, pending)
+	}
+	return nil
+}
+
+// RunExpiry calls RemoveExpired on every tick until ctx is done.
+func (us *UploadService) RunExpiry(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := us.RemoveExpired(ctx); err != nil {
+				log.Printf("could not remove expired uploads: %v", err)
+			}
+		}
+	}
+}
+
+// Usage reports what the account owning a page stores against its quota.
+func (us *UploadService) Usage(ctx context.Context, pageID int32) (upload.Usage, error) {
+	used, err := us.ImageService.Usage(ctx, pageID)
+	if err != nil {
+		return upload.Usage{}, err
+	}
+	return upload.Usage{
+		UsedBytes:     used,
+		QuotaBytes:    us.FileService.Quota,
+		MaxUploadSize: us.FileService.MaxUploadSize,
+	}, nil
+}
+
+func (us *UploadService) discard(ctx context.Context, pending upload_repository.Upload) {
+	us.ImageService.discardUpload(ctx, pending)
+}
+
+// uploadKey returns a new key for an upload of the page.
+func uploadKey(pageID int32) string {
+	return fmt.Sprintf("%s%d/%s", uploadPrefix, pageID, uuid.NewString())
+}
+
+func verifyUpload(pending upload_repository.Upload, data []byte) error {
+	if int64(len(data)) != pending.Size {
+		return ErrUploadMismatch
+	}
+
+	contentType, err := imaging.Sniff(data)
+	if err != nil {
+		return err
+	}
+	if contentType != pending.ContentType {
+		return ErrUploadMismatch
+	}
+
+	if pending.Checksum != "" {
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != pending.Checksum {
+			return ErrUploadMismatch
+		}
+	}
+	return nil
+}
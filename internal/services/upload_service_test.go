@@ -0,0 +1,153 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/theNixagen/linker/internal/domain/upload"
+	"github.com/theNixagen/linker/internal/repositories/page_repository"
+	"github.com/theNixagen/linker/internal/repositories/upload_repository"
+)
+
+func newTestUploadService(t *testing.T) *UploadService {
+	is, _, _, _ := newTestImageService(t)
+	is.FileService.Quota = 1 << 20
+	return NewUploadService(is.UploadRepository, is, is.FileService)
+}
+
+func TestUploadService_CreateUpload_TooLarge(t *testing.T) {
+	us := newTestUploadService(t)
+
+	_, err := us.CreateUpload(t.Context(), 1, upload.CreateUpload{Kind: upload.KindAvatar, ContentType: "image/png", Size: DefaultMaxUploadSize + 1})
+	if !errors.Is(err, ErrUploadTooLarge) {
+		t.Fatalf("expected ErrUploadTooLarge, got %v", err)
+	}
+}
+
+func TestUploadService_CreateUpload_QuotaExceeded(t *testing.T) {
+	us := newTestUploadService(t)
+	if _, err := us.CreateUpload(t.Context(), 1, upload.CreateUpload{Kind: upload.KindAvatar, ContentType: "image/png", Size: 1 << 19}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The first upload was not sent yet, but its size is reserved.
+	_, err := us.CreateUpload(t.Context(), 1, upload.CreateUpload{Kind: upload.KindAvatar, ContentType: "image/png", Size: 1<<19 + 1})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	usage, err := us.Usage(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if usage.UsedBytes != 1<<19 || usage.QuotaBytes != 1<<20 {
+		t.Errorf("expected 512 KiB of 1 MiB in use, got %+v", usage)
+	}
+}
+
+func TestUploadService_CreateUpload_QuotaSharedByAccount(t *testing.T) {
+	us := newTestUploadService(t)
+	if _, err := us.ImageService.PageRepository.CreatePage(t.Context(), page_repository.Page{ID: 2, AccountID: 1, Handle: "johndoe-links", Name: "John Doe"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := us.CreateUpload(t.Context(), 1, upload.CreateUpload{Kind: upload.KindAvatar, ContentType: "image/png", Size: 1 << 19}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// Another page of the same account draws from the same quota.
+	_, err := us.CreateUpload(t.Context(), 2, upload.CreateUpload{Kind: upload.KindAvatar, ContentType: "image/png", Size: 1<<19 + 1})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+
+	usage, err := us.Usage(t.Context(), 2)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if usage.UsedBytes != 1<<19 {
+		t.Errorf("expected the account to use 512 KiB, got %+v", usage)
+	}
+}
+
+func TestUploadService_CompleteUpload_QuotaExceeded(t *testing.T) {
+	us := newTestUploadService(t)
+	data := testPNG(t, color.White)
+
+	created, err := us.CreateUpload(t.Context(), 1, upload.CreateUpload{Kind: upload.KindAvatar, ContentType: "image/png", Size: int64(len(data))})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	pending, err := us.UploadRepository.GetUpload(t.Context(), 1, created.ID)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := us.FileService.PutObject(t.Context(), pending.ObjectKey, bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	us.FileService.Quota = int64(len(data)) - 1
+	if err := us.CompleteUpload(t.Context(), 1, "johndoe", created.ID); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if _, err := us.UploadRepository.GetUpload(t.Context(), 1, created.ID); err != nil {
+		t.Fatalf("expected the upload to be kept for a retry, got %v", err)
+	}
+
+	us.FileService.Quota = 1 << 20
+	if err := us.CompleteUpload(t.Context(), 1, "johndoe", created.ID); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	us.ImageService.Wait()
+
+	p, err := us.ImageService.PageRepository.GetPageByHandle(t.Context(), "johndoe")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if p.ProfilePicture == "" {
+		t.Fatal("expected the upload to be set as the avatar")
+	}
+	pendingSize, err := us.UploadRepository.PendingSize(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if pendingSize != 0 {
+		t.Errorf("expected the reservation to be dropped, got %d bytes", pendingSize)
+	}
+}
+
+func TestVerifyUpload(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	data := buf.Bytes()
+	sum := sha256.Sum256(data)
+
+	pending := upload_repository.Upload{ContentType: "image/png", Size: int64(len(data)), Checksum: hex.EncodeToString(sum[:])}
+	if err := verifyUpload(pending, data); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*upload_repository.Upload)
+	}{
+		{"size", func(u *upload_repository.Upload) { u.Size++ }},
+		{"content type", func(u *upload_repository.Upload) { u.ContentType = "image/jpeg" }},
+		{"checksum", func(u *upload_repository.Upload) { u.Checksum = hex.EncodeToString(make([]byte, 32)) }},
+	}
+
+	for _, tt := range tests {
+		u := pending
+		tt.modify(&u)
+		if err := verifyUpload(u, data); !errors.Is(err, ErrUploadMismatch) {
+			t.Errorf("%s: expected ErrUploadMismatch, got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN slug VARCHAR(64);
+ALTER TABLE links ADD COLUMN short_code VARCHAR(16);
+UPDATE links SET short_code = substr(md5(random()::text || id::text), 1, 8);
+ALTER TABLE links ALTER COLUMN short_code SET NOT NULL;
+ALTER TABLE links ADD CONSTRAINT links_short_code_key UNIQUE (short_code);
+CREATE UNIQUE INDEX links_user_id_slug_idx ON links (user_id, slug) WHERE slug IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX links_user_id_slug_idx;
+ALTER TABLE links DROP COLUMN short_code;
+ALTER TABLE links DROP COLUMN slug;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN platform VARCHAR(32) NOT NULL DEFAULT '';
+ALTER TABLE links ADD COLUMN handle VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN handle;
+ALTER TABLE links DROP COLUMN platform;
+-- +goose StatementEnd
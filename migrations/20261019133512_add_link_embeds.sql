@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Embeds are fetched in the background and stored on the link, so serving a
+-- profile never waits on an oEmbed provider. Links that were never fetched
+-- have a NULL embed_fetched_at.
+ALTER TABLE links ADD COLUMN embed JSONB;
+ALTER TABLE links ADD COLUMN embed_fetched_at TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN embed_fetched_at;
+ALTER TABLE links DROP COLUMN embed;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE links ADD COLUMN sensitive BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE links ADD COLUMN password_hash VARCHAR(255) NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN password_hash;
+ALTER TABLE links DROP COLUMN sensitive;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN utm JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE links ADD COLUMN utm JSONB NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links DROP COLUMN utm;
+ALTER TABLE users DROP COLUMN utm;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE custom_domains (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    domain VARCHAR(253) NOT NULL,
+    verification_token VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    last_checked_at TIMESTAMP,
+    verified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX custom_domains_user_id_idx ON custom_domains (user_id);
+-- A pending claim doesn't hold the domain: any user may claim it, and only
+-- the first to verify owns it. Unverified claims expire after a while.
+CREATE UNIQUE INDEX custom_domains_verified_domain_key ON custom_domains (domain) WHERE status = 'verified';
+CREATE UNIQUE INDEX custom_domains_user_id_domain_key ON custom_domains (user_id, domain);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE custom_domains;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'public'
+    CONSTRAINT users_visibility_check CHECK (visibility IN ('public', 'unlisted', 'private'));
+
+ALTER TABLE links ADD COLUMN published BOOLEAN NOT NULL DEFAULT FALSE;
+-- UTM tags are part of the draft: visitors get the tags copied here at
+-- publish time, so editing them doesn't change the live profile.
+ALTER TABLE links ADD COLUMN published_utm JSONB NOT NULL DEFAULT '{}';
+UPDATE links SET published = TRUE, published_utm = utm;
+
+CREATE TABLE published_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    bio TEXT NOT NULL,
+    profile_picture TEXT NOT NULL,
+    banner_picture TEXT NOT NULL,
+    theme JSONB,
+    utm JSONB NOT NULL DEFAULT '{}',
+    published_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Every existing profile was live, so it starts published as it is.
+INSERT INTO published_profiles (user_id, name, bio, profile_picture, banner_picture, theme, utm)
+SELECT u.id, COALESCE(u.name, ''), u.bio, u.profile_picture, u.banner_picture, t.theme, u.utm
+FROM users u
+LEFT JOIN profile_themes t ON t.user_id = u.id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE published_profiles;
+ALTER TABLE links DROP COLUMN published_utm;
+ALTER TABLE links DROP COLUMN published;
+ALTER TABLE users DROP COLUMN visibility;
+-- +goose StatementEnd
@@ -0,0 +1,117 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE pages (
+    id SERIAL PRIMARY KEY,
+    account_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    handle VARCHAR(255) NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    bio TEXT NOT NULL DEFAULT '',
+    profile_picture TEXT NOT NULL DEFAULT '',
+    banner_picture TEXT NOT NULL DEFAULT '',
+    utm JSONB NOT NULL DEFAULT '{}',
+    visibility VARCHAR(16) NOT NULL DEFAULT 'public'
+        CONSTRAINT pages_visibility_check CHECK (visibility IN ('public', 'unlisted', 'private')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT pages_handle_key UNIQUE (handle)
+);
+
+CREATE INDEX pages_account_id_idx ON pages (account_id);
+
+-- Every user becomes one page with the same id, so rows that pointed to the
+-- user keep pointing to its page.
+INSERT INTO pages (id, account_id, handle, name, bio, profile_picture, banner_picture, utm, visibility, created_at)
+SELECT id, id, COALESCE(username, 'user-' || id), COALESCE(name, ''), bio, profile_picture, banner_picture, utm, visibility, created_at
+FROM users;
+
+SELECT setval(pg_get_serial_sequence('pages', 'id'), COALESCE((SELECT MAX(id) FROM pages), 0) + 1, false);
+
+ALTER TABLE links DROP CONSTRAINT links_user_id_fkey;
+ALTER TABLE links RENAME COLUMN user_id TO page_id;
+ALTER TABLE links ADD CONSTRAINT links_page_id_fkey FOREIGN KEY (page_id) REFERENCES pages(id) ON DELETE CASCADE;
+ALTER INDEX links_user_id_slug_idx RENAME TO links_page_id_slug_idx;
+
+ALTER TABLE profile_themes DROP CONSTRAINT profile_themes_user_id_fkey;
+ALTER TABLE profile_themes RENAME COLUMN user_id TO page_id;
+ALTER TABLE profile_themes ADD CONSTRAINT profile_themes_page_id_fkey FOREIGN KEY (page_id) REFERENCES pages(id) ON DELETE CASCADE;
+
+ALTER TABLE clicks DROP CONSTRAINT clicks_user_id_fkey;
+ALTER TABLE clicks RENAME COLUMN user_id TO page_id;
+ALTER TABLE clicks ADD CONSTRAINT clicks_page_id_fkey FOREIGN KEY (page_id) REFERENCES pages(id) ON DELETE CASCADE;
+ALTER INDEX clicks_user_id_created_at_idx RENAME TO clicks_page_id_created_at_idx;
+
+ALTER TABLE custom_domains DROP CONSTRAINT custom_domains_user_id_fkey;
+ALTER TABLE custom_domains RENAME COLUMN user_id TO page_id;
+ALTER TABLE custom_domains ADD CONSTRAINT custom_domains_page_id_fkey FOREIGN KEY (page_id) REFERENCES pages(id) ON DELETE CASCADE;
+ALTER INDEX custom_domains_user_id_idx RENAME TO custom_domains_page_id_idx;
+ALTER INDEX custom_domains_user_id_domain_key RENAME TO custom_domains_page_id_domain_key;
+
+ALTER TABLE published_profiles DROP CONSTRAINT published_profiles_user_id_fkey;
+ALTER TABLE published_profiles RENAME COLUMN user_id TO page_id;
+ALTER TABLE published_profiles ADD CONSTRAINT published_profiles_page_id_fkey FOREIGN KEY (page_id) REFERENCES pages(id) ON DELETE CASCADE;
+
+ALTER TABLE profile_revisions DROP CONSTRAINT profile_revisions_user_id_fkey;
+ALTER TABLE profile_revisions RENAME COLUMN user_id TO page_id;
+ALTER TABLE profile_revisions ADD CONSTRAINT profile_revisions_page_id_fkey FOREIGN KEY (page_id) REFERENCES pages(id) ON DELETE CASCADE;
+ALTER INDEX profile_revisions_user_id_idx RENAME TO profile_revisions_page_id_idx;
+
+ALTER TABLE users
+    DROP COLUMN profile_picture,
+    DROP COLUMN bio,
+    DROP COLUMN banner_picture,
+    DROP COLUMN utm,
+    DROP COLUMN visibility;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN profile_picture TEXT NOT NULL DEFAULT '',
+    ADD COLUMN bio TEXT NOT NULL DEFAULT '',
+    ADD COLUMN banner_picture TEXT NOT NULL DEFAULT '',
+    ADD COLUMN utm JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN visibility VARCHAR(16) NOT NULL DEFAULT 'public'
+        CONSTRAINT users_visibility_check CHECK (visibility IN ('public', 'unlisted', 'private'));
+
+UPDATE users u SET
+    profile_picture = p.profile_picture,
+    bio = p.bio,
+    banner_picture = p.banner_picture,
+    utm = p.utm,
+    visibility = p.visibility
+FROM pages p
+WHERE p.id = u.id;
+
+-- Only the page created from each user can go back to it.
+DELETE FROM pages WHERE id <> account_id;
+
+ALTER TABLE profile_revisions DROP CONSTRAINT profile_revisions_page_id_fkey;
+ALTER TABLE profile_revisions RENAME COLUMN page_id TO user_id;
+ALTER TABLE profile_revisions ADD CONSTRAINT profile_revisions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER INDEX profile_revisions_page_id_idx RENAME TO profile_revisions_user_id_idx;
+
+ALTER TABLE published_profiles DROP CONSTRAINT published_profiles_page_id_fkey;
+ALTER TABLE published_profiles RENAME COLUMN page_id TO user_id;
+ALTER TABLE published_profiles ADD CONSTRAINT published_profiles_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE custom_domains DROP CONSTRAINT custom_domains_page_id_fkey;
+ALTER TABLE custom_domains RENAME COLUMN page_id TO user_id;
+ALTER TABLE custom_domains ADD CONSTRAINT custom_domains_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER INDEX custom_domains_page_id_idx RENAME TO custom_domains_user_id_idx;
+ALTER INDEX custom_domains_page_id_domain_key RENAME TO custom_domains_user_id_domain_key;
+
+ALTER TABLE clicks DROP CONSTRAINT clicks_page_id_fkey;
+ALTER TABLE clicks RENAME COLUMN page_id TO user_id;
+ALTER TABLE clicks ADD CONSTRAINT clicks_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER INDEX clicks_page_id_created_at_idx RENAME TO clicks_user_id_created_at_idx;
+
+ALTER TABLE profile_themes DROP CONSTRAINT profile_themes_page_id_fkey;
+ALTER TABLE profile_themes RENAME COLUMN page_id TO user_id;
+ALTER TABLE profile_themes ADD CONSTRAINT profile_themes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+
+ALTER TABLE links DROP CONSTRAINT links_page_id_fkey;
+ALTER TABLE links RENAME COLUMN page_id TO user_id;
+ALTER TABLE links ADD CONSTRAINT links_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id);
+ALTER INDEX links_page_id_slug_idx RENAME TO links_user_id_slug_idx;
+
+DROP TABLE pages;
+-- +goose StatementEnd
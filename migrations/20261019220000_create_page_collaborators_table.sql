@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE page_collaborators (
+    page_id INTEGER NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    account_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (page_id, account_id),
+    CONSTRAINT page_collaborators_role_check CHECK (role IN ('editor', 'analyst'))
+);
+
+CREATE INDEX page_collaborators_account_id_idx ON page_collaborators (account_id);
+
+CREATE TABLE page_invitations (
+    id SERIAL PRIMARY KEY,
+    page_id INTEGER NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    role VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    invited_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT page_invitations_token_hash_key UNIQUE (token_hash),
+    CONSTRAINT page_invitations_page_id_email_key UNIQUE (page_id, email),
+    CONSTRAINT page_invitations_role_check CHECK (role IN ('editor', 'analyst'))
+);
+
+CREATE TABLE audit_log (
+    id SERIAL PRIMARY KEY,
+    page_id INTEGER NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    account_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(64) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_log_page_id_created_at_idx ON audit_log (page_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_log;
+DROP TABLE page_invitations;
+DROP TABLE page_collaborators;
+-- +goose StatementEnd
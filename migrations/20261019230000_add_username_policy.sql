@@ -0,0 +1,82 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Usernames and handles that only differ by case get their id appended so the
+-- case-insensitive indexes can be created. The oldest one keeps its name. The
+-- name is cut to fit the 30 character limit with the suffix, and a counter is
+-- added while the result is still taken.
+DO $$
+DECLARE
+    dup RECORD;
+    suffix TEXT;
+    candidate TEXT;
+    attempt INT;
+BEGIN
+    FOR dup IN
+        SELECT id, username FROM (
+            SELECT id, username, ROW_NUMBER() OVER (PARTITION BY LOWER(username) ORDER BY id) AS n
+            FROM users WHERE username IS NOT NULL
+        ) ranked WHERE n > 1
+    LOOP
+        attempt := 0;
+        LOOP
+            suffix := '_' || dup.id || CASE WHEN attempt > 0 THEN '_' || attempt ELSE '' END;
+            candidate := RTRIM(LEFT(dup.username, 30 - LENGTH(suffix)), '._') || suffix;
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM users WHERE LOWER(username) = LOWER(candidate));
+            attempt := attempt + 1;
+        END LOOP;
+        UPDATE users SET username = candidate WHERE id = dup.id;
+    END LOOP;
+
+    FOR dup IN
+        SELECT id, handle FROM (
+            SELECT id, handle, ROW_NUMBER() OVER (PARTITION BY LOWER(handle) ORDER BY id) AS n
+            FROM pages
+        ) ranked WHERE n > 1
+    LOOP
+        attempt := 0;
+        LOOP
+            suffix := '_' || dup.id || CASE WHEN attempt > 0 THEN '_' || attempt ELSE '' END;
+            candidate := RTRIM(LEFT(dup.handle, 30 - LENGTH(suffix)), '._') || suffix;
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM pages WHERE LOWER(handle) = LOWER(candidate));
+            attempt := attempt + 1;
+        END LOOP;
+        UPDATE pages SET handle = candidate WHERE id = dup.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE users DROP CONSTRAINT users_username_key;
+CREATE UNIQUE INDEX users_username_lower_key ON users (LOWER(username));
+
+ALTER TABLE pages DROP CONSTRAINT pages_handle_key;
+CREATE UNIQUE INDEX pages_handle_lower_key ON pages (LOWER(handle));
+
+-- Handles are unique by skeleton, so lookalikes such as "j0hndoe" can't be
+-- registered next to "johndoe". The translation matches username.Skeleton.
+-- When existing handles already share a skeleton the oldest one holds it and
+-- the others keep a NULL skeleton.
+ALTER TABLE pages ADD COLUMN handle_skeleton VARCHAR(255);
+
+UPDATE pages SET handle_skeleton = ranked.skeleton
+FROM (
+    SELECT id, skeleton, ROW_NUMBER() OVER (PARTITION BY skeleton ORDER BY id) AS n
+    FROM (
+        SELECT id, TRANSLATE(LOWER(handle), 'авезіјкмнорстухѕԁԛԝɡıℓαβεικνορτυχω０１２３４５６７８９ａｂｃｄｅｆｇｈｉｊｋｌｍｎｏｐｑｒｓｔｕｖｗｘｙｚ01i5_.', 'abe3ljkmhopctyxsdqwgllabelkvoptuxwol234s6789abcdefghljklmnopqrstuvwxyzolls') AS skeleton
+        FROM pages
+    ) folded
+) ranked
+WHERE pages.id = ranked.id AND ranked.n = 1;
+
+CREATE UNIQUE INDEX pages_handle_skeleton_key ON pages (handle_skeleton);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX pages_handle_skeleton_key;
+ALTER TABLE pages DROP COLUMN handle_skeleton;
+
+DROP INDEX pages_handle_lower_key;
+ALTER TABLE pages ADD CONSTRAINT pages_handle_key UNIQUE (handle);
+
+DROP INDEX users_username_lower_key;
+ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
+-- +goose StatementEnd
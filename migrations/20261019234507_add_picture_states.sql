@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pages
+    ADD COLUMN profile_picture_state VARCHAR(16) NOT NULL DEFAULT 'ready'
+        CONSTRAINT pages_profile_picture_state_check CHECK (profile_picture_state IN ('ready', 'pending', 'rejected', 'failed')),
+    ADD COLUMN banner_picture_state VARCHAR(16) NOT NULL DEFAULT 'ready'
+        CONSTRAINT pages_banner_picture_state_check CHECK (banner_picture_state IN ('ready', 'pending', 'rejected', 'failed'));
+
+ALTER TABLE uploads ADD COLUMN processing_since TIMESTAMP;
+
+CREATE INDEX uploads_processing_since_idx ON uploads (processing_since);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX uploads_processing_since_idx;
+
+ALTER TABLE uploads DROP COLUMN processing_since;
+
+ALTER TABLE pages
+    DROP COLUMN profile_picture_state,
+    DROP COLUMN banner_picture_state;
+-- +goose StatementEnd
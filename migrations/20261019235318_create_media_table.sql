@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE media (
+    id SERIAL PRIMARY KEY,
+    page_id INTEGER NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+    object_key TEXT NOT NULL,
+    content_type VARCHAR(64) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    size BIGINT NOT NULL,
+    -- Bytes of the avatar and banner variants made from the image while
+    -- they are stored, counted towards the quota.
+    avatar_size BIGINT NOT NULL DEFAULT 0,
+    banner_size BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT media_page_id_object_key_key UNIQUE (page_id, object_key)
+);
+
+-- Thumbnail holds the object key of the media, which never changes, so links
+-- are listed without a join.
+ALTER TABLE links
+    ADD COLUMN thumbnail_media_id INTEGER REFERENCES media(id),
+    ADD COLUMN thumbnail TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE links
+    DROP COLUMN thumbnail_media_id,
+    DROP COLUMN thumbnail;
+
+DROP TABLE media;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- name: CreateAuditEntry :exec
+INSERT INTO audit_log(
+  page_id,
+  account_id,
+  action,
+  details
+) values(
+  $1,$2,$3,$4
+);
+
+-- name: ListAuditEntries :many
+SELECT audit_log.id, audit_log.page_id, audit_log.account_id, audit_log.action, audit_log.details, audit_log.created_at, users.email
+FROM audit_log
+LEFT JOIN users ON users.id = audit_log.account_id
+WHERE audit_log.page_id = $1
+ORDER BY audit_log.created_at DESC, audit_log.id DESC
+LIMIT $2;
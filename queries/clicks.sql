@@ -0,0 +1,14 @@
+-- name: RecordLinkClick :exec
+INSERT INTO clicks(
+  page_id,
+  link_id,
+  source
+) SELECT page_id, id, $2 FROM links WHERE id = $1;
+
+-- name: RecordProfileView :exec
+INSERT INTO clicks(
+  page_id,
+  source
+) values(
+  $1,$2
+);
@@ -0,0 +1,51 @@
+-- name: AddCollaborator :exec
+INSERT INTO page_collaborators(
+  page_id,
+  account_id,
+  role
+) values(
+  $1,$2,$3
+) ON CONFLICT (page_id, account_id) DO UPDATE SET role = EXCLUDED.role;
+
+-- name: DeleteInvitation :exec
+DELETE FROM page_invitations WHERE id = $1;
+
+-- name: GetCollaborator :one
+SELECT * FROM page_collaborators WHERE page_id = $1 AND account_id = $2;
+
+-- name: GetInvitationByTokenHash :one
+SELECT * FROM page_invitations WHERE token_hash = $1;
+
+-- name: ListCollaborators :many
+SELECT page_collaborators.page_id, page_collaborators.account_id, page_collaborators.role, page_collaborators.created_at, users.email, users.name
+FROM page_collaborators
+JOIN users ON users.id = page_collaborators.account_id
+WHERE page_collaborators.page_id = $1
+ORDER BY page_collaborators.created_at;
+
+-- name: ListCollaborationsByAccount :many
+SELECT * FROM page_collaborators WHERE account_id = $1 ORDER BY page_id;
+
+-- name: ListInvitations :many
+SELECT * FROM page_invitations WHERE page_id = $1 AND expires_at > NOW() ORDER BY created_at;
+
+-- name: RemoveCollaborator :execrows
+DELETE FROM page_collaborators WHERE page_id = $1 AND account_id = $2;
+
+-- name: UpsertInvitation :one
+INSERT INTO page_invitations(
+  page_id,
+  email,
+  role,
+  token_hash,
+  invited_by,
+  expires_at
+) values(
+  $1,$2,$3,$4,$5,$6
+) ON CONFLICT (page_id, email) DO UPDATE SET
+  role = EXCLUDED.role,
+  token_hash = EXCLUDED.token_hash,
+  invited_by = EXCLUDED.invited_by,
+  expires_at = EXCLUDED.expires_at,
+  created_at = NOW()
+RETURNING *;
@@ -0,0 +1,37 @@
+-- name: CreateCustomDomain :one
+INSERT INTO custom_domains(
+  page_id,
+  domain,
+  verification_token
+) values(
+  $1,$2,$3
+) RETURNING *;
+
+-- name: DeleteCustomDomain :execrows
+DELETE FROM custom_domains WHERE id = $1 AND page_id = $2;
+
+-- name: DeleteExpiredCustomDomains :execrows
+DELETE FROM custom_domains WHERE status = 'pending' AND created_at < $1;
+
+-- name: FindVerifiedCustomDomain :one
+SELECT * FROM custom_domains WHERE domain = $1 AND status = 'verified';
+
+-- name: FindCustomDomainByID :one
+SELECT * FROM custom_domains WHERE id = $1;
+
+-- name: ListCustomDomainsByPage :many
+SELECT * FROM custom_domains WHERE page_id = $1 ORDER BY created_at;
+
+-- name: ListCustomDomainsToVerify :many
+SELECT * FROM custom_domains
+WHERE last_checked_at IS NULL OR last_checked_at < $1
+ORDER BY last_checked_at NULLS FIRST
+LIMIT $2;
+
+-- name: UpdateCustomDomainStatus :exec
+UPDATE custom_domains SET
+  status = $2,
+  failure_reason = $3,
+  last_checked_at = NOW(),
+  verified_at = CASE WHEN $2 = 'verified' THEN COALESCE(verified_at, NOW()) ELSE verified_at END
+WHERE id = $1;
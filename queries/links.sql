@@ -1,15 +1,90 @@
--- name: CreateLink :exec
+-- name: CreateLink :one
 INSERT INTO links(
-  user_id,
+  page_id,
 	url,
 	title,
 	description,
+	slug,
+	short_code,
+	platform,
+	handle,
+	sensitive,
+	password_hash,
+	thumbnail_media_id,
+	thumbnail,
 	created_at
 ) values(
-  $1,$2,$3,$4,NOW()
-);
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW()
+) RETURNING id;
 
--- name: FindAllLinksFromAUser :many
-SELECT * FROM links where user_id = $1;
+-- name: FindAllLinksFromAPage :many
+SELECT * FROM links where page_id = $1;
 
+-- name: FindPublishedLinksFromAPage :many
+SELECT * FROM links where page_id = $1 and published = TRUE;
 
+-- name: FindLinkByID :one
+SELECT * FROM links where id = $1;
+
+-- name: FindLinkBySlug :one
+SELECT * FROM links where page_id = $1 and slug = $2;
+
+-- name: FindLinkByShortCode :one
+SELECT * FROM links where short_code = $1;
+
+-- name: ListStaleEmbedLinks :many
+SELECT id, url, embed FROM links
+WHERE platform = '' AND (embed_fetched_at IS NULL OR embed_fetched_at < $1)
+ORDER BY embed_fetched_at NULLS FIRST, id
+LIMIT $2;
+
+-- name: UpdateLinkEmbed :exec
+UPDATE links set embed = $1, embed_fetched_at = NOW() where id = $2 and url = $3;
+
+-- name: UpdateLinkUTM :execrows
+UPDATE links set utm = $1 where id = $2 and page_id = $3;
+
+-- name: PublishLinks :exec
+UPDATE links set published = TRUE, published_utm = utm where page_id = $1;
+
+-- name: UnpublishLinks :exec
+UPDATE links set published = FALSE where page_id = $1;
+
+-- name: RestoreLink :exec
+INSERT INTO links(
+  id,
+  page_id,
+  url,
+  title,
+  description,
+  slug,
+  short_code,
+  platform,
+  handle,
+  sensitive,
+  password_hash,
+  utm,
+  published_utm,
+  published,
+  created_at,
+  thumbnail_media_id,
+  thumbnail
+) values(
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$12,TRUE,$13,$14,$15
+) ON CONFLICT (id) DO UPDATE SET
+  url = EXCLUDED.url,
+  title = EXCLUDED.title,
+  description = EXCLUDED.description,
+  slug = EXCLUDED.slug,
+  platform = EXCLUDED.platform,
+  handle = EXCLUDED.handle,
+  sensitive = EXCLUDED.sensitive,
+  password_hash = EXCLUDED.password_hash,
+  utm = EXCLUDED.utm,
+  published_utm = EXCLUDED.published_utm,
+  published = TRUE,
+  thumbnail_media_id = EXCLUDED.thumbnail_media_id,
+  thumbnail = EXCLUDED.thumbnail,
+  embed = CASE WHEN links.url = EXCLUDED.url THEN links.embed END,
+  embed_fetched_at = CASE WHEN links.url = EXCLUDED.url THEN links.embed_fetched_at END
+WHERE links.page_id = EXCLUDED.page_id;
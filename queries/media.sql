@@ -0,0 +1,63 @@
+-- name: CreateMedia :one
+INSERT INTO media(
+  page_id,
+  object_key,
+  content_type,
+  width,
+  height,
+  size
+) values(
+  $1,$2,$3,$4,$5,$6
+) ON CONFLICT (page_id, object_key) DO UPDATE SET object_key = EXCLUDED.object_key
+RETURNING *;
+
+-- name: GetMedia :one
+SELECT * FROM media WHERE id = $1 AND page_id = $2;
+
+-- name: ListMedia :many
+SELECT * FROM media WHERE page_id = $1 ORDER BY id DESC;
+
+-- name: UpdateMediaAvatarSize :exec
+UPDATE media SET avatar_size = $2 WHERE id = $1;
+
+-- name: UpdateMediaBannerSize :exec
+UPDATE media SET banner_size = $2 WHERE id = $1;
+
+-- name: ResetMediaAvatarSize :exec
+UPDATE media SET avatar_size = 0 WHERE object_key = $1;
+
+-- name: ResetMediaBannerSize :exec
+UPDATE media SET banner_size = 0 WHERE object_key = $1;
+
+-- name: GetMediaUsage :one
+SELECT COALESCE(SUM(m.size + m.avatar_size + m.banner_size), 0)::bigint AS used FROM media m JOIN pages p ON p.id = m.page_id WHERE p.account_id = $1;
+
+-- name: DeleteUnreferencedMedia :execrows
+DELETE FROM media m
+WHERE m.id = $1 AND m.page_id = $2
+  AND NOT EXISTS (
+    SELECT 1 FROM pages p
+    WHERE p.id = m.page_id AND (p.profile_picture = ANY(@keys::text[]) OR p.banner_picture = ANY(@keys::text[]))
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM profile_themes t
+    WHERE t.page_id = m.page_id AND t.theme->'background'->>'image' = ANY(@keys::text[])
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM published_profiles pp
+    WHERE pp.page_id = m.page_id AND (pp.profile_picture = ANY(@keys::text[]) OR pp.banner_picture = ANY(@keys::text[]) OR pp.theme->'background'->>'image' = ANY(@keys::text[]))
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM links l
+    WHERE l.thumbnail_media_id = m.id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM profile_revisions r
+    WHERE r.page_id = m.page_id AND (r.snapshot->>'profile_picture' = ANY(@keys::text[]) OR r.snapshot->>'banner_picture' = ANY(@keys::text[]) OR r.snapshot->'theme'->'background'->>'image' = ANY(@keys::text[]) OR r.snapshot->'links' @> jsonb_build_array(jsonb_build_object('thumbnail_media_id', m.id)))
+  );
+
+-- name: ListPicturesWithoutMedia :many
+SELECT id AS page_id, profile_picture, banner_picture FROM pages p
+WHERE (profile_picture <> '' OR banner_picture <> '')
+  AND NOT EXISTS (SELECT 1 FROM media m WHERE m.page_id = p.id)
+ORDER BY id;
@@ -0,0 +1,25 @@
+-- name: ListReferencedObjectKeys :many
+SELECT profile_picture AS object_key FROM pages WHERE profile_picture <> ''
+UNION
+SELECT banner_picture FROM pages WHERE banner_picture <> ''
+UNION
+SELECT profile_picture FROM published_profiles WHERE profile_picture <> ''
+UNION
+SELECT banner_picture FROM published_profiles WHERE banner_picture <> ''
+UNION
+SELECT theme->'background'->>'image' FROM published_profiles WHERE theme->'background'->>'image' <> ''
+UNION
+SELECT theme->'background'->>'image' FROM profile_themes WHERE theme->'background'->>'image' <> ''
+UNION
+SELECT snapshot->>'profile_picture' FROM profile_revisions WHERE snapshot->>'profile_picture' <> ''
+UNION
+SELECT snapshot->>'banner_picture' FROM profile_revisions WHERE snapshot->>'banner_picture' <> ''
+UNION
+SELECT snapshot->'theme'->'background'->>'image' FROM profile_revisions WHERE snapshot->'theme'->'background'->>'image' <> ''
+UNION
+SELECT object_key FROM uploads
+UNION
+SELECT object_key FROM media;
+
+-- name: ListPageHandles :many
+SELECT handle FROM pages ORDER BY id;
@@ -0,0 +1,43 @@
+-- name: CreatePage :one
+INSERT INTO pages(
+  account_id,
+  handle,
+  name,
+  handle_skeleton,
+  created_at
+) values(
+  $1,$2,$3,$4,NOW()
+) RETURNING id;
+
+-- name: GetPageByHandle :one
+SELECT * FROM pages where LOWER(handle) = LOWER(@handle);
+
+-- name: GetPageByID :one
+SELECT * FROM pages where id = $1;
+
+-- name: ListPagesByAccount :many
+SELECT * FROM pages where account_id = $1 ORDER BY id;
+
+-- name: UpdatePageBio :execrows
+UPDATE pages set bio = $1 where handle = $2;
+
+-- name: UpdatePageProfilePicture :execrows
+UPDATE pages set profile_picture = $1 where handle = $2;
+
+-- name: UpdatePageBannerPicture :execrows
+UPDATE pages set banner_picture = $1 where handle = $2;
+
+-- name: UpdatePageProfilePictureState :execrows
+UPDATE pages set profile_picture_state = $1 where handle = $2;
+
+-- name: UpdatePageBannerPictureState :execrows
+UPDATE pages set banner_picture_state = $1 where handle = $2;
+
+-- name: UpdatePageUTM :execrows
+UPDATE pages set utm = $1 where handle = $2;
+
+-- name: UpdatePageVisibility :execrows
+UPDATE pages set visibility = $1 where handle = $2;
+
+-- name: RestorePage :exec
+UPDATE pages set name = $2, bio = $3, profile_picture = $4, banner_picture = $5, utm = $6 where id = $1;
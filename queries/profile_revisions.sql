@@ -0,0 +1,21 @@
+-- name: CreateRevision :one
+INSERT INTO profile_revisions(
+  page_id,
+  reason,
+  snapshot,
+  created_at
+) values(
+  $1,$2,$3,NOW()
+) RETURNING *;
+
+-- name: ListRevisions :many
+SELECT id, page_id, reason, created_at FROM profile_revisions where page_id = $1 ORDER BY id DESC;
+
+-- name: GetRevision :one
+SELECT * FROM profile_revisions where id = $1 and page_id = $2;
+
+-- name: PruneRevisions :exec
+DELETE FROM profile_revisions
+WHERE page_id = $1 AND id NOT IN (
+  SELECT id FROM profile_revisions WHERE page_id = $1 ORDER BY id DESC LIMIT $2
+);
@@ -0,0 +1,24 @@
+-- name: GetPublishedProfile :one
+SELECT * FROM published_profiles where page_id = $1;
+
+-- name: UpsertPublishedProfile :one
+INSERT INTO published_profiles(
+  page_id,
+  name,
+  bio,
+  profile_picture,
+  banner_picture,
+  theme,
+  utm,
+  published_at
+) values(
+  $1,$2,$3,$4,$5,$6,$7,NOW()
+) ON CONFLICT (page_id) DO UPDATE SET
+  name = EXCLUDED.name,
+  bio = EXCLUDED.bio,
+  profile_picture = EXCLUDED.profile_picture,
+  banner_picture = EXCLUDED.banner_picture,
+  theme = EXCLUDED.theme,
+  utm = EXCLUDED.utm,
+  published_at = NOW()
+RETURNING published_at;
@@ -0,0 +1,15 @@
+-- name: GetThemeByPageID :one
+SELECT * FROM profile_themes where page_id = $1;
+
+-- name: UpsertTheme :exec
+INSERT INTO profile_themes(
+  page_id,
+  version,
+  theme,
+  updated_at
+) values(
+  $1,$2,$3,NOW()
+) ON CONFLICT (page_id) DO UPDATE SET version = EXCLUDED.version, theme = EXCLUDED.theme, updated_at = NOW();
+
+-- name: DeleteTheme :exec
+DELETE FROM profile_themes where page_id = $1;
@@ -0,0 +1,46 @@
+-- name: CreateUpload :one
+INSERT INTO uploads(
+  page_id,
+  kind,
+  object_key,
+  content_type,
+  size,
+  checksum,
+  expires_at
+) values(
+  $1,$2,$3,$4,$5,$6,$7
+) RETURNING *;
+
+-- name: DeleteUpload :exec
+DELETE FROM uploads WHERE id = $1;
+
+-- name: GetUpload :one
+SELECT * FROM uploads WHERE id = $1 AND page_id = $2;
+
+-- name: GetPendingUploadSize :one
+SELECT COALESCE(SUM(CASE WHEN u.processing_since IS NULL THEN u.size ELSE u.reserved_size END), 0)::bigint AS pending FROM uploads u JOIN pages p ON p.id = u.page_id WHERE p.account_id = $1;
+
+-- name: ListExpiredUploads :many
+SELECT * FROM uploads WHERE expires_at <= NOW() AND processing_since IS NULL ORDER BY id;
+
+-- name: ListStaleUploads :many
+SELECT * FROM uploads WHERE processing_since <= @before ORDER BY id;
+
+-- name: LockAccountStorage :exec
+SELECT pg_advisory_xact_lock(hashtext('account_storage'), @account_id::int);
+
+-- name: ReleaseUploadReservation :exec
+UPDATE uploads SET reserved_size = 0 WHERE id = $1;
+
+-- name: StartUploadProcessing :exec
+UPDATE uploads SET processing_since = NOW(), reserved_size = $2 WHERE id = $1;
+
+-- name: FailOrphanedPictureStates :exec
+UPDATE pages SET
+  profile_picture_state = CASE WHEN profile_picture_state = 'pending' AND NOT EXISTS (
+    SELECT 1 FROM uploads WHERE uploads.page_id = pages.id AND uploads.kind = 'avatar' AND uploads.processing_since IS NOT NULL
+  ) THEN 'failed' ELSE profile_picture_state END,
+  banner_picture_state = CASE WHEN banner_picture_state = 'pending' AND NOT EXISTS (
+    SELECT 1 FROM uploads WHERE uploads.page_id = pages.id AND uploads.kind = 'banner' AND uploads.processing_since IS NOT NULL
+  ) THEN 'failed' ELSE banner_picture_state END
+WHERE profile_picture_state = 'pending' OR banner_picture_state = 'pending';